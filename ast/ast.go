@@ -8,7 +8,6 @@ import (
 	"go/ast"
 	"go/token"
 	"io"
-	"math/rand"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -16,7 +15,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/icrowley/fake"
 	"github.com/influx6/gobuild/build"
 	"github.com/influx6/moz/gen"
 )
@@ -32,10 +30,10 @@ var (
 	goSrcPath = filepath.Join(goPath, "src")
 
 	timeLayout = "2006-01-02T15:04:05Z07:00"
-	spaces     = regexp.MustCompile(`/s+`)
-	itag       = regexp.MustCompile(`((\w+):"(\w+|[\w,?\s+\w]+)")`)
 	annotation = regexp.MustCompile("@(\\w+(:\\w+)?)(\\([.\\s\\S]+\\))?")
 
+	// ASTTemplatFuncs defines template functions for working with ast declarations, producing values
+	// from the DefaultValueGenerator. Use ValueGenerator.TemplateFuncs for reproducible values.
 	ASTTemplatFuncs = map[string]interface{}{
 		"getTag":            GetTag,
 		"fieldFor":          FieldFor,
@@ -56,6 +54,8 @@ var (
 		"randomFieldValue":  RandomFieldValue,
 		"defaultType":       DefaultTypeValueString,
 		"defaultFieldValue": DefaultFieldValue,
+		"defaultLiteral":    DefaultFieldLiteral,
+		"randomLiteral":     RandomFieldLiteral,
 	}
 
 	naturalIdents = map[string]bool{
@@ -329,6 +329,7 @@ type PackageDeclaration struct {
 	Interfaces       []InterfaceDeclaration
 	Functions        []FuncDeclaration
 	Variables        []VariableDeclaration
+	ConstGroups      []ConstGroupDeclaration
 	ObjectFunc       map[*ast.Object][]FuncDeclaration
 	importedloaded   bool
 }
@@ -363,8 +364,13 @@ func (pkg PackageDeclaration) ImportedPackageFor(packageName string) (Package, b
 		}
 	}
 
-	pkgItem, ok := pkg.ImportedPackages[pkgPath.Path]
-	return pkgItem, ok
+	if pkgItem, ok := pkg.ImportedPackages[pkgPath.Path]; ok {
+		return pkgItem, true
+	}
+
+	// Packages not loaded with the declaration (e.g standard library packages) are loaded on demand.
+	pkgItem, err := DefaultImportResolver.Package(pkgPath.Path, filepath.Dir(pkg.FilePath))
+	return pkgItem, err == nil
 }
 
 // HasAnnotation returns true/false if giving PackageDeclaration has annotation at package level.
@@ -413,17 +419,7 @@ func (pkg PackageDeclaration) AnnotationsFor(typeName string) []AnnotationDeclar
 
 // FunctionsForName returns a slice of FuncDeclaration for the giving name.
 func (pkg PackageDeclaration) FunctionsForName(objName string) []FuncDeclaration {
-	var funcs []FuncDeclaration
-
-	for obj, list := range pkg.ObjectFunc {
-		if obj.Name != objName {
-			continue
-		}
-
-		funcs = append(funcs, list...)
-	}
-
-	return funcs
+	return pkg.methodsFor(objName)
 }
 
 // ImportFor returns the ImportDeclaration associated with the giving handle.
@@ -491,15 +487,10 @@ func (pkg PackageDeclaration) FunctionsFor(obj *ast.Object) []FuncDeclaration {
 }
 
 // MethodFor returns associated FuncDeclaration with has struct declaration has receiver.
+// Use Package.MethodsFor or Package.MethodSet for methods declared across files.
 func (pkg PackageDeclaration) MethodFor(structName string) ([]FuncDeclaration, bool) {
-	for obj, set := range pkg.ObjectFunc {
-		if obj.Name != structName {
-			continue
-		}
-		return set, true
-	}
-
-	return nil, false
+	methods := pkg.methodsFor(structName)
+	return methods, len(methods) != 0
 }
 
 //===========================================================================================================
@@ -823,9 +814,12 @@ type ArgType struct {
 	ArrayType       *ast.ArrayType
 	MapType         *ast.MapType
 	ChanType        *ast.ChanType
+	FuncType        *ast.FuncType
+	Ellipsis        *ast.Ellipsis
 	PointerType     *ast.StarExpr
 	IdentType       *ast.Ident
 	Tags            []TagDeclaration
+	Expr            ast.Expr
 }
 
 // FunctionDefinition defines a type to represent the function/method declarations of an
@@ -1128,49 +1122,91 @@ type FieldDeclaration struct {
 	Struct        *ast.StructType
 	Tags          []TagDeclaration
 	Arg           ArgType
+	RawTag        string
+	Declr         *PackageDeclaration
+
+	// Index and Path are the index sequence and field names leading to the field from the
+	// struct it was retrieved for, as reflect.StructField.Index, where promoted fields have
+	// a Depth above 0 and the embedded fields they are promoted through in Embeddings.
+	Index      []int
+	Path       []string
+	Depth      int
+	Promoted   bool
+	Promotes   bool
+	Embeddings []FieldDeclaration
 }
 
 // GetFields returns all fields associated with the giving struct but skips
 func GetFields(str StructDeclaration, pkg *PackageDeclaration) []FieldDeclaration {
 	var fields []FieldDeclaration
 
-	var counter int
+	var counter, index int
 	for _, item := range str.Struct.Fields.List {
 		counter++
+
+		names := item.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{nil}
+		}
+
+		index += len(names)
+
 		arg, err := GetArgTypeFromField(counter, "var", pkg.File, item, pkg)
 		if err != nil {
 			continue
 		}
 
-		var field FieldDeclaration
-		field.Arg = arg
-		field.Type = arg.TypeObject
-		field.Spec = arg.Spec
-		field.Struct = arg.StructObject
-		field.Field = item
-		field.FieldName = arg.Name
-		field.FieldTypeName = arg.Type
+		// Fields declared together (e.g A, B int) each become a field of their own.
+		for position, ident := range names {
+			arg := arg
+			if ident != nil {
+				arg.Name = ident.Name
+				arg.NameObject = ident.Obj
+			}
 
-		if len(item.Names) == 0 {
-			field.Exported = true
-			field.Embedded = true
-		}
+			var field FieldDeclaration
+			field.Arg = arg
+			field.Type = arg.TypeObject
+			field.Spec = arg.Spec
+			field.Struct = arg.StructObject
+			field.Field = item
+			field.RawTag = rawTag(item)
+			field.Declr = pkg
+			field.FieldName = arg.Name
+			field.FieldTypeName = arg.Type
+			field.Index = []int{index - len(names) + position}
+
+			if arg.Name != strings.ToLower(arg.Name) {
+				field.Exported = true
+			}
 
-		if arg.Name != strings.ToLower(arg.Name) {
-			field.Exported = true
-		}
+			// Embedded fields are named after their type, as Go names them.
+			if ident == nil {
+				field.Embedded = true
+				field.FieldName = embeddedName(item.Type)
+				field.Exported = ast.IsExported(field.FieldName)
+			}
 
-		for _, tag := range arg.Tags {
-			tag.Field = field
-			field.Tags = append(field.Tags, tag)
-		}
+			field.Path = []string{field.FieldName}
+
+			for _, tag := range arg.Tags {
+				tag.Field = field
+				field.Tags = append(field.Tags, tag)
+			}
 
-		fields = append(fields, field)
+			fields = append(fields, field)
+		}
 	}
 
 	return fields
 }
 
+// DefaultTypeValueJSON returns the default value of a giving typeName as
+// a valid JSON value.
+func DefaultTypeValueJSON(typeName string) string {
+	return DefaultValueGenerator.DefaultTypeValueJSON(typeName)
+}
+
 // GetTag returns the giving tag associated with the name if it exists.
 func (f FieldDeclaration) GetTag(tagName string) (TagDeclaration, error) {
 	for _, tag := range f.Tags {
@@ -1184,11 +1220,25 @@ func (f FieldDeclaration) GetTag(tagName string) (TagDeclaration, error) {
 
 // TagDeclaration defines a type which represents a giving tag declaration for a provided type.
 type TagDeclaration struct {
-	Name  string
-	Value string
-	Metas []string
-	Base  string
-	Field FieldDeclaration
+	Name    string
+	Value   string
+	Metas   []string
+	Raw     string
+	Options []TagOption
+	Base    string
+	Field   FieldDeclaration
+}
+
+// Option returns the value of the option with the giving key (e.g min for min=3) and
+// true/false if the tag has it.
+func (t TagDeclaration) Option(key string) (string, bool) {
+	for _, option := range t.Options {
+		if option.Key == key {
+			return option.Value, true
+		}
+	}
+
+	return "", false
 }
 
 // Has returns true/false if the tag.Metas has the given value in the list.
@@ -1249,26 +1299,18 @@ func GetIdentName(field *ast.Field) (*ast.Ident, error) {
 // GetArgTypeFromField returns a ArgType that writes out the representation of the giving variable name or decleration ast.Field
 // associated with the giving package. It returns an error if it does not know the type.
 func GetArgTypeFromField(retCounter int, varPrefix string, targetFile string, result *ast.Field, pkg *PackageDeclaration) (ArgType, error) {
-	var tags []TagDeclaration
-
-	if result.Tag != nil {
-		tagList := strings.Split(spaces.ReplaceAllString(result.Tag.Value, " "), " ")
-		for _, tag := range tagList {
-			if !itag.MatchString(tag) {
-				continue
-			}
+	arg, err := getArgTypeFromField(retCounter, varPrefix, targetFile, result, pkg)
+	if err != nil {
+		return arg, err
+	}
 
-			res := itag.FindStringSubmatch(tag)
-			resValue := strings.Split(res[3], ",")
+	arg.Expr = result.Type
+	return arg, nil
+}
 
-			tags = append(tags, TagDeclaration{
-				Base:  res[0],
-				Name:  res[2],
-				Value: resValue[0],
-				Metas: resValue[1:],
-			})
-		}
-	}
+func getArgTypeFromField(retCounter int, varPrefix string, targetFile string, result *ast.Field, pkg *PackageDeclaration) (ArgType, error) {
+	// Malformed tags keep the pairs before the malformed part, as reflect.StructTag.Lookup finds them.
+	tags, _ := ParseStructTag(rawTag(result))
 
 	resPkg, defaultresType := getPackageFromItem(result.Type, filepath.Base(pkg.Package))
 
@@ -1609,6 +1651,7 @@ func GetArgTypeFromField(retCounter int, varPrefix string, targetFile string, re
 		var arg ArgType
 		arg.Name = name
 		arg.Tags = tags
+		arg.ChanType = iobj
 		arg.Type = getName(iobj.Value)
 		arg.ExType = getNameAsFromOuter(iobj, filepath.Base(pkg.Package))
 
@@ -1658,6 +1701,41 @@ func GetArgTypeFromField(retCounter int, varPrefix string, targetFile string, re
 		}
 
 		return arg, nil
+
+	case *ast.FuncType:
+
+		var name string
+		resName, err := GetIdentName(result)
+		switch err != nil {
+		case true:
+			name = fmt.Sprintf("%s%d", varPrefix, retCounter)
+		case false:
+			name = resName.Name
+		}
+
+		var arg ArgType
+		arg.Name = name
+		arg.Tags = tags
+		arg.FuncType = iobj
+		arg.Type = getName(iobj)
+		arg.ExType = getNameAsFromOuter(iobj, filepath.Base(pkg.Package))
+
+		return arg, nil
+
+	case *ast.Ellipsis:
+
+		// Variadic parameters are described by their element type, with the Ellipsis kept
+		// so the parameter can be written back as such.
+		elem, err := getArgTypeFromField(retCounter, varPrefix, targetFile, &ast.Field{Names: result.Names, Type: iobj.Elt}, pkg)
+		if err != nil {
+			return ArgType{}, err
+		}
+
+		elem.Ellipsis = iobj
+		elem.Type = getName(iobj)
+		elem.ExType = getNameAsFromOuter(iobj, filepath.Base(pkg.Package))
+
+		return elem, nil
 	}
 
 	return ArgType{}, errors.New("Unknown Field type, only variable type declaration wanted")
@@ -1908,6 +1986,12 @@ func getNameAsFromOuter(item interface{}, basePkg string) string {
 		return fmt.Sprintf("[]%s", getNameAsFromOuter(di.Elt, basePkg))
 	case *ast.ChanType:
 		return fmt.Sprintf("chan %s", getNameAsFromOuter(di.Value, basePkg))
+	case *ast.Ellipsis:
+		return "..." + getNameAsFromOuter(di.Elt, basePkg)
+	case *ast.ParenExpr:
+		return "(" + getNameAsFromOuter(di.X, basePkg) + ")"
+	case *ast.FuncType:
+		return exprString(di)
 	default:
 		return ""
 	}
@@ -1949,6 +2033,12 @@ func getName(item interface{}) string {
 		return fmt.Sprintf("[]%s", getName(di.Elt))
 	case *ast.ChanType:
 		return fmt.Sprintf("chan %s", getName(di.Value))
+	case *ast.Ellipsis:
+		return "..." + getName(di.Elt)
+	case *ast.ParenExpr:
+		return "(" + getName(di.X) + ")"
+	case *ast.FuncType:
+		return exprString(di)
 	default:
 		return ""
 	}
@@ -2081,41 +2171,18 @@ func FieldNameFor(item StructDeclaration, tag string, tagFieldName string) strin
 // AssignDefaultValue will get the fieldName for a giving tag and tagVal and return	a string of giving
 // variable name with fieldName equal to default value.
 func AssignDefaultValue(item StructDeclaration, tag string, tagVal string, varName string) (string, error) {
-	fieldName, defaultVal, err := DefaultFieldValueFor(item, tag, tagVal)
-	if err != nil {
-		return "", err
-	}
-
-	return fmt.Sprintf("%s.%s = %s", varName, fieldName, defaultVal), nil
+	return DefaultValueGenerator.AssignDefaultValue(item, tag, tagVal, varName)
 }
 
 // DefaultFieldValueFor defines a function to return a field default value.
 func DefaultFieldValueFor(item StructDeclaration, tag string, tagVal string) (string, string, error) {
-	fields := Fields(GetFields(item, item.Declr))
-
-	wTags := fields.TagFor(tag)
-
-	// Collect key field names from embedded first
-	for _, tag := range wTags {
-		if tag.Value != tagVal {
-			continue
-		}
-
-		return tag.Field.FieldName, RandomFieldValue(tag.Field), nil
-	}
-
-	return "", "", fmt.Errorf("Field for tag value %q not found", tagVal)
+	return DefaultValueGenerator.DefaultFieldValueFor(item, tag, tagVal)
 }
 
 // RandomFieldAssign generates a random Field of a giving struct and returns a variable assignment
 // declaration with the types default value.
 func RandomFieldAssign(item StructDeclaration, varName string, tag string, exceptions ...string) (string, error) {
-	randomFieldVal, _, err := RandomFieldWithExcept(item, tag, exceptions...)
-	if err != nil {
-		return "", err
-	}
-
-	return AssignDefaultValue(item, tag, randomFieldVal, varName)
+	return DefaultValueGenerator.RandomFieldAssign(item, varName, tag, exceptions...)
 }
 
 // RandomFieldWithExcept defines a function to return a random field name which is not
@@ -2307,141 +2374,43 @@ func MapOutFieldsToMap(item StructDeclaration, rootName, tagName, fallback strin
 // MapOutFieldsToJSON returns the giving map values containing string for the giving
 // output.
 func MapOutFieldsToJSON(item StructDeclaration, tagName, fallback string) (string, error) {
-	document, err := MapOutFieldsToJSONWriter(item, tagName, fallback)
-	if err != nil {
-		return "", err
-	}
-
-	var doc bytes.Buffer
-
-	if _, err := document.WriteTo(&doc); err != nil && err != io.EOF {
-		return "", err
-	}
-
-	return doc.String(), nil
+	return DefaultValueGenerator.MapOutFieldsToJSON(item, tagName, fallback)
 }
 
 // MapOutTypeToJSON returns the giving map values containing string for the giving
 // output.
 func MapOutTypeToJSON(item TypeDeclaration) (io.WriterTo, error) {
-	if item.Declr == nil {
-		return bytes.NewBuffer(nil), errors.New("TypeDeclaration has no PackageDeclaration field")
-	}
-
-	if item.TypeInfo == nil {
-		valueJSON := DefaultTypeValueString(strings.ToLower(item.Type.Name))
-		if valueJSON == "nil" {
-			valueJSON = "null"
-		}
-
-		return bytes.NewBufferString(valueJSON), nil
-	}
-
-	valueJSON := DefaultTypeValueString(strings.ToLower(item.TypeInfo.ExType))
-	if valueJSON == "nil" {
-		valueJSON = "null"
-	}
-
-	return bytes.NewBufferString(valueJSON), nil
+	return DefaultValueGenerator.MapOutTypeToJSON(item)
 }
 
 // MapOutFieldsWithRandomValuesToJSON returns the giving map values containing string for the giving
 // output.
 func MapOutFieldsWithRandomValuesToJSON(item StructDeclaration, tagName, fallback string) (string, error) {
-	document, err := MapOutFieldsToJSONWriterWithRandomValues(item, tagName, fallback)
-	if err != nil {
-		return "", err
-	}
-
-	var doc bytes.Buffer
-
-	if _, err := document.WriteTo(&doc); err != nil && err != io.EOF {
-		return "", err
-	}
-
-	return doc.String(), nil
+	return DefaultValueGenerator.MapOutFieldsWithRandomValuesToJSON(item, tagName, fallback)
 }
 
 // MapOutFieldsToJSONWriter returns the giving map values containing string for the giving
 // output.
 func MapOutFieldsToJSONWriter(item StructDeclaration, tagName, fallback string) (io.WriterTo, error) {
-	if item.Declr == nil {
-		return bytes.NewBuffer(nil), errors.New("StructDeclaration has no PackageDeclaration field")
-	}
-
-	fields := Fields(GetFields(item, item.Declr))
-
-	wTags := fields.TagFor(tagName)
-	if len(wTags) == 0 {
-		wTags = fields.TagFor(fallback)
-
-		if len(wTags) == 0 {
-			return nil, fmt.Errorf("No tags match for %q and %q fallback for struct %q", tagName, fallback, item.Object.Name)
-		}
-	}
-
-	documents := make(map[string]io.WriterTo)
-
-	// Collect key field names from embedded first
-	for _, tag := range wTags {
-		if tag.Value == "-" {
-			continue
-		}
-
-		if tag.Field.Type != nil {
-			embededType, embedStruct, err := GetStructSpec(tag.Field.Type.Decl)
-			if err != nil {
-				return nil, err
-			}
-
-			document, err := MapOutFieldsToJSONWriter(StructDeclaration{
-				Object: embededType,
-				Struct: embedStruct,
-				Declr:  item.Declr,
-			}, tagName, fallback)
-
-			if err != nil {
-				return nil, err
-			}
-
-			documents[tag.Value] = document
-			continue
-		}
-
-		valueJSON := DefaultTypeValueString(strings.ToLower(tag.Field.FieldTypeName))
-		documents[tag.Value] = gen.Text(valueJSON)
-	}
-
-	return gen.JSONDocument(documents), nil
+	return DefaultValueGenerator.MapOutFieldsToJSONWriter(item, tagName, fallback)
 }
 
 // MapOutTypeToJSONWriterWithRandomValues returns the giving map values containing string for the giving
 // output.
 func MapOutTypeToJSONWriterWithRandomValues(item TypeDeclaration) (io.WriterTo, error) {
-	if item.Declr == nil {
-		return bytes.NewBuffer(nil), errors.New("TypeDeclaration has no PackageDeclaration field")
-	}
-
-	if item.TypeInfo == nil {
-		valueJSON := RandomDataTypeValueJSON(item.Type.Name, item.Object.Name.Name)
-		if valueJSON == "nil" {
-			valueJSON = "null"
-		}
-
-		return bytes.NewBufferString(valueJSON), nil
-	}
-
-	valueJSON := RandomDataTypeValueJSON(item.TypeInfo.ExType, item.Object.Name.Name)
-	if valueJSON == "nil" {
-		valueJSON = "null"
-	}
-
-	return bytes.NewBufferString(valueJSON), nil
+	return DefaultValueGenerator.MapOutTypeToJSONWriterWithRandomValues(item)
 }
 
 // MapOutFieldsToJSONWriterWithRandomValues returns the giving map values containing string for the giving
 // output.
 func MapOutFieldsToJSONWriterWithRandomValues(item StructDeclaration, tagName, fallback string) (io.WriterTo, error) {
+	return DefaultValueGenerator.MapOutFieldsToJSONWriterWithRandomValues(item, tagName, fallback)
+}
+
+// mapOutFieldsToJSONDocument returns a JSON document keyed by the tag values of the struct fields,
+// where fields of struct types are mapped as nested documents and all others use the JSON value
+// returned by the provided function.
+func mapOutFieldsToJSONDocument(item StructDeclaration, tagName, fallback string, valueFor func(FieldDeclaration) string) (io.WriterTo, error) {
 	if item.Declr == nil {
 		return bytes.NewBuffer(nil), errors.New("StructDeclaration has no PackageDeclaration field")
 	}
@@ -2459,37 +2428,29 @@ func MapOutFieldsToJSONWriterWithRandomValues(item StructDeclaration, tagName, f
 
 	documents := make(map[string]io.WriterTo)
 
-	// Collect key field names from embedded first
 	for _, tag := range wTags {
 		if tag.Value == "-" {
 			continue
 		}
 
 		if tag.Field.Type != nil {
-			embededType, embedStruct, err := GetStructSpec(tag.Field.Type.Decl)
-			if err != nil {
-				return nil, err
-			}
-
-			document, err := MapOutFieldsToJSONWriter(StructDeclaration{
-				Object: embededType,
-				Struct: embedStruct,
-				Declr:  item.Declr,
-			}, tagName, fallback)
+			if embededType, embedStruct, err := GetStructSpec(tag.Field.Type.Decl); err == nil {
+				document, err := mapOutFieldsToJSONDocument(StructDeclaration{
+					Object: embededType,
+					Struct: embedStruct,
+					Declr:  item.Declr,
+				}, tagName, fallback, valueFor)
+
+				if err != nil {
+					return nil, err
+				}
 
-			if err != nil {
-				return nil, err
+				documents[tag.Value] = document
+				continue
 			}
-
-			documents[tag.Value] = document
-			continue
 		}
 
-		valueJSON := RandomDataTypeValueJSON(tag.Field.FieldTypeName, tag.Field.FieldName)
-		if valueJSON == "nil" {
-			valueJSON = "null"
-		}
-		documents[tag.Value] = gen.Text(valueJSON)
+		documents[tag.Value] = gen.Text(valueFor(tag.Field))
 	}
 
 	return gen.JSONDocument(documents), nil
@@ -2598,242 +2559,48 @@ func MapOutFieldsValues(item StructDeclaration, onlyExported bool, name *gen.Nam
 
 // RandomFieldValue returns the default value for a giving field.
 func RandomFieldValue(fld FieldDeclaration) string {
-	return RandomDataTypeValueWithName(fld.FieldTypeName, fld.FieldName)
+	return DefaultValueGenerator.RandomFieldValue(fld)
+}
+
+// DefaultFieldLiteral returns the Go source of the default value for a giving field,
+// built from the structure of its type.
+func DefaultFieldLiteral(fld FieldDeclaration) string {
+	return DefaultValueGenerator.DefaultFieldLiteral(fld)
+}
+
+// RandomFieldLiteral returns the Go source of a random value for a giving field,
+// built from the structure of its type.
+func RandomFieldLiteral(fld FieldDeclaration) string {
+	return DefaultValueGenerator.RandomFieldLiteral(fld)
 }
 
 // DefaultFieldValue returns the default value for a giving field.
 func DefaultFieldValue(fld FieldDeclaration) string {
-	return DefaultTypeValueString(fld.FieldTypeName)
+	return DefaultValueGenerator.DefaultFieldValue(fld)
 }
 
 // RandomDataTypeValueJSON returns the default value string of a giving
 // typeName.
 func RandomDataTypeValueJSON(typeName string, varName string) string {
-	switch typeName {
-	case "time.Time", "*time.Time", "Time", "time.time":
-		return strconv.Quote(time.Now().Format(timeLayout))
-	case "uint", "uint32", "uint64":
-		return fmt.Sprintf("%d", rand.Uint64())
-	case "bool":
-		return fmt.Sprintf("%t", rand.Int63n(1) == 0)
-	case "string":
-		switch strings.ToLower(varName) {
-		case "username", "user_name", "login_name":
-			return fmt.Sprintf("%q", fake.UserName())
-		case "user-agent", "useragent":
-			return fmt.Sprintf("%q", fake.UserAgent())
-		case "domain", "url":
-			return fmt.Sprintf("%q", fake.DomainName())
-		case "zip", "zip_code", "zip-code":
-			return fmt.Sprintf("%q", fake.Zip())
-		case "title", "user_title":
-			return fmt.Sprintf("%q", fake.Title())
-		case "day":
-			return fmt.Sprintf("%q", fake.WeekDay())
-		case "week":
-			return fmt.Sprintf("%d Week", fake.WeekdayNum())
-		case "year":
-			return fmt.Sprintf("%d", fake.Year(1998, 10000))
-		case "date", "date_time", "time":
-			return strconv.Quote(time.Now().Format(timeLayout))
-		case "location", "location_address", "location_addr":
-			return fmt.Sprintf("%q", fake.Street())
-		case "company", "company_name", "companyname":
-			return fmt.Sprintf("%q", fake.Company())
-		case "subject", "subject_name", "subjectname":
-			return fmt.Sprintf("%q", fake.EmailSubject())
-		case "email", "email_address", "emailaddress":
-			return fmt.Sprintf("%q", fake.EmailAddress())
-		case "addr", "address", "streetAddress", "street_address", "main_address", "mainaddress", "streetaddress":
-			return fmt.Sprintf("%q", fake.StreetAddress())
-		case "companyaddress", "company_address":
-			return fmt.Sprintf("%q", fake.StreetAddress())
-		case "first_name", "firstname":
-			return fmt.Sprintf("%q", fake.FirstName())
-		case "last_name", "lastname":
-			return fmt.Sprintf("%q", fake.LastName())
-		case "name", "fullname", "full_name":
-			return fmt.Sprintf("%q", fake.FullName())
-		case "public_id", "publicid", "private_id", "privateid", "user_id", "tenant_user_id", "tenant_id", "user_tenant_id":
-			return fmt.Sprintf("%q", fake.CharactersN(30))
-		case "creditcardnum", "credit_card_number", "credit_card_num":
-			return fmt.Sprintf("%q", fake.CreditCardNum(fake.CreditCardType()))
-		case "creditcard", "credit_card":
-			return fmt.Sprintf("%q", fake.CreditCardNum(fake.CreditCardType()))
-		default:
-			return fmt.Sprintf("%q", fake.CharactersN(20))
-		}
-	case "rune":
-		return fmt.Sprintf("'%x'", fake.CharactersN(1))
-	case "byte":
-		return fmt.Sprintf("'%x'", fake.CharactersN(1))
-	case "float32", "float64":
-		return fmt.Sprintf("%.4f", rand.Float64())
-	case "int", "int32", "int64":
-		switch varName {
-		case "week":
-			return fmt.Sprintf("%d", fake.WeekdayNum())
-		case "day":
-			return fmt.Sprintf("%d", fake.Day())
-		case "year":
-			return fmt.Sprintf("%d", fake.Year(1998, 10000))
-		default:
-			return fmt.Sprintf("%d", rand.Int63n(20))
-		}
-	default:
-		return "null"
-	}
+	return DefaultValueGenerator.RandomDataTypeValueJSON(typeName, varName)
 }
 
 // RandomDataTypeValueWithName returns the default value string of a giving
 // typeName.
 func RandomDataTypeValueWithName(typeName string, varName string) string {
-	switch typeName {
-	case "time.Time", "*time.Time", "Time", "time.time":
-		return strconv.Quote(time.Now().Format(timeLayout))
-	case "uint", "uint32", "uint64":
-		return fmt.Sprintf("%d", rand.Uint64())
-	case "bool":
-		return fmt.Sprintf("%t", rand.Int63n(1) == 0)
-	case "string":
-		switch strings.ToLower(varName) {
-		case "username", "user_name", "login_name":
-			return fmt.Sprintf("%q", fake.UserName())
-		case "user-agent", "useragent":
-			return fmt.Sprintf("%q", fake.UserAgent())
-		case "domain", "url":
-			return fmt.Sprintf("%q", fake.DomainName())
-		case "zip", "zip_code", "zip-code":
-			return fmt.Sprintf("%q", fake.Zip())
-		case "title", "user_title":
-			return fmt.Sprintf("%q", fake.Title())
-		case "day":
-			return fmt.Sprintf("%q", fake.WeekDay())
-		case "week":
-			return fmt.Sprintf("%d Week", fake.WeekdayNum())
-		case "year":
-			return fmt.Sprintf("%d", fake.Year(1998, 10000))
-		case "date", "date_time", "time":
-			return strconv.Quote(time.Now().Format(timeLayout))
-		case "location", "location_address", "location_addr":
-			return fmt.Sprintf("%q", fake.Street())
-		case "company", "company_name", "companyname":
-			return fmt.Sprintf("%q", fake.Company())
-		case "subject", "subject_name", "subjectname":
-			return fmt.Sprintf("%q", fake.EmailSubject())
-		case "email", "email_address", "emailaddress":
-			return fmt.Sprintf("%q", fake.EmailAddress())
-		case "addr", "address", "streetAddress", "street_address", "main_address", "mainaddress", "streetaddress":
-			return fmt.Sprintf("%q", fake.StreetAddress())
-		case "companyaddress", "company_address":
-			return fmt.Sprintf("%q", fake.StreetAddress())
-		case "first_name", "firstname":
-			return fmt.Sprintf("%q", fake.FirstName())
-		case "last_name", "lastname":
-			return fmt.Sprintf("%q", fake.LastName())
-		case "name", "fullname", "full_name":
-			return fmt.Sprintf("%q", fake.FullName())
-		case "public_id", "publicid", "private_id", "privateid", "user_id", "tenant_user_id", "tenant_id", "user_tenant_id":
-			return fmt.Sprintf("%q", fake.CharactersN(30))
-		case "creditcardnum", "credit_card_number", "credit_card_num":
-			return fmt.Sprintf("%q", fake.CreditCardNum(fake.CreditCardType()))
-		case "creditcard", "credit_card":
-			return fmt.Sprintf("%q", fake.CreditCardNum(fake.CreditCardType()))
-		default:
-			return fmt.Sprintf("%q", fake.CharactersN(20))
-		}
-	case "rune":
-		return fmt.Sprintf("'%x'", fake.CharactersN(1))
-	case "byte":
-		return fmt.Sprintf("'%x'", fake.CharactersN(1))
-	case "float32", "float64":
-		return fmt.Sprintf("%.4f", rand.Float64())
-	case "int", "int32", "int64":
-		switch varName {
-		case "week":
-			return fmt.Sprintf("%d", fake.WeekdayNum())
-		case "day":
-			return fmt.Sprintf("%d", fake.Day())
-		case "year":
-			return fmt.Sprintf("%d", fake.Year(1998, 10000))
-		default:
-			return fmt.Sprintf("%d", rand.Int63n(20))
-		}
-	default:
-		return DefaultTypeValueString(typeName)
-	}
+	return DefaultValueGenerator.RandomDataTypeValueWithName(typeName, varName)
 }
 
 // RandomDataTypeValue returns the default value string of a giving
 // typeName.
 func RandomDataTypeValue(typeName string) string {
-	switch typeName {
-	case "time.Time":
-		return time.Now().Format(timeLayout)
-	case "uint", "uint32", "uint64":
-		return fmt.Sprintf("%d", rand.Uint64())
-	case "bool":
-		return fmt.Sprintf("%t", rand.Int63n(1) == 0)
-	case "string":
-		return fmt.Sprintf("%q", fake.Character())
-	case "rune":
-		return fmt.Sprintf("'%x'", fake.CharactersN(1))
-	case "byte":
-		return fmt.Sprintf("'%x'", fake.CharactersN(1))
-	case "float32", "float64":
-		return fmt.Sprintf("%.4f", rand.Float64())
-	case "int", "int32", "int64":
-		return fmt.Sprintf("%d", rand.Int63())
-	default:
-		return DefaultTypeValueString(typeName)
-	}
+	return DefaultValueGenerator.RandomDataTypeValue(typeName)
 }
 
 // DefaultTypeValueString returns the default value string of a giving
 // typeName.
 func DefaultTypeValueString(typeName string) string {
-	switch typeName {
-	case "uint", "uint32", "uint64":
-		return "0"
-	case "bool":
-		return `false`
-	case "time.Time", "*time.Time", "Time", "time.time":
-		return strconv.Quote(time.Now().Format(timeLayout))
-	case "string":
-		return `""`
-	case "rune":
-		return `rune(0)`
-	case "[]uint":
-		return `[]uint{}`
-	case "[]uint64":
-		return `[]uint64{}`
-	case "[]uint32":
-		return `[]uint32{}`
-	case "[]int":
-		return `[]int{}`
-	case "[]int64":
-		return `[]int64{}`
-	case "[]int32":
-		return `[]int32{}`
-	case "[]bool":
-		return `[]bool{}`
-	case "[]string":
-		return `[]string{}`
-	case "[]byte":
-		return `[]byte{}`
-	case "byte":
-		return `byte(rune(0))`
-	case "float32", "float64":
-		return "0.0"
-	case "int", "int32", "int64":
-		return "0"
-	case "map[string]interface{}":
-		return "map[string]interface{}"
-	case "map[string]string":
-		return "map[string]string{}"
-	default:
-		return "nil"
-	}
+	return DefaultValueGenerator.DefaultTypeValueString(typeName)
 }
 
 // GetTag returns the giving tag associated with the name if it exists.
@@ -2880,6 +2647,12 @@ func DefaultGoTypeString(typeName string) string {
 		return "map[string]interface{}"
 	case "map[string]string":
 		return "map[string]string{}"
+	case "time.Duration":
+		return "time.Duration(0)"
+	}
+
+	if value, ok := DefaultValueGenerator.literals(nil, false).basic(typeName, ""); ok {
+		return value
 	}
 
 	return fmt.Sprintf("%s{}", typeName)
@@ -460,10 +460,10 @@ func parseFileToPackage(log metrics.Metrics, dir string, path string, pkgName st
 			if _, ok := packageDeclr.ImportedPackages[imported.Path]; !ok {
 				var importDir string
 
+				// Packages outside of GOPATH (e.g standard library) are loaded on demand by
+				// ImportedPackageFor through DefaultImportResolver.
 				if !imported.InternalPkg {
 					importDir = srcpath.FromSrcPath(imported.Path)
-				} else {
-					//importDir = srcpath.FromRootPath(imported.Path)
 				}
 
 				// Check if import path exists else skip.
@@ -510,6 +510,8 @@ func parseFileToPackage(log metrics.Metrics, dir string, path string, pkgName st
 			packageDeclr.Annotations = append(packageDeclr.Annotations, annotationRead...)
 		}
 
+		named := namedTypes(file)
+
 		// Collect and categorize annotations in types and their fields.
 	declrLoop:
 		for _, declr := range file.Decls {
@@ -596,16 +598,15 @@ func parseFileToPackage(log metrics.Metrics, dir string, path string, pkgName st
 					defFunc.FuncType = rdeclr.Recv
 
 					nameIdent := rdeclr.Recv.List[0]
-					var receiverNameType *ast.Ident
-
-					switch nmi := nameIdent.Type.(type) {
-					case *ast.Ident:
-						receiverNameType = nmi
-					case *ast.StarExpr:
-						receiverNameType = nmi.X.(*ast.Ident)
+					if nmi, ok := nameIdent.Type.(*ast.StarExpr); ok {
 						defFunc.RecieverPointer = nmi
 					}
 
+					receiverNameType := receiverIdent(nameIdent.Type)
+					if receiverNameType == nil {
+						continue declrLoop
+					}
+
 					defFunc.Reciever = receiverNameType.Obj
 					defFunc.RecieverIdent = receiverNameType
 					defFunc.RecieverName = receiverNameType.Name
@@ -667,6 +668,26 @@ func parseFileToPackage(log metrics.Metrics, dir string, path string, pkgName st
 					}
 				}
 
+				if rdeclr.Tok == token.CONST {
+					consts := constsOf(rdeclr, constScope(packageDeclr.ConstGroups), named)
+
+					packageDeclr.ConstGroups = append(packageDeclr.ConstGroups, ConstGroupDeclaration{
+						Consts:      consts,
+						Type:        sharedConstType(consts),
+						Annotations: annotations,
+						GenObj:      rdeclr,
+						Comments:    comment,
+						Declr:       &packageDeclr,
+						File:        packageDeclr.File,
+						Package:     packageDeclr.Package,
+						Path:        packageDeclr.Path,
+						FilePath:    packageDeclr.FilePath,
+						Position:    rdeclr.Pos(),
+						From:        beginPosition.Offset,
+						Length:      positionLength,
+					})
+				}
+
 				for _, spec := range rdeclr.Specs {
 					switch obj := spec.(type) {
 					case *ast.ValueSpec:
@@ -844,12 +865,40 @@ func parseFileToPackage(log metrics.Metrics, dir string, path string, pkgName st
 	return packageDeclr, nil
 }
 
+// receiverIdent returns the name of the type of a method receiver, which may be a pointer or an
+// instance of a generic type (e.g *List[T]).
+func receiverIdent(expr ast.Expr) *ast.Ident {
+	switch item := expr.(type) {
+	case *ast.Ident:
+		return item
+	case *ast.StarExpr:
+		return receiverIdent(item.X)
+	case *ast.ParenExpr:
+		return receiverIdent(item.X)
+	case *ast.IndexExpr:
+		return receiverIdent(item.X)
+	case *ast.IndexListExpr:
+		return receiverIdent(item.X)
+	}
+	return nil
+}
+
 func relativeToSrc(path string) (string, error) {
 	if _, err := os.Stat(path); err != nil {
 		return "", err
 	}
 
-	return filepath.Rel(goSrcPath, path)
+	rel, err := filepath.Rel(goSrcPath, path)
+	if err != nil || !strings.HasPrefix(rel, "..") {
+		return rel, err
+	}
+
+	// Standard library packages live within GOROOT.
+	if rootRel, err := filepath.Rel(filepath.Join(build.Default.GOROOT, "src"), path); err == nil && !strings.HasPrefix(rootRel, "..") {
+		return rootRel, nil
+	}
+
+	return rel, nil
 }
 
 //===========================================================================================================
@@ -935,6 +984,10 @@ func SimpleWriteDirective(toDir string, doFileOverwrite bool, item gen.WriteDire
 		}
 	}
 
+	if err := ApplyRewrites(item.Rewrites...); err != nil {
+		return err
+	}
+
 	if item.Writer == nil {
 		return nil
 	}
@@ -1027,6 +1080,11 @@ func WriteDirective(log metrics.Metrics, toDir string, doFileOverwrite bool, ite
 			},
 		}))
 
+	if err := ApplyRewrites(item.Rewrites...); err != nil {
+		log.Emit(metrics.Error(err), metrics.With("op", "rewrite"), metrics.With("Rewrites", len(item.Rewrites)))
+		return err
+	}
+
 	if item.Writer == nil {
 		log.Emit(metrics.Info("Resolved WriteDirective"), metrics.With("File", item.FileName), metrics.With("Overwrite", item.DontOverride), metrics.With("Dir", item.Dir))
 		return nil
@@ -1104,6 +1162,8 @@ func ParsePackage(toDir string, log metrics.Metrics, provider *AnnotationRegistr
 		return fmt.Errorf("Destination path is not within current GOPATH: %+q", err.Error())
 	}
 
+	var rewrites []gen.RewriteDirective
+
 	for _, pkg := range pkgDeclrs.Packages {
 		log.Emit(metrics.Info("ParsePackage: Parse PackageDeclaration"),
 			metrics.With("toDir", toDir), metrics.With("overwriter-file", doFileOverwrite),
@@ -1120,6 +1180,11 @@ func ParsePackage(toDir string, log metrics.Metrics, provider *AnnotationRegistr
 		log.Emit(metrics.Info("ParseSuccess"), metrics.With("From", pkg.FilePath), metrics.With("package", pkg.Package), metrics.With("Directives", len(wdrs)))
 
 		for _, wd := range wdrs {
+			// Rewrites are held back till all directives of the package are written, so edits
+			// from different annotations to the same file are applied against the same source.
+			rewrites = append(rewrites, wd.Rewrites...)
+			wd.Rewrites = nil
+
 			if err := WriteDirective(log, toDir, doFileOverwrite, wd.WriteDirective); err != nil {
 				log.Emit(metrics.Error(err), metrics.With("annotation", wd.Annotation),
 					metrics.With("dir", toDir),
@@ -1136,6 +1201,11 @@ func ParsePackage(toDir string, log metrics.Metrics, provider *AnnotationRegistr
 
 	}
 
+	if err := ApplyRewrites(rewrites...); err != nil {
+		log.Emit(metrics.Error(err), metrics.With("op", "rewrite"), metrics.With("package", pkgDeclrs.Path))
+		return err
+	}
+
 	return nil
 }
 
@@ -1155,6 +1225,8 @@ func SimplyParsePackage(toDir string, log metrics.Metrics, provider *AnnotationR
 		return fmt.Errorf("Destination path is not within current GOPATH: %+q", err.Error())
 	}
 
+	var rewrites []gen.RewriteDirective
+
 	for _, pkg := range pkgDeclrs.Packages {
 		log.Emit(metrics.Info("ParsePackage: Parse PackageDeclaration"),
 			metrics.With("toDir", toDir), metrics.With("overwriter-file", doFileOverwrite),
@@ -1171,6 +1243,11 @@ func SimplyParsePackage(toDir string, log metrics.Metrics, provider *AnnotationR
 		log.Emit(metrics.Info("ParseSuccess"), metrics.With("From", pkg.FilePath), metrics.With("package", pkg.Package), metrics.With("Directives", len(wdrs)))
 
 		for _, wd := range wdrs {
+			// Rewrites are held back till all directives of the package are written, so edits
+			// from different annotations to the same file are applied against the same source.
+			rewrites = append(rewrites, wd.Rewrites...)
+			wd.Rewrites = nil
+
 			if err := SimpleWriteDirective(toDir, doFileOverwrite, wd.WriteDirective); err != nil {
 				log.Emit(metrics.Error(err), metrics.With("annotation", wd.Annotation),
 					metrics.With("dir", toDir),
@@ -1187,6 +1264,11 @@ func SimplyParsePackage(toDir string, log metrics.Metrics, provider *AnnotationR
 
 	}
 
+	if err := ApplyRewrites(rewrites...); err != nil {
+		log.Emit(metrics.Error(err), metrics.With("op", "rewrite"), metrics.With("package", pkgDeclrs.Path))
+		return err
+	}
+
 	return nil
 }
 
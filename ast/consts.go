@@ -0,0 +1,435 @@
+package ast
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"strings"
+)
+
+// ConstDeclaration defines a single constant declared within a const group, along with the type
+// and value it is given by the group, where constants without a type or values repeat those of the
+// previous specification as Go does. Constants declared without a type take the type of the typed
+// constants or conversions their value is computed from (e.g Default = Red), and are otherwise
+// untyped with an empty Type.
+type ConstDeclaration struct {
+	Name        string
+	Type        string
+	TypeExpr    ast.Expr
+	Iota        int
+	Exported    bool
+	Doc         string
+	Comment     string
+	Value       constant.Value
+	Spec        *ast.ValueSpec
+	Annotations []AnnotationDeclaration
+}
+
+// Known returns true/false if the value of the constant could be computed from its declaration.
+func (c ConstDeclaration) Known() bool {
+	return c.Value != nil && c.Value.Kind() != constant.Unknown
+}
+
+// AnnotationsFor returns all annotations with the giving name declared in the doc or trailing
+// comment of the constant.
+func (c ConstDeclaration) AnnotationsFor(typeName string) []AnnotationDeclaration {
+	var found []AnnotationDeclaration
+
+	for _, item := range c.Annotations {
+		if item.Name != typeName {
+			continue
+		}
+
+		found = append(found, item)
+	}
+
+	return found
+}
+
+// ConstGroupDeclaration defines a type which holds the constants of a giving const declaration,
+// either a single constant or a parenthesized block of them. Type is set if all constants share
+// the same declared type.
+type ConstGroupDeclaration struct {
+	From        int
+	Length      int
+	Package     string
+	Path        string
+	FilePath    string
+	File        string
+	Comments    string
+	Type        string
+	Position    token.Pos
+	Consts      []ConstDeclaration
+	GenObj      *ast.GenDecl
+	Declr       *PackageDeclaration
+	Annotations []AnnotationDeclaration
+}
+
+// ConstantsFor returns all constants within the group declared with the named type.
+func (cg ConstGroupDeclaration) ConstantsFor(typeName string) []ConstDeclaration {
+	var found []ConstDeclaration
+
+	for _, item := range cg.Consts {
+		if item.Type != typeName {
+			continue
+		}
+
+		found = append(found, item)
+	}
+
+	return found
+}
+
+// ConstantsFor returns all constants declared with the named type within the file, in declaration order.
+func (pkg PackageDeclaration) ConstantsFor(typeName string) []ConstDeclaration {
+	var found []ConstDeclaration
+
+	for _, group := range pkg.ConstGroups {
+		found = append(found, group.ConstantsFor(typeName)...)
+	}
+
+	return found
+}
+
+// ConstantsFor returns all constants declared with the named type within the package, in declaration order.
+func (pkg Package) ConstantsFor(typeName string) []ConstDeclaration {
+	var found []ConstDeclaration
+
+	for _, declr := range pkg.Packages {
+		found = append(found, declr.ConstantsFor(typeName)...)
+	}
+
+	return found
+}
+
+//===========================================================================================================
+
+// constsOf returns the constants declared by the const declaration, where values are computed from
+// literals, iota, operators, conversions and the constants within scope, which receives each constant
+// in turn. Values which can not be computed, such as those calling functions other than conversions
+// or referring to constants of other files, are of kind constant.Unknown. The named types, as returned
+// by namedTypes, resolve the types of constants declared with named types.
+func constsOf(gen *ast.GenDecl, scope map[string]ConstDeclaration, named map[string]string) []ConstDeclaration {
+	var consts []ConstDeclaration
+
+	var typeExpr ast.Expr
+	var values []ast.Expr
+
+	for index, spec := range gen.Specs {
+		value, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+
+		if value.Type != nil || len(value.Values) != 0 {
+			typeExpr = value.Type
+			values = value.Values
+		}
+
+		var doc, comment string
+		if value.Doc != nil {
+			doc = value.Doc.Text()
+		}
+		if value.Comment != nil {
+			comment = value.Comment.Text()
+		}
+
+		annotations := ReadAnnotationsFromCommentry(strings.NewReader(doc + "\n" + comment + "\n"))
+
+		for position, name := range value.Names {
+			var typeName string
+			if typeExpr != nil {
+				typeName = exprString(typeExpr)
+			} else if position < len(values) {
+				typeName = constType(values[position], scope)
+			}
+
+			computed := constant.MakeUnknown()
+			if position < len(values) {
+				computed = constValue(values[position], index, typeName, named, scope)
+			}
+
+			item := ConstDeclaration{
+				Name:        name.Name,
+				Type:        typeName,
+				TypeExpr:    typeExpr,
+				Iota:        index,
+				Exported:    name.IsExported(),
+				Doc:         doc,
+				Comment:     strings.TrimSpace(comment),
+				Value:       computed,
+				Spec:        value,
+				Annotations: annotations,
+			}
+
+			if name.Name != "_" {
+				scope[name.Name] = item
+			}
+
+			consts = append(consts, item)
+		}
+	}
+
+	return consts
+}
+
+// sharedConstType returns the type all constants are declared with, or an empty string if
+// they differ or are untyped.
+func sharedConstType(consts []ConstDeclaration) string {
+	if len(consts) == 0 {
+		return ""
+	}
+
+	for _, item := range consts[1:] {
+		if item.Type != consts[0].Type {
+			return ""
+		}
+	}
+
+	return consts[0].Type
+}
+
+// constScope returns all constants within the groups, by name.
+func constScope(groups []ConstGroupDeclaration) map[string]ConstDeclaration {
+	scope := make(map[string]ConstDeclaration)
+
+	for _, group := range groups {
+		for _, item := range group.Consts {
+			if item.Name != "_" {
+				scope[item.Name] = item
+			}
+		}
+	}
+
+	return scope
+}
+
+// constValue returns the value of the constant expression for the giving iota, converted to the
+// type if it is, or is declared with, a predeclared numeric type. Expressions which go/constant
+// rejects, such as mismatched operands, yield an unknown value rather than a panic, as do complements
+// of types which can not be resolved, whose bit size is not known.
+func constValue(expr ast.Expr, iota int, typeName string, named map[string]string, scope map[string]ConstDeclaration) (value constant.Value) {
+	defer func() {
+		if recover() != nil {
+			value = constant.MakeUnknown()
+		}
+	}()
+
+	typeName, ok := underlyingType(typeName, named)
+	if !ok && hasComplement(expr) {
+		return constant.MakeUnknown()
+	}
+
+	value = evalConst(expr, iota, unsignedBits(typeName), scope)
+
+	switch {
+	case value.Kind() == constant.Unknown:
+	case strings.HasPrefix(typeName, "int") || strings.HasPrefix(typeName, "uint") || typeName == "byte" || typeName == "rune":
+		value = constant.ToInt(value)
+	case strings.HasPrefix(typeName, "float"):
+		value = constant.ToFloat(value)
+	}
+
+	return value
+}
+
+// evalConst returns the value of the constant expression, where prec defines the bit size used
+// for the complement of unsigned values.
+func evalConst(expr ast.Expr, iota int, prec uint, scope map[string]ConstDeclaration) constant.Value {
+	switch item := expr.(type) {
+	case *ast.BasicLit:
+		return constant.MakeFromLiteral(item.Value, item.Kind, 0)
+	case *ast.ParenExpr:
+		return evalConst(item.X, iota, prec, scope)
+	case *ast.Ident:
+		switch item.Name {
+		case "iota":
+			return constant.MakeInt64(int64(iota))
+		case "true":
+			return constant.MakeBool(true)
+		case "false":
+			return constant.MakeBool(false)
+		}
+
+		if named, ok := scope[item.Name]; ok && named.Value != nil {
+			return named.Value
+		}
+	case *ast.UnaryExpr:
+		x := evalConst(item.X, iota, prec, scope)
+		if x.Kind() == constant.Unknown {
+			return x
+		}
+
+		return constant.UnaryOp(item.Op, x, prec)
+	case *ast.BinaryExpr:
+		x := evalConst(item.X, iota, prec, scope)
+		y := evalConst(item.Y, iota, prec, scope)
+		if x.Kind() == constant.Unknown || y.Kind() == constant.Unknown {
+			return constant.MakeUnknown()
+		}
+
+		switch item.Op {
+		case token.SHL, token.SHR:
+			shift, ok := constant.Uint64Val(constant.ToInt(y))
+			if !ok {
+				return constant.MakeUnknown()
+			}
+			return constant.Shift(constant.ToInt(x), item.Op, uint(shift))
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return constant.MakeBool(constant.Compare(x, item.Op, y))
+		case token.QUO:
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				return constant.BinaryOp(x, token.QUO_ASSIGN, y)
+			}
+		}
+
+		return constant.BinaryOp(x, item.Op, y)
+	case *ast.CallExpr:
+		// Only conversions such as Weekday(1) or uint8(x) are constant calls we can evaluate, which
+		// leave the value as is.
+		if len(item.Args) != 1 {
+			break
+		}
+
+		if ident, ok := item.Fun.(*ast.Ident); ok && (ident.Name == "string" || isBuiltinCall(ident)) {
+			break
+		}
+
+		return evalConst(item.Args[0], iota, prec, scope)
+	}
+
+	return constant.MakeUnknown()
+}
+
+// constType returns the type of the constant expression given by the typed constants and
+// conversions within it, or an empty string if it is untyped.
+func constType(expr ast.Expr, scope map[string]ConstDeclaration) string {
+	switch item := expr.(type) {
+	case *ast.ParenExpr:
+		return constType(item.X, scope)
+	case *ast.Ident:
+		return scope[item.Name].Type
+	case *ast.UnaryExpr:
+		return constType(item.X, scope)
+	case *ast.BinaryExpr:
+		switch item.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return ""
+		case token.SHL, token.SHR:
+			return constType(item.X, scope)
+		}
+
+		if typeName := constType(item.X, scope); typeName != "" {
+			return typeName
+		}
+		return constType(item.Y, scope)
+	case *ast.CallExpr:
+		if len(item.Args) != 1 || isBuiltinCall(item.Fun) {
+			return ""
+		}
+		return exprString(item.Fun)
+	}
+
+	return ""
+}
+
+// isBuiltinCall returns true/false if the function is a builtin function which may be used within
+// constant expressions.
+func isBuiltinCall(fn ast.Expr) bool {
+	ident, ok := fn.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	switch ident.Name {
+	case "len", "cap", "real", "imag", "complex", "min", "max":
+		return true
+	}
+	return false
+}
+
+// namedTypes returns the names of the types declared within the file with another named type, such
+// as uint8 for `type Flag uint8`, by the name of the declared type.
+func namedTypes(file *ast.File) map[string]string {
+	named := make(map[string]string)
+
+	for _, declr := range file.Decls {
+		gen, ok := declr.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			if ident, ok := typeSpec.Type.(*ast.Ident); ok {
+				named[typeSpec.Name.Name] = ident.Name
+			}
+		}
+	}
+
+	return named
+}
+
+// underlyingType returns the predeclared type the type is, or is declared with through the named
+// types, and false if it resolves to none, as for types declared in other files or packages. Untyped
+// constants have an empty type name.
+func underlyingType(typeName string, named map[string]string) (string, bool) {
+	for hops := 0; hops <= len(named); hops++ {
+		if typeName == "" || predeclaredConstType(typeName) {
+			return typeName, true
+		}
+
+		next, ok := named[typeName]
+		if !ok {
+			break
+		}
+
+		typeName = next
+	}
+
+	return "", false
+}
+
+// predeclaredConstType returns true/false if the type name is a predeclared type constants may have.
+func predeclaredConstType(typeName string) bool {
+	switch typeName {
+	case "bool", "string", "byte", "rune", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128":
+		return true
+	}
+	return false
+}
+
+// hasComplement returns true/false if the expression takes the bitwise complement of a value.
+func hasComplement(expr ast.Expr) bool {
+	var found bool
+
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if unary, ok := node.(*ast.UnaryExpr); ok && unary.Op == token.XOR {
+			found = true
+		}
+		return !found
+	})
+
+	return found
+}
+
+// unsignedBits returns the bit size of the named unsigned integer type, or zero for any other type.
+func unsignedBits(typeName string) uint {
+	switch typeName {
+	case "uint8", "byte":
+		return 8
+	case "uint16":
+		return 16
+	case "uint32":
+		return 32
+	case "uint", "uint64", "uintptr":
+		return 64
+	}
+	return 0
+}
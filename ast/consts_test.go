@@ -0,0 +1,130 @@
+package ast_test
+
+import (
+	"go/constant"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+)
+
+var constSource = `package colors
+
+// Color defines a color.
+// @enum(case => kebab, trim => Color, ignorecase)
+type Color int
+
+const (
+	ColorRed Color = iota + 1
+	ColorDarkGreen
+	_
+	ColorBlue // @enum(name => azure)
+	ColorDefault = ColorRed
+	ColorHidden Color = 10 // @enum(-)
+)
+
+// Size defines a size in bytes.
+type Size uint64
+
+const (
+	_       = iota
+	KB Size = 1 << (10 * iota)
+	MB
+	GB
+)
+
+// Level defines a log level.
+// @enum(case => upper)
+type Level string
+
+const (
+	Debug Level = "debug"
+	Error Level = "err" + "or"
+)
+
+const (
+	Name, Version = "moz", 2
+	Ratio         = Version * 7 / 2
+	Half          = 7 / 2.0
+	Large         = MB > KB
+	Mask    uint8 = ^uint8(0) >> 4
+	Offset        = Size(3) * 2
+)
+
+// Flag defines a set of flags.
+type Flag uint8
+
+// Mode defines a set of flags of a file.
+type Mode Flag
+
+const (
+	All  Flag = ^Flag(0)
+	Low       = All >> 4
+	Each      = ^Mode(0)
+)
+`
+
+// TestConstGroups validates const groups are modeled with the types and values go/types computes.
+func TestConstGroups(t *testing.T) {
+	fx := newFixture(t, sourceFile{Name: "colors.go", Source: constSource})
+	pkgs, checked := fx.Packages, fx.Check()
+
+	groups := pkgs[0].Packages[0].ConstGroups
+	if len(groups) != 5 {
+		tests.Info("Groups: %d", len(groups))
+		tests.Failed("Should have successfully modeled all const groups")
+	}
+	tests.Passed("Should have successfully modeled all const groups")
+
+	if groups[1].Type != "" || groups[2].Type != "Level" {
+		tests.Failed("Should have successfully set shared type of const groups")
+	}
+	tests.Passed("Should have successfully set shared type of const groups")
+
+	for _, group := range groups {
+		for _, item := range group.Consts {
+			if item.Name == "_" {
+				continue
+			}
+
+			object, ok := checked.Scope().Lookup(item.Name).(*types.Const)
+			if !ok {
+				tests.Failed("Should have successfully found constant %q", item.Name)
+			}
+
+			expectedType := object.Type().String()
+			if basic, ok := object.Type().(*types.Basic); ok && basic.Info()&types.IsUntyped != 0 {
+				expectedType = ""
+			}
+
+			if "colors."+item.Type != expectedType && item.Type != expectedType {
+				tests.Info("Constant: %s", item.Name)
+				tests.Info("Type: %q", item.Type)
+				tests.Info("Expected: %q", expectedType)
+				tests.Failed("Should have successfully matched type of constant")
+			}
+
+			if !item.Known() || !constant.Compare(item.Value, token.EQL, object.Val()) {
+				tests.Info("Constant: %s", item.Name)
+				tests.Info("Value: %s", item.Value)
+				tests.Info("Expected: %s", object.Val())
+				tests.Failed("Should have successfully computed value of constant")
+			}
+		}
+	}
+	tests.Passed("Should have successfully matched types and values of constants")
+
+	var names []string
+	for _, item := range pkgs[0].ConstantsFor("Color") {
+		if item.Name != "_" {
+			names = append(names, item.Name)
+		}
+	}
+
+	if len(names) != 5 || names[0] != "ColorRed" || names[3] != "ColorDefault" {
+		tests.Info("Constants: %+q", names)
+		tests.Failed("Should have successfully found constants of type")
+	}
+	tests.Passed("Should have successfully found constants of type")
+}
@@ -0,0 +1,161 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"go/constant"
+	"go/token"
+	"strings"
+	"unicode"
+
+	"github.com/influx6/moz/gen"
+)
+
+// EnumAnnotation defines the annotation which marks a named type to have enum methods generated
+// from its constants by EnumAnnotationGenerator.
+const EnumAnnotation = "@enum"
+
+// EnumCases defines the case transforms which can be applied to the names of constants by the
+// "case" parameter of an @enum annotation.
+var EnumCases = map[string]func(string) string{
+	"snake":     snakeCase,
+	"kebab":     func(name string) string { return strings.Replace(snakeCase(name), "_", "-", -1) },
+	"screaming": func(name string) string { return strings.ToUpper(snakeCase(name)) },
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"camel":     camelCase,
+}
+
+// EnumFor returns the declaration of the enum methods of the named type, whose values are the
+// constants declared with the type anywhere within the package.
+//
+// The annotation accepts the following options:
+//
+//	@enum(case => snake)     names are transformed with one of the EnumCases
+//	@enum(trim => Color)     the prefix is removed from constant names before the transform
+//	@enum(ignorecase)        Parse<Type> matches names regardless of case
+//
+// A constant is given a custom name with an @enum(name => text) annotation in its trailing or
+// doc comment, or left out with @enum(-). Constants repeating the value of an earlier one are
+// aliases and are left out.
+func EnumFor(typ TypeDeclaration, annotation AnnotationDeclaration, pkg Package) (gen.EnumDeclr, error) {
+	if typ.Object == nil {
+		return gen.EnumDeclr{}, errors.New("TypeDeclaration has no type specification")
+	}
+
+	typeName := typ.Object.Name.Name
+	if typ.Object.Assign.IsValid() {
+		return gen.EnumDeclr{}, fmt.Errorf("Enum type %q can not be an alias", typeName)
+	}
+
+	transform := func(name string) string { return name }
+	if caseName := annotation.Param("case"); caseName != "" {
+		caseFn, ok := EnumCases[caseName]
+		if !ok {
+			return gen.EnumDeclr{}, fmt.Errorf("Enum type %q has unknown case %q", typeName, caseName)
+		}
+		transform = caseFn
+	}
+
+	consts := pkg.ConstantsFor(typeName)
+	if len(consts) == 0 && typ.Declr != nil {
+		consts = typ.Declr.ConstantsFor(typeName)
+	}
+
+	enum := gen.Enum(typeName, exprString(typ.Object.Type))
+	enum.IgnoreCase = annotation.HasArg("ignorecase")
+
+	var known []constant.Value
+	names := make(map[string]string)
+
+constLoop:
+	for _, item := range consts {
+		if item.Name == "_" {
+			continue
+		}
+
+		name := transform(strings.TrimPrefix(item.Name, annotation.Param("trim")))
+
+		for _, custom := range item.AnnotationsFor(EnumAnnotation) {
+			if custom.HasArg("-") {
+				continue constLoop
+			}
+
+			if value := custom.Param("name"); value != "" {
+				name = value
+			}
+		}
+
+		if item.Known() {
+			for _, value := range known {
+				if constant.Compare(value, token.EQL, item.Value) {
+					continue constLoop
+				}
+			}
+			known = append(known, item.Value)
+		}
+
+		key := name
+		if enum.IgnoreCase {
+			key = strings.ToLower(name)
+		}
+
+		if other, ok := names[key]; ok {
+			return gen.EnumDeclr{}, fmt.Errorf("Enum type %q has constants %q and %q named %q", typeName, other, item.Name, name)
+		}
+		names[key] = item.Name
+
+		enum.Values = append(enum.Values, gen.EnumValue(item.Name, name))
+	}
+
+	if len(enum.Values) == 0 {
+		return gen.EnumDeclr{}, fmt.Errorf("Enum type %q has no constants", typeName)
+	}
+
+	return enum, nil
+}
+
+// EnumAnnotationGenerator defines a TypeAnnotationGenerator for the @enum annotation, which writes
+// the methods declared by EnumFor into a "<type>_enum.go" file of the type's package.
+func EnumAnnotationGenerator(toDir string, an AnnotationDeclaration, typ TypeDeclaration, pkgDeclr PackageDeclaration, pkg Package) ([]gen.WriteDirective, error) {
+	enum, err := EnumFor(typ, an, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	imports := []gen.ImportItemDeclr{gen.Import("fmt", "")}
+	if enum.IgnoreCase {
+		imports = append(imports, gen.Import("strings", ""))
+	}
+
+	return []gen.WriteDirective{
+		{
+			FileName: fmt.Sprintf("%s_enum.go", snakeCase(typ.Object.Name.Name)),
+			Writer: gen.Package(
+				gen.Name(pkgDeclr.Package),
+				gen.Imports(imports...),
+				enum,
+			),
+		},
+	}, nil
+}
+
+// camelCase returns the name with its leading word in lower case (e.g UserID => userID,
+// HTTPServer => httpServer).
+func camelCase(name string) string {
+	runes := []rune(name)
+
+	var index int
+	for index < len(runes) && unicode.IsUpper(runes[index]) {
+		index++
+	}
+
+	switch {
+	case index == 0:
+		return name
+	case index > 1 && index < len(runes) && unicode.IsLower(runes[index]):
+		index--
+	}
+
+	return strings.ToLower(string(runes[:index])) + string(runes[index:])
+}
@@ -0,0 +1,73 @@
+package ast_test
+
+import (
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+)
+
+// TestEnumAnnotationGenerator validates @enum annotated types generate compilable enum methods.
+func TestEnumAnnotationGenerator(t *testing.T) {
+	fx := newFixture(t, sourceFile{Name: "colors.go", Source: constSource})
+	pkgs := fx.Packages
+
+	color, _ := pkgs[0].TypeFor("Color")
+	enum, err := ast.EnumFor(color, color.Annotations[0], pkgs[0])
+	if err != nil {
+		tests.Failed("Should have successfully created enum declaration: %+q", err)
+	}
+	tests.Passed("Should have successfully created enum declaration")
+
+	var names []string
+	for _, value := range enum.Values {
+		names = append(names, value.Constant+"="+value.Name)
+	}
+
+	if strings.Join(names, ",") != "ColorRed=red,ColorDarkGreen=dark-green,ColorBlue=azure" {
+		tests.Info("Values: %s", strings.Join(names, ","))
+		tests.Failed("Should have successfully named enum values skipping aliases")
+	}
+	tests.Passed("Should have successfully named enum values skipping aliases")
+
+	registry := ast.NewAnnotationRegistry()
+	registry.RegisterType(ast.EnumAnnotation, ast.EnumAnnotationGenerator)
+
+	directives, err := registry.ParseDeclr(pkgs[0], pkgs[0].Packages[0], fx.Dir)
+	if err != nil {
+		tests.Failed("Should have successfully generated enum directives: %+q", err)
+	}
+
+	if len(directives) != 2 || directives[0].FileName != "color_enum.go" || directives[1].FileName != "level_enum.go" {
+		tests.Failed("Should have successfully generated a file per enum type")
+	}
+	tests.Passed("Should have successfully generated a file per enum type")
+
+	files := directiveFiles(directives)
+	for _, file := range files {
+		if file.Name == "level_enum.go" && !strings.Contains(file.Source, `case Error:
+		return "ERROR"`) {
+			tests.Info("Source: %s", file.Source)
+			tests.Failed("Should have successfully applied case transform to names")
+		}
+	}
+
+	checked := fx.Check(files...)
+	tests.Passed("Should have successfully rendered compilable enum files")
+
+	level := checked.Scope().Lookup("Level").Type()
+	methods := types.NewMethodSet(types.NewPointer(level))
+	for _, name := range []string{"String", "Values", "IsValid", "MarshalText", "UnmarshalText"} {
+		if methods.Lookup(checked, name) == nil {
+			tests.Info("Method: %s", name)
+			tests.Failed("Should have successfully generated enum methods")
+		}
+	}
+
+	if checked.Scope().Lookup("ParseLevel") == nil {
+		tests.Failed("Should have successfully generated enum methods")
+	}
+	tests.Passed("Should have successfully generated enum methods")
+}
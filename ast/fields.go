@@ -0,0 +1,338 @@
+package ast
+
+import (
+	"errors"
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// maxFieldDepth defines the maximum depth of embedded structs walked for promoted fields.
+const maxFieldDepth = 32
+
+// EffectiveFields returns the fields of the struct as accessible on a value of it, including fields
+// promoted from embedded structs declared within the file of the struct or imported packages.
+// Promoted fields are shadowed by fields of the same name at a shallower depth and fields with the
+// same name at the same depth are dropped as ambiguous, as Go resolves selectors.
+func (str StructDeclaration) EffectiveFields() (Fields, error) {
+	return str.EffectiveFieldsIn(Package{})
+}
+
+// EffectiveFieldsIn returns the effective fields of the struct as EffectiveFields, where embedded
+// structs declared in other files of the struct's package are resolved from pkg.
+func (str StructDeclaration) EffectiveFieldsIn(pkg Package) (Fields, error) {
+	candidates, _, err := collectFields(str, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	return dominantFields(candidates, func(field FieldDeclaration) (string, bool) {
+		return field.FieldName, false
+	}), nil
+}
+
+// EffectiveFieldsFor returns the effective fields of the struct as seen by the tagName tag (e.g json),
+// following the rules of encoding/json. Fields tagged "-" are skipped along with any field promoted
+// through them, embedded structs with a tag name are kept as a single field, and names are taken from
+// the tag if set. Among fields with the same name, the shallowest wins, then the one with a tag name.
+func (str StructDeclaration) EffectiveFieldsFor(tagName string) (Fields, error) {
+	return str.EffectiveFieldsForIn(tagName, Package{})
+}
+
+// EffectiveFieldsForIn returns the effective fields of the struct as EffectiveFieldsFor, where embedded
+// structs declared in other files of the struct's package are resolved from pkg.
+func (str StructDeclaration) EffectiveFieldsForIn(tagName string, pkg Package) (Fields, error) {
+	candidates, _, err := collectFields(str, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagged []FieldDeclaration
+	for _, field := range candidates {
+		if !field.taggedBy(tagName) {
+			continue
+		}
+
+		tag, err := field.GetTag(tagName)
+		named := err == nil && tag.Value != ""
+
+		if field.Embedded && field.Promotes && !named {
+			continue
+		}
+
+		if !field.Exported && !(field.Embedded && field.Promotes) {
+			continue
+		}
+
+		tagged = append(tagged, field)
+	}
+
+	return dominantFields(tagged, func(field FieldDeclaration) (string, bool) {
+		if tag, err := field.GetTag(tagName); err == nil && tag.Value != "" {
+			return tag.Value, true
+		}
+		return field.FieldName, false
+	}), nil
+}
+
+// NameFor returns the name of the field for the giving tag, which is the tag value if set or the field
+// name.
+func (f FieldDeclaration) NameFor(tagName string) string {
+	if tag, err := f.GetTag(tagName); err == nil && tag.Value != "" && tag.Value != "-" {
+		return tag.Value
+	}
+	return f.FieldName
+}
+
+// Selector returns the selector expression accessing the field from the giving root
+// (e.g root.Base.ID for a field ID promoted through Base).
+func (f FieldDeclaration) Selector(root string) string {
+	return strings.Join(append([]string{root}, f.Path...), ".")
+}
+
+// AnnotationsFor returns all annotations with the giving name within the doc or trailing comment of
+// the field.
+func (f FieldDeclaration) AnnotationsFor(typeName string) []AnnotationDeclaration {
+	if f.Field == nil {
+		return nil
+	}
+
+	var comments []string
+	if f.Field.Doc != nil {
+		comments = append(comments, f.Field.Doc.Text())
+	}
+	if f.Field.Comment != nil {
+		comments = append(comments, f.Field.Comment.Text())
+	}
+
+	typeName = strings.TrimPrefix(typeName, "@")
+
+	var found []AnnotationDeclaration
+	for _, annotation := range ReadAnnotationsFromCommentry(strings.NewReader(strings.Join(comments, "\n") + "\n")) {
+		if strings.TrimPrefix(annotation.Name, "@") == typeName {
+			found = append(found, annotation)
+		}
+	}
+
+	return found
+}
+
+// taggedBy returns true/false if the field is neither tagged "-" for the tagName, nor promoted through
+// an embedded field tagged "-" or with a tag name.
+func (f FieldDeclaration) taggedBy(tagName string) bool {
+	if tag, err := f.GetTag(tagName); err == nil && tag.Value == "-" && tag.Raw == "-" {
+		return false
+	}
+
+	for _, embedding := range f.Embeddings {
+		if tag, err := embedding.GetTag(tagName); err == nil && tag.Value != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+//===========================================================================================================
+
+// fieldLevel defines an embedded struct whose fields are collected at a giving depth.
+type fieldLevel struct {
+	str   StructDeclaration
+	pkg   Package
+	owner *FieldDeclaration
+}
+
+// collectFields returns all fields of the struct and of the structs embedded within it at any depth,
+// breadth first, along with the package each field was declared in. Structs already walked at a
+// shallower depth are skipped, which protects against cycles through embedded pointers.
+func collectFields(str StructDeclaration, pkg Package) ([]FieldDeclaration, []Package, error) {
+	if str.Declr == nil || str.Struct == nil {
+		return nil, nil, errors.New("StructDeclaration has no PackageDeclaration field")
+	}
+
+	var fields []FieldDeclaration
+	var within []Package
+
+	visited := map[string]bool{}
+	levels := []fieldLevel{{str: str, pkg: pkg}}
+
+	for depth := 0; len(levels) > 0 && depth < maxFieldDepth; depth++ {
+		var next []fieldLevel
+
+		seen := map[string]bool{}
+		for _, level := range levels {
+			key := structKey(level.str)
+			if visited[key] {
+				continue
+			}
+			seen[key] = true
+
+			for _, field := range GetFields(level.str, level.str.Declr) {
+				field.Depth = depth
+				field.Promoted = depth > 0
+
+				if owner := level.owner; owner != nil {
+					field.Index = append(append([]int{}, owner.Index...), field.Index...)
+					field.Path = append(append([]string{}, owner.Path...), field.Path...)
+					field.Embeddings = append(append([]FieldDeclaration{}, owner.Embeddings...), *owner)
+
+					for index := range field.Tags {
+						field.Tags[index].Field = field
+					}
+				}
+
+				if field.Embedded {
+					if embedded, within, ok := embeddedStruct(field, level.pkg); ok {
+						field.Promotes = true
+
+						owner := field
+						next = append(next, fieldLevel{str: embedded, pkg: within, owner: &owner})
+					}
+				}
+
+				fields = append(fields, field)
+				within = append(within, level.pkg)
+			}
+		}
+
+		for key := range seen {
+			visited[key] = true
+		}
+
+		levels = next
+	}
+
+	return fields, within, nil
+}
+
+// dominantFields returns the fields which dominate all others with the same name, where the name
+// and whether it was explicitly given (e.g by a tag) are returned by nameOf. The shallowest field
+// with a name wins, then the only one at that depth with an explicit name, else none. Fields are
+// returned in index order, with promoted fields in place of the fields promoting them.
+func dominantFields(fields []FieldDeclaration, nameOf func(FieldDeclaration) (string, bool)) Fields {
+	var names []string
+
+	byName := map[string][]FieldDeclaration{}
+	for _, field := range fields {
+		name, _ := nameOf(field)
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], field)
+	}
+
+	var dominant Fields
+	for _, name := range names {
+		candidates := byName[name]
+
+		depth := candidates[0].Depth
+		var shallowest, explicit []FieldDeclaration
+		for _, field := range candidates {
+			if field.Depth < depth {
+				depth = field.Depth
+			}
+		}
+
+		for _, field := range candidates {
+			if field.Depth != depth {
+				continue
+			}
+
+			shallowest = append(shallowest, field)
+			if _, ok := nameOf(field); ok {
+				explicit = append(explicit, field)
+			}
+		}
+
+		switch {
+		case len(shallowest) == 1:
+			dominant = append(dominant, shallowest[0])
+		case len(explicit) == 1:
+			dominant = append(dominant, explicit[0])
+		}
+	}
+
+	sort.SliceStable(dominant, func(i, j int) bool {
+		return lessIndex(dominant[i].Index, dominant[j].Index)
+	})
+
+	return dominant
+}
+
+// lessIndex returns true/false if the index sequence a is ordered before b.
+func lessIndex(a, b []int) bool {
+	for index := 0; index < len(a) && index < len(b); index++ {
+		if a[index] != b[index] {
+			return a[index] < b[index]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// embeddedStruct returns the StructDeclaration of the struct type embedded by the field, along
+// with the package used to resolve its own embedded structs.
+func embeddedStruct(field FieldDeclaration, pkg Package) (StructDeclaration, Package, bool) {
+	if field.Field == nil || field.Declr == nil {
+		return StructDeclaration{}, Package{}, false
+	}
+
+	switch expr := unstar(field.Field.Type).(type) {
+	case *ast.Ident:
+		if str, ok := field.Declr.StructFor(expr.Name); ok {
+			return str, pkg, true
+		}
+
+		if str, ok := pkg.StructFor(expr.Name); ok {
+			return str, pkg, true
+		}
+
+		if spec, ok := identDecl(expr).(*ast.TypeSpec); ok {
+			if structType, ok := spec.Type.(*ast.StructType); ok {
+				return StructDeclaration{
+					Name:    spec.Name.Name,
+					Package: field.Declr.Package,
+					Path:    field.Declr.Path,
+					Object:  spec,
+					Struct:  structType,
+					Declr:   field.Declr,
+				}, pkg, true
+			}
+		}
+	case *ast.SelectorExpr:
+		pkgName, ok := expr.X.(*ast.Ident)
+		if !ok {
+			return StructDeclaration{}, Package{}, false
+		}
+
+		imported, ok := field.Declr.ImportedPackageFor(pkgName.Name)
+		if !ok {
+			return StructDeclaration{}, Package{}, false
+		}
+
+		if str, ok := imported.StructFor(expr.Sel.Name); ok {
+			return str, imported, true
+		}
+	}
+
+	return StructDeclaration{}, Package{}, false
+}
+
+// structKey returns a key identifying the struct type across packages.
+func structKey(str StructDeclaration) string {
+	if str.Object != nil {
+		return str.Path + "." + str.Object.Name.Name
+	}
+	return str.Path + "." + str.Name
+}
+
+// embeddedName returns the field name of an embedded type, which is the name of the type
+// without its package or pointer.
+func embeddedName(expr ast.Expr) string {
+	switch item := unstar(expr).(type) {
+	case *ast.Ident:
+		return item.Name
+	case *ast.SelectorExpr:
+		return item.Sel.Name
+	}
+	return getRealIdentName(expr)
+}
@@ -0,0 +1,141 @@
+package ast_test
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+)
+
+var fieldSources = []sourceFile{
+	{Name: "order.go", Source: `package shop
+
+// Base defines common fields.
+type Base struct {
+	ID      string ` + "`json:\"id\"`" + `
+	Created string ` + "`json:\"created\"`" + `
+}
+
+// Audit defines audit fields.
+type Audit struct {
+	ID      int
+	Updated string ` + "`json:\"updated\"`" + `
+	Note    string
+}
+
+// Hidden defines fields never exposed.
+type Hidden struct {
+	Secret string
+}
+
+// Node defines a linked node.
+type Node struct {
+	*Node
+	Value int
+}
+
+// Order defines an order.
+type Order struct {
+	Base
+	*Audit
+	Meta   ` + "`json:\"meta\"`" + `
+	Hidden ` + "`json:\"-\"`" + `
+	Node
+	Total, Tax float64
+	Note       string
+	secret     string
+}
+`},
+	{Name: "meta.go", Source: `package shop
+
+// Meta defines order metadata.
+type Meta struct {
+	Tags  []string
+	Owner string
+}
+`},
+}
+
+// TestEffectiveFields validates promoted fields are resolved as Go and encoding/json resolve them.
+func TestEffectiveFields(t *testing.T) {
+	fx := newFixture(t, fieldSources...)
+	pkgs := fx.Packages
+
+	order, ok := pkgs[0].StructFor("Order")
+	if !ok {
+		tests.Failed("Should have successfully found Order struct")
+	}
+	tests.Passed("Should have successfully found Order struct")
+
+	fileFields, err := order.EffectiveFields()
+	if err != nil {
+		tests.Failed("Should have successfully retrieved effective fields: %+q", err)
+	}
+
+	if _, ok := ast.Fields(fileFields).ByName("Owner"); ok {
+		tests.Failed("Should have successfully skipped structs declared in other files")
+	}
+	tests.Passed("Should have successfully skipped structs declared in other files")
+
+	fields, err := order.EffectiveFieldsIn(pkgs[0])
+	if err != nil {
+		tests.Failed("Should have successfully retrieved effective fields: %+q", err)
+	}
+	tests.Passed("Should have successfully retrieved effective fields")
+
+	checked := fx.Check()
+
+	orderType := checked.Scope().Lookup("Order").Type()
+
+	names := []string{"Base", "Audit", "Meta", "Hidden", "Node", "Total", "Tax", "Note", "secret", "ID", "Created", "Updated", "Secret", "Value", "Tags", "Owner"}
+	for _, name := range names {
+		obj, index, _ := types.LookupFieldOrMethod(orderType, true, checked, name)
+		field, ok := fields.ByName(name)
+
+		if (obj != nil) != ok {
+			tests.Info("Field: %s", name)
+			tests.Failed("Should have successfully resolved field visibility as Go does")
+		}
+
+		if ok && !reflect.DeepEqual(field.Index, index) {
+			tests.Info("Field: %s", name)
+			tests.Info("Index: %v != %v", field.Index, index)
+			tests.Failed("Should have successfully resolved field index as Go does")
+		}
+	}
+	tests.Passed("Should have successfully resolved fields as Go does")
+
+	if len(fields) != len(names)-1 {
+		tests.Info("Fields: %d", len(fields))
+		tests.Failed("Should have successfully returned only accessible fields")
+	}
+	tests.Passed("Should have successfully returned only accessible fields")
+
+	updated, _ := fields.ByName("Updated")
+	if !updated.Promoted || updated.Depth != 1 || updated.Selector("o") != "o.Audit.Updated" || updated.Embeddings[0].FieldName != "Audit" {
+		tests.Info("Field: %#v", updated)
+		tests.Failed("Should have successfully recorded access path of promoted field")
+	}
+	tests.Passed("Should have successfully recorded access path of promoted field")
+
+	tagged, err := order.EffectiveFieldsForIn("json", pkgs[0])
+	if err != nil {
+		tests.Failed("Should have successfully retrieved tagged fields: %+q", err)
+	}
+
+	var jsonNames []string
+	for _, field := range tagged {
+		jsonNames = append(jsonNames, field.NameFor("json"))
+	}
+
+	expected := "id,created,ID,updated,meta,Value,Total,Tax,Note"
+	if strings.Join(jsonNames, ",") != expected {
+		tests.Info("Names: %s", strings.Join(jsonNames, ","))
+		tests.Info("Expected: %s", expected)
+		tests.Failed("Should have successfully resolved tagged fields as encoding/json does")
+	}
+	tests.Passed("Should have successfully resolved tagged fields as encoding/json does")
+}
@@ -0,0 +1,122 @@
+package ast_test
+
+import (
+	"bytes"
+	goast "go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influx6/faux/metrics"
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/gobuild/build"
+	"github.com/influx6/moz/ast"
+)
+
+// sourceFile defines a Go source file of a test package.
+type sourceFile struct {
+	Name   string
+	Source string
+}
+
+// fixture defines a package of source files written into a temporary directory and parsed by
+// the ast package.
+type fixture struct {
+	Dir      string
+	Packages []ast.Package
+	Files    []sourceFile
+}
+
+// newFixture writes the source files into a temporary directory, which is removed once the test
+// completes, and parses the package within.
+func newFixture(t *testing.T, files ...sourceFile) fixture {
+	dir, err := ioutil.TempDir("", "moz-fixture")
+	if err != nil {
+		tests.Failed("Should have successfully created temporary directory: %+q", err)
+	}
+
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	for _, file := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, file.Name), []byte(file.Source), 0644); err != nil {
+			tests.Failed("Should have successfully written source file %q: %+q", file.Name, err)
+		}
+	}
+
+	pkgs, err := ast.PackageWithBuildCtx(metrics.New(), dir, build.Default)
+	if err != nil {
+		tests.Failed("Should have successfully parsed package: %+q", err)
+	}
+	tests.Passed("Should have successfully parsed package")
+
+	return fixture{Dir: dir, Packages: pkgs, Files: files}
+}
+
+// Check type checks the source files of the fixture along with the extra files, where extra files
+// named after a file of the fixture replace it.
+func (fx fixture) Check(extra ...sourceFile) *types.Package {
+	replaced := make(map[string]bool, len(extra))
+	for _, file := range extra {
+		replaced[file.Name] = true
+	}
+
+	var files []sourceFile
+	for _, file := range fx.Files {
+		if !replaced[file.Name] {
+			files = append(files, file)
+		}
+	}
+
+	return typeCheck(types.Config{Importer: importer.Default()}, append(files, extra...)...)
+}
+
+// directiveFiles returns the rendered content of the directives as source files named after them.
+func directiveFiles(directives []ast.AnnotationWriteDirective) []sourceFile {
+	files := make([]sourceFile, 0, len(directives))
+
+	for _, directive := range directives {
+		var source bytes.Buffer
+		if _, err := directive.Writer.WriteTo(&source); err != nil {
+			tests.Failed("Should have successfully rendered %q: %+q", directive.FileName, err)
+		}
+
+		files = append(files, sourceFile{Name: directive.FileName, Source: source.String()})
+	}
+
+	return files
+}
+
+// typeCheck parses and type checks the source files as a single package with the config, logging
+// the sources if they fail to check.
+func typeCheck(config types.Config, files ...sourceFile) *types.Package {
+	fset := token.NewFileSet()
+
+	parsed := make([]*goast.File, 0, len(files))
+	for _, file := range files {
+		item, err := parser.ParseFile(fset, file.Name, file.Source, 0)
+		if err != nil {
+			tests.Info("Source: %s", file.Source)
+			tests.Failed("Should have successfully parsed source file %q: %+q", file.Name, err)
+		}
+
+		parsed = append(parsed, item)
+	}
+
+	checked, err := config.Check(parsed[0].Name.Name, fset, parsed, nil)
+	if err != nil {
+		for _, file := range files {
+			tests.Info("Source %s: %s", file.Name, file.Source)
+		}
+		tests.Failed("Should have successfully type checked source files: %+q", err)
+	}
+	tests.Passed("Should have successfully type checked source files")
+
+	return checked
+}
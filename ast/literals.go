@@ -0,0 +1,545 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// ValueProvider returns the Go source of a value for a type, where fieldName is empty when
+// the value is not for a struct field. Returning an empty string leaves the value to the
+// ValueGenerator.
+type ValueProvider func(typeName string, fieldName string) string
+
+// maxLiteralDepth sets the maximum nesting of generated literals.
+const maxLiteralDepth = 8
+
+// ProvideType registers a ValueProvider used for all values of the giving type (e.g time.Time,
+// *User, []string) created by the generator's literal functions.
+func (g *ValueGenerator) ProvideType(typeName string, provider ValueProvider) {
+	g.ml.Lock()
+	defer g.ml.Unlock()
+
+	if g.types == nil {
+		g.types = make(map[string]ValueProvider)
+	}
+
+	g.types[typeName] = provider
+}
+
+// ProvideField registers a ValueProvider used for all struct fields with the giving name,
+// matched first exactly and then case-insensitively. Field providers take priority over
+// type providers.
+func (g *ValueGenerator) ProvideField(fieldName string, provider ValueProvider) {
+	g.ml.Lock()
+	defer g.ml.Unlock()
+
+	if g.fields == nil {
+		g.fields = make(map[string]ValueProvider)
+	}
+
+	g.fields[fieldName] = provider
+}
+
+// provided returns the value from a registered provider for the type or field if any.
+func (g *ValueGenerator) provided(typeName string, fieldName string) (string, bool) {
+	g.ml.Lock()
+	field, ok := g.fields[fieldName]
+	if !ok && fieldName != "" {
+		field, ok = g.fields[strings.ToLower(fieldName)]
+	}
+	typed, tok := g.types[typeName]
+	g.ml.Unlock()
+
+	if ok && fieldName != "" {
+		if value := field(typeName, fieldName); value != "" {
+			return value, true
+		}
+	}
+
+	if tok {
+		if value := typed(typeName, fieldName); value != "" {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// samples returns the number of elements to generate for slices and maps.
+func (g *ValueGenerator) samples() int {
+	if g.Samples <= 0 {
+		return 2
+	}
+	return g.Samples
+}
+
+//===========================================================================================================
+
+// DefaultLiteral returns the Go source of the default value for the type expression, resolving
+// named types from the package declaration, which may be nil. Structs are written as literals
+// holding only fields with non-zero defaults, and named types with declared constants default
+// to the first constant.
+func (g *ValueGenerator) DefaultLiteral(expr ast.Expr, pkg *PackageDeclaration) string {
+	value, _ := g.literals(pkg, false).literal(expr, "", 0)
+	return value
+}
+
+// RandomLiteral returns the Go source of a random value for the type expression, resolving
+// named types from the package declaration, which may be nil. Structs are written with all
+// exported fields, slices and maps with Samples elements, pointers as allocations and named
+// types with declared constants pick one of them.
+func (g *ValueGenerator) RandomLiteral(expr ast.Expr, pkg *PackageDeclaration) string {
+	value, _ := g.literals(pkg, true).literal(expr, "", 0)
+	return value
+}
+
+// DefaultFieldLiteral returns the Go source of the default value for the field.
+func (g *ValueGenerator) DefaultFieldLiteral(fld FieldDeclaration) string {
+	value, _ := g.literals(fld.Declr, false).literal(fieldExpr(fld), fld.FieldName, 0)
+	return value
+}
+
+// RandomFieldLiteral returns the Go source of a random value for the field.
+func (g *ValueGenerator) RandomFieldLiteral(fld FieldDeclaration) string {
+	value, _ := g.literals(fld.Declr, true).literal(fieldExpr(fld), fld.FieldName, 0)
+	return value
+}
+
+// DefaultArgLiteral returns the Go source of the default value for the argument type.
+func (g *ValueGenerator) DefaultArgLiteral(arg ArgType, pkg *PackageDeclaration) string {
+	value, _ := g.literals(pkg, false).literal(argExpr(arg), arg.Name, 0)
+	return value
+}
+
+// RandomArgLiteral returns the Go source of a random value for the argument type.
+func (g *ValueGenerator) RandomArgLiteral(arg ArgType, pkg *PackageDeclaration) string {
+	value, _ := g.literals(pkg, true).literal(argExpr(arg), arg.Name, 0)
+	return value
+}
+
+// typeNameLiteral returns the Go source of a value for the type name, if it parses as a type.
+func (g *ValueGenerator) typeNameLiteral(typeName string, fieldName string, random bool) (string, bool) {
+	expr, err := parser.ParseExpr(typeName)
+	if err != nil {
+		return "", false
+	}
+
+	value, _ := g.literals(nil, random).literal(expr, fieldName, 0)
+	return value, true
+}
+
+func (g *ValueGenerator) literals(pkg *PackageDeclaration, random bool) *literalWriter {
+	return &literalWriter{
+		gen:    g,
+		pkg:    pkg,
+		random: random,
+		active: make(map[string]bool),
+	}
+}
+
+// fieldExpr returns the type expression of the field.
+func fieldExpr(fld FieldDeclaration) ast.Expr {
+	if fld.Field != nil {
+		return fld.Field.Type
+	}
+	return argExpr(fld.Arg)
+}
+
+// argExpr returns the type expression of the argument type.
+func argExpr(arg ArgType) ast.Expr {
+	expr, err := parser.ParseExpr(arg.Type)
+	if err != nil {
+		return ast.NewIdent(arg.Type)
+	}
+
+	if ident, ok := expr.(*ast.Ident); ok && arg.TypeObject != nil {
+		ident.Obj = arg.TypeObject
+	}
+
+	return expr
+}
+
+//===========================================================================================================
+
+// literalWriter writes Go literals for type expressions.
+type literalWriter struct {
+	gen    *ValueGenerator
+	pkg    *PackageDeclaration
+	random bool
+	active map[string]bool
+}
+
+// literal returns the Go source of a value for the type and true/false if it is the
+// zero value of the type.
+func (lw *literalWriter) literal(expr ast.Expr, fieldName string, depth int) (string, bool) {
+	typeName := exprString(expr)
+
+	if value, ok := lw.gen.provided(typeName, fieldName); ok {
+		return value, false
+	}
+
+	if depth > maxLiteralDepth {
+		return fmt.Sprintf("*new(%s)", typeName), true
+	}
+
+	switch item := expr.(type) {
+	case *ast.ParenExpr:
+		return lw.literal(item.X, fieldName, depth)
+	case *ast.Ident:
+		if value, ok := lw.basic(item.Name, fieldName); ok {
+			return value, !lw.random
+		}
+
+		switch item.Name {
+		case "error", "any":
+			return "nil", true
+		}
+
+		spec := lw.typeSpec(item)
+		if spec == nil {
+			return fmt.Sprintf("*new(%s)", typeName), true
+		}
+
+		return lw.named(typeName, spec, fieldName, depth)
+	case *ast.SelectorExpr:
+		switch typeName {
+		case "time.Time":
+			if !lw.random {
+				return "time.Time{}", true
+			}
+			return fmt.Sprintf("time.Unix(%d, 0).UTC()", lw.gen.Now().Unix()), false
+		case "time.Duration":
+			if !lw.random {
+				return "time.Duration(0)", true
+			}
+			return fmt.Sprintf("time.Duration(%d) * time.Second", lw.gen.int63n(3600)+1), false
+		}
+
+		return fmt.Sprintf("*new(%s)", typeName), true
+	case *ast.StarExpr:
+		if !lw.random || lw.isActive(item.X) {
+			return "nil", true
+		}
+
+		value, _ := lw.literal(item.X, fieldName, depth+1)
+		if lw.isComposite(item.X) {
+			return "&" + value, false
+		}
+
+		return fmt.Sprintf("func() %s { v := %s; return &v }()", typeName, value), false
+	case *ast.ArrayType:
+		return lw.array(typeName, item, fieldName, depth)
+	case *ast.Ellipsis:
+		return lw.array("[]"+exprString(item.Elt), &ast.ArrayType{Elt: item.Elt}, fieldName, depth)
+	case *ast.MapType:
+		return lw.mapOf(typeName, item, depth)
+	case *ast.StructType:
+		return lw.structOf(typeName, item, depth)
+	case *ast.ChanType:
+		if !lw.random {
+			return "nil", true
+		}
+		return fmt.Sprintf("make(%s)", typeName), false
+	}
+
+	// Interfaces, functions and unknown expressions can only be nil.
+	return "nil", true
+}
+
+// named returns a value of the named type declared by the spec.
+func (lw *literalWriter) named(typeName string, spec *ast.TypeSpec, fieldName string, depth int) (string, bool) {
+	if lw.active[typeName] {
+		return fmt.Sprintf("*new(%s)", typeName), true
+	}
+
+	lw.active[typeName] = true
+	defer delete(lw.active, typeName)
+
+	if constants := lw.constantsOf(typeName); len(constants) != 0 {
+		if !lw.random {
+			return constants[0], false
+		}
+		return constants[lw.gen.int63n(int64(len(constants)))], false
+	}
+
+	switch underlying := spec.Type.(type) {
+	case *ast.StructType:
+		return lw.structOf(typeName, underlying, depth)
+	case *ast.ArrayType, *ast.MapType:
+		value, zero := lw.literal(underlying, fieldName, depth+1)
+		if prefix := exprString(underlying); strings.HasPrefix(value, prefix+"{") {
+			return typeName + strings.TrimPrefix(value, prefix), zero
+		}
+		return fmt.Sprintf("%s(%s)", typeName, value), zero
+	case *ast.Ident, *ast.SelectorExpr, *ast.StarExpr, *ast.ParenExpr:
+		value, zero := lw.literal(underlying, fieldName, depth+1)
+		if value == "nil" {
+			return fmt.Sprintf("%s(nil)", typeName), zero
+		}
+		return fmt.Sprintf("%s(%s)", typeName, untyped(value)), zero
+	}
+
+	return fmt.Sprintf("*new(%s)", typeName), true
+}
+
+// structOf returns a struct literal of the type, holding all exported fields when random or
+// only fields with non-zero values otherwise.
+func (lw *literalWriter) structOf(typeName string, str *ast.StructType, depth int) (string, bool) {
+	var values []string
+
+	for _, field := range str.Fields.List {
+		names := field.Names
+		if len(names) == 0 {
+			name := getRealIdentName(unstar(field.Type))
+			if index := strings.LastIndex(name, "."); index != -1 {
+				name = name[index+1:]
+			}
+			names = []*ast.Ident{ast.NewIdent(name)}
+		}
+
+		for _, name := range names {
+			if !name.IsExported() {
+				continue
+			}
+
+			value, zero := lw.literal(field.Type, name.Name, depth+1)
+			if zero && !lw.random {
+				continue
+			}
+
+			values = append(values, fmt.Sprintf("%s: %s", name.Name, value))
+		}
+	}
+
+	return fmt.Sprintf("%s{%s}", typeName, strings.Join(values, ", ")), len(values) == 0
+}
+
+// array returns a slice or array literal of the type.
+func (lw *literalWriter) array(typeName string, arr *ast.ArrayType, fieldName string, depth int) (string, bool) {
+	if isByteSlice(arr) && lw.random {
+		return fmt.Sprintf("[]byte(%s)", strconv.Quote(lw.gen.namedString(fieldName))), false
+	}
+
+	total := lw.gen.samples()
+	if arr.Len != nil {
+		size, err := arrayLen(arr.Len)
+		if err != nil {
+			total = 0
+		} else if size < total {
+			total = size
+		}
+	}
+
+	if !lw.random || lw.isActive(arr.Elt) {
+		return typeName + "{}", true
+	}
+
+	values := make([]string, 0, total)
+	for index := 0; index < total; index++ {
+		value, _ := lw.literal(arr.Elt, "", depth+1)
+		values = append(values, value)
+	}
+
+	return fmt.Sprintf("%s{%s}", typeName, strings.Join(values, ", ")), false
+}
+
+// mapOf returns a map literal of the type, with distinct keys.
+func (lw *literalWriter) mapOf(typeName string, mp *ast.MapType, depth int) (string, bool) {
+	if !lw.random || lw.isActive(mp.Key) || lw.isActive(mp.Value) {
+		return typeName + "{}", true
+	}
+
+	seen := make(map[string]bool)
+	total := lw.gen.samples()
+
+	var values []string
+	for attempt := 0; attempt < total*3 && len(values) < total; attempt++ {
+		key, _ := lw.literal(mp.Key, "", depth+1)
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		value, _ := lw.literal(mp.Value, "", depth+1)
+		values = append(values, fmt.Sprintf("%s: %s", key, value))
+	}
+
+	return fmt.Sprintf("%s{%s}", typeName, strings.Join(values, ", ")), false
+}
+
+// basic returns a value for a predeclared basic type.
+func (lw *literalWriter) basic(typeName string, fieldName string) (string, bool) {
+	g := lw.gen
+
+	switch typeName {
+	case "bool":
+		if !lw.random {
+			return "false", true
+		}
+		return strconv.FormatBool(g.bool()), true
+	case "string":
+		if !lw.random {
+			return `""`, true
+		}
+		return strconv.Quote(g.namedString(fieldName)), true
+	case "int":
+		if !lw.random {
+			return "0", true
+		}
+		return g.namedInt(fieldName), true
+	case "rune":
+		if !lw.random {
+			return "rune(0)", true
+		}
+		return fmt.Sprintf("%q", g.character()), true
+	case "float64":
+		if !lw.random {
+			return "0.0", true
+		}
+		return fmt.Sprintf("%.4f", g.float64()), true
+	case "float32":
+		if !lw.random {
+			return "float32(0)", true
+		}
+		return fmt.Sprintf("float32(%.4f)", g.float64()), true
+	case "complex64", "complex128":
+		if !lw.random {
+			return fmt.Sprintf("%s(0)", typeName), true
+		}
+		return fmt.Sprintf("%s(complex(%.4f, %.4f))", typeName, g.float64(), g.float64()), true
+	}
+
+	if limit, ok := integerLimits[typeName]; ok {
+		if !lw.random {
+			return fmt.Sprintf("%s(0)", typeName), true
+		}
+		return fmt.Sprintf("%s(%d)", typeName, g.int63n(limit)), true
+	}
+
+	return "", false
+}
+
+// integerLimits defines the exclusive upper bound of random values for sized integer types, where
+// uint and uintptr are bound to fit 32-bit platforms.
+var integerLimits = map[string]int64{
+	"int8":    1 << 7,
+	"int16":   1 << 15,
+	"int32":   1 << 31,
+	"int64":   1 << 62,
+	"uint":    1 << 31,
+	"uint8":   1 << 8,
+	"byte":    1 << 8,
+	"uint16":  1 << 16,
+	"uint32":  1 << 32,
+	"uint64":  1 << 62,
+	"uintptr": 1 << 31,
+}
+
+// typeSpec returns the declaration of the named type if it can be found.
+func (lw *literalWriter) typeSpec(ident *ast.Ident) *ast.TypeSpec {
+	if ident.Obj != nil {
+		if spec, ok := ident.Obj.Decl.(*ast.TypeSpec); ok {
+			return spec
+		}
+	}
+
+	if lw.pkg == nil {
+		return nil
+	}
+
+	for _, item := range lw.pkg.Structs {
+		if item.Object != nil && item.Object.Name.Name == ident.Name {
+			return item.Object
+		}
+	}
+
+	for _, item := range lw.pkg.Types {
+		if item.Object != nil && item.Object.Name.Name == ident.Name {
+			return item.Object
+		}
+	}
+
+	for _, item := range lw.pkg.Interfaces {
+		if item.Object != nil && item.Object.Name.Name == ident.Name {
+			return item.Object
+		}
+	}
+
+	return nil
+}
+
+// constantsOf returns the names of all constants declared with the named type in declaration order.
+func (lw *literalWriter) constantsOf(typeName string) []string {
+	if lw.pkg == nil {
+		return nil
+	}
+
+	var names []string
+	for _, item := range lw.pkg.ConstantsFor(typeName) {
+		if item.Name != "_" {
+			names = append(names, item.Name)
+		}
+	}
+
+	return names
+}
+
+// isActive returns true/false if the expression refers to a named type currently being written,
+// which would otherwise recurse without end.
+func (lw *literalWriter) isActive(expr ast.Expr) bool {
+	return lw.active[exprString(unstar(expr))]
+}
+
+// isComposite returns true/false if values of the type are written as composite literals,
+// whose address can be taken directly.
+func (lw *literalWriter) isComposite(expr ast.Expr) bool {
+	switch item := expr.(type) {
+	case *ast.StructType, *ast.ArrayType, *ast.MapType:
+		return true
+	case *ast.Ident:
+		if spec := lw.typeSpec(item); spec != nil && len(lw.constantsOf(item.Name)) == 0 {
+			switch spec.Type.(type) {
+			case *ast.StructType, *ast.ArrayType, *ast.MapType:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// arrayLen returns the length of an array type with a literal length.
+func arrayLen(expr ast.Expr) (int, error) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, fmt.Errorf("array length %q is not an integer literal", exprString(expr))
+	}
+
+	size, err := strconv.ParseInt(lit.Value, 0, 64)
+	return int(size), err
+}
+
+// untyped returns the constant within a conversion of a predeclared type, so it can be
+// converted into a named type directly.
+func untyped(value string) string {
+	if index := strings.Index(value, "("); index > 0 && strings.HasSuffix(value, ")") {
+		if _, ok := integerLimits[value[:index]]; ok || strings.HasPrefix(value, "float32(") || strings.HasPrefix(value, "rune(") {
+			return value[index+1 : len(value)-1]
+		}
+	}
+	return value
+}
+
+// exprString returns the Go source of the expression.
+func exprString(expr ast.Expr) string {
+	var bu bytes.Buffer
+	if err := printer.Fprint(&bu, token.NewFileSet(), expr); err != nil {
+		return ""
+	}
+	return bu.String()
+}
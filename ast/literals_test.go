@@ -0,0 +1,128 @@
+package ast_test
+
+import (
+	"fmt"
+	goast "go/ast"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+)
+
+var literalSource = `package store
+
+import "time"
+
+// Status defines the state of an account.
+type Status int
+
+// Account states.
+const (
+	Pending Status = iota
+	Active
+	Closed
+)
+
+// Tags defines a set of labels.
+type Tags []string
+
+// Node defines a tree of nodes.
+type Node struct {
+	Name     string
+	Next     *Node
+	Children []Node
+}
+
+// Account defines a user account.
+type Account struct {
+	Email    string
+	Level    int8
+	Ratio    float32
+	Scores   []float64
+	Lookup   map[int]string
+	Status   Status
+	Tags     Tags
+	Timeout  time.Duration
+	Created  time.Time
+	Owner    *Node
+	Count    *uint16
+	Values   [3]int
+	Handler  func() error
+	internal bool
+}
+`
+
+// TestFieldLiterals validates default and random literals built from field types compile
+// and resolve named types and constants.
+func TestFieldLiterals(t *testing.T) {
+	fx := newFixture(t, sourceFile{Name: "store.go", Source: literalSource})
+	pkgs := fx.Packages
+
+	account, ok := pkgs[0].StructFor("Account")
+	if !ok {
+		tests.Failed("Should have successfully found Account struct")
+	}
+	tests.Passed("Should have successfully found Account struct")
+
+	fields, err := account.Fields()
+	if err != nil {
+		tests.Failed("Should have successfully retrieved Account fields: %+q", err)
+	}
+	tests.Passed("Should have successfully retrieved Account fields")
+
+	values := ast.NewValueGenerator(11, ast.FixedClock(time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)))
+	values.ProvideField("email", func(typeName string, fieldName string) string {
+		return `"bob@example.com"`
+	})
+
+	expected := map[string]string{
+		"Email":   `"bob@example.com"`,
+		"Level":   "int8(0)",
+		"Ratio":   "float32(0)",
+		"Scores":  "[]float64{}",
+		"Lookup":  "map[int]string{}",
+		"Status":  "Pending",
+		"Tags":    "Tags{}",
+		"Timeout": "time.Duration(0)",
+		"Created": "time.Time{}",
+		"Owner":   "nil",
+		"Values":  "[3]int{}",
+		"Handler": "nil",
+	}
+
+	var source []string
+	for _, field := range fields {
+		def := values.DefaultFieldLiteral(field)
+		if want, ok := expected[field.FieldName]; ok && def != want {
+			tests.Info("Field: %s", field.FieldName)
+			tests.Info("Literal: %s", def)
+			tests.Info("Expected: %s", want)
+			tests.Failed("Should have successfully matched default literal")
+		}
+
+		random := values.RandomFieldLiteral(field)
+		if field.FieldName == "Status" && random != "Pending" && random != "Active" && random != "Closed" {
+			tests.Info("Literal: %s", random)
+			tests.Failed("Should have successfully picked a declared constant")
+		}
+
+		source = append(source,
+			fmt.Sprintf("var _ %s = %s", typeOf(field.Field.Type, literalSource), def),
+			fmt.Sprintf("var _ %s = %s", typeOf(field.Field.Type, literalSource), random),
+		)
+	}
+	tests.Passed("Should have successfully matched default literals")
+
+	nodeLiteral := values.RandomLiteral(fields[9].Field.Type, fields[9].Declr)
+	source = append(source, fmt.Sprintf("var _ *Node = %s", nodeLiteral))
+
+	fx.Check(sourceFile{Name: "store.go", Source: literalSource + "\n" + strings.Join(source, "\n")})
+	tests.Passed("Should have successfully type checked generated literals")
+}
+
+// typeOf returns the source of the expression within src.
+func typeOf(expr goast.Expr, src string) string {
+	return src[expr.Pos()-1 : expr.End()-1]
+}
@@ -0,0 +1,462 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/influx6/moz/gen"
+)
+
+// MappingConversion defines how the value of a source field is converted into a target field.
+type MappingConversion int
+
+// Conversions supported by a MappingPlan.
+const (
+	// MapAssign assigns the source value as is.
+	MapAssign MappingConversion = iota
+
+	// MapConvert converts a numeric source value into a wider numeric type.
+	MapConvert
+
+	// MapAddress assigns the address of a copy of the source value to a pointer target.
+	MapAddress
+
+	// MapDereference assigns the value of a pointer source if it is not nil.
+	MapDereference
+
+	// MapFormatTime formats a time.Time source value into a string.
+	MapFormatTime
+
+	// MapParseTime parses a non-empty string source value into a time.Time.
+	MapParseTime
+)
+
+// String returns the name of the conversion.
+func (mc MappingConversion) String() string {
+	switch mc {
+	case MapAssign:
+		return "assign"
+	case MapConvert:
+		return "convert"
+	case MapAddress:
+		return "address"
+	case MapDereference:
+		return "dereference"
+	case MapFormatTime:
+		return "format-time"
+	case MapParseTime:
+		return "parse-time"
+	}
+	return "unknown"
+}
+
+// MappingRules defines how fields of a target struct are matched with those of a source struct.
+// Fields are matched by a @map(from => Field) annotation on the target field, then by Fields,
+// then by the Tag value of both fields and lastly by name. Annotating a target field with @map(-)
+// or listing it in Ignore skips it.
+type MappingRules struct {
+	Tag        string
+	IgnoreCase bool
+	Fields     map[string]string
+	Ignore     []string
+
+	// TimeLayout defines the Go expression of the layout used to format and parse time values,
+	// which defaults to time.RFC3339.
+	TimeLayout string
+}
+
+// FieldMapping defines the mapping of a source field into a target field.
+type FieldMapping struct {
+	Target     FieldDeclaration
+	Source     FieldDeclaration
+	TargetType string
+	SourceType string
+	MatchedBy  string
+	Conversion MappingConversion
+}
+
+// MappingDiagnostic defines a target field which could not be mapped and why.
+type MappingDiagnostic struct {
+	Field  FieldDeclaration
+	Reason string
+}
+
+// String returns the diagnostic as a message.
+func (md MappingDiagnostic) String() string {
+	return fmt.Sprintf("%s: %s", strings.Join(md.Field.Path, "."), md.Reason)
+}
+
+// MappingPlan defines the mappings from the fields of a source struct into a target struct, along
+// with the target fields which could not be mapped and the source fields which were not used.
+type MappingPlan struct {
+	From      StructDeclaration
+	To        StructDeclaration
+	Layout    string
+	Fields    []FieldMapping
+	Unmatched []MappingDiagnostic
+	Unused    Fields
+}
+
+// Complete returns true/false if all target fields were mapped.
+func (mp MappingPlan) Complete() bool {
+	return len(mp.Unmatched) == 0
+}
+
+// Imports returns the import paths used by the code the plan renders.
+func (mp MappingPlan) Imports() []string {
+	for _, mapping := range mp.Fields {
+		if mapping.Conversion == MapFormatTime || mapping.Conversion == MapParseTime {
+			return []string{"time"}
+		}
+	}
+	return nil
+}
+
+// Function returns a gen.FunctionDeclr for a function with the giving name, which takes the source
+// struct as fromType (e.g api.User) and returns the target struct as toType with an error.
+func (mp MappingPlan) Function(name string, fromType string, toType string) gen.FunctionDeclr {
+	return gen.FunctionWithParams(
+		gen.Name(name),
+		gen.Params(gen.Param("from", fromType)),
+		gen.Returns(gen.Type(toType), gen.Type("error")),
+		mp.Body("from", "to", toType),
+	)
+}
+
+// Body returns the statements mapping the source value named from into a target value named to,
+// declared with the giving toType and returned along with an error.
+func (mp MappingPlan) Body(from string, to string, toType string) io.WriterTo {
+	var body bytes.Buffer
+
+	fmt.Fprintf(&body, "var %s %s\n", to, toType)
+
+	for _, mapping := range mp.Fields {
+		source := mapping.Source.Selector(from)
+		target := mapping.Target.Selector(to)
+
+		var guards []string
+		for _, embedding := range mapping.Source.Embeddings {
+			if isPointerField(embedding) {
+				guards = append(guards, embedding.Selector(from)+" != nil")
+			}
+		}
+
+		var statement string
+
+		switch mapping.Conversion {
+		case MapAssign:
+			statement = fmt.Sprintf("%s = %s\n", target, source)
+		case MapConvert:
+			statement = fmt.Sprintf("%s = %s(%s)\n", target, mapping.TargetType, source)
+		case MapAddress:
+			value := mappingVar(mapping.Target, "Value")
+			statement = fmt.Sprintf("%s := %s\n%s = &%s\n", value, source, target, value)
+		case MapDereference:
+			guards = append(guards, source+" != nil")
+			statement = fmt.Sprintf("%s = *%s\n", target, source)
+		case MapFormatTime:
+			statement = fmt.Sprintf("%s = %s.Format(%s)\n", target, source, mp.Layout)
+		case MapParseTime:
+			value := mappingVar(mapping.Target, "Time")
+			guards = append(guards, source+` != ""`)
+			statement = fmt.Sprintf("%s, err := time.Parse(%s, %s)\nif err != nil {\n\treturn %s, err\n}\n%s = %s\n", value, mp.Layout, source, to, target, value)
+		}
+
+		if len(guards) == 0 {
+			body.WriteString(statement)
+			continue
+		}
+
+		fmt.Fprintf(&body, "if %s {\n", strings.Join(guards, " && "))
+		for _, line := range strings.SplitAfter(statement, "\n") {
+			if line != "" {
+				body.WriteString("\t" + line)
+			}
+		}
+		body.WriteString("}\n")
+	}
+
+	fmt.Fprintf(&body, "return %s, nil", to)
+
+	return gen.Text(body.String())
+}
+
+//===========================================================================================================
+
+// MapStructs returns a MappingPlan mapping the fields of the from struct into the fields of the to
+// struct according to the giving rules. Fields promoted from embedded structs are mapped individually
+// and target fields which can not be matched or converted are reported in MappingPlan.Unmatched.
+func MapStructs(from StructDeclaration, to StructDeclaration, rules MappingRules) (MappingPlan, error) {
+	plan := MappingPlan{From: from, To: to, Layout: rules.TimeLayout}
+	if plan.Layout == "" {
+		plan.Layout = "time.RFC3339"
+	}
+
+	sources, err := from.EffectiveFields()
+	if err != nil {
+		return plan, err
+	}
+
+	targets, err := to.EffectiveFields()
+	if err != nil {
+		return plan, err
+	}
+
+	samePackage := from.Declr.Path == to.Declr.Path && from.Declr.Package == to.Declr.Package
+
+	ignored := map[string]bool{}
+	for _, name := range rules.Ignore {
+		ignored[name] = true
+	}
+
+	used := map[string]bool{}
+
+	for _, target := range targets {
+		if target.Embedded && target.Promotes {
+			continue
+		}
+
+		if !target.Exported && !samePackage {
+			continue
+		}
+
+		path := strings.Join(target.Path, ".")
+		if ignored[path] || ignored[target.FieldName] {
+			continue
+		}
+
+		sourceName, matchedBy, skip := mappingSourceFor(target, rules)
+		if skip {
+			continue
+		}
+
+		var source FieldDeclaration
+		var found bool
+
+		switch {
+		case sourceName != "":
+			source, found = fieldByPath(sources, sourceName, false)
+		case rules.Tag != "":
+			if tag, err := target.GetTag(rules.Tag); err == nil && tag.Value != "" && tag.Value != "-" {
+				for _, field := range sources {
+					if field.NameFor(rules.Tag) == tag.Value {
+						source, found, matchedBy = field, true, "tag"
+						break
+					}
+				}
+			}
+		}
+
+		if !found && sourceName == "" {
+			source, found = fieldByPath(sources, target.FieldName, rules.IgnoreCase)
+			matchedBy = "name"
+		}
+
+		if !found {
+			reason := "no source field found"
+			if sourceName != "" {
+				reason = fmt.Sprintf("source field %q not found", sourceName)
+			}
+
+			plan.Unmatched = append(plan.Unmatched, MappingDiagnostic{Field: target, Reason: reason})
+			continue
+		}
+
+		if !source.Exported && !samePackage {
+			plan.Unmatched = append(plan.Unmatched, MappingDiagnostic{Field: target, Reason: fmt.Sprintf("source field %q is unexported", source.FieldName)})
+			continue
+		}
+
+		if embedding, ok := pointerEmbedding(target); ok {
+			plan.Unmatched = append(plan.Unmatched, MappingDiagnostic{Field: target, Reason: fmt.Sprintf("promoted through embedded pointer %q", embedding.FieldName)})
+			continue
+		}
+
+		mapping := FieldMapping{
+			Target:     target,
+			Source:     source,
+			MatchedBy:  matchedBy,
+			TargetType: qualifiedType(target),
+			SourceType: qualifiedType(source),
+		}
+
+		conversion, ok := conversionFor(mapping.SourceType, mapping.TargetType)
+		if !ok {
+			plan.Unmatched = append(plan.Unmatched, MappingDiagnostic{Field: target, Reason: fmt.Sprintf("can not convert %s to %s", mapping.SourceType, mapping.TargetType)})
+			continue
+		}
+
+		mapping.Conversion = conversion
+
+		used[strings.Join(source.Path, ".")] = true
+		plan.Fields = append(plan.Fields, mapping)
+	}
+
+	for _, source := range sources {
+		if source.Embedded && source.Promotes || !source.Exported && !samePackage {
+			continue
+		}
+
+		if !used[strings.Join(source.Path, ".")] {
+			plan.Unused = append(plan.Unused, source)
+		}
+	}
+
+	return plan, nil
+}
+
+// mappingSourceFor returns the source field name for the target field from its @map annotation or
+// the rules, how it was matched and whether the field is to be skipped.
+func mappingSourceFor(target FieldDeclaration, rules MappingRules) (string, string, bool) {
+	for _, annotation := range target.AnnotationsFor("@map") {
+		if annotation.HasArg("-") {
+			return "", "", true
+		}
+
+		if from := annotation.Param("from"); from != "" {
+			return from, "annotation", false
+		}
+	}
+
+	if from, ok := rules.Fields[strings.Join(target.Path, ".")]; ok {
+		return from, "rule", from == "-"
+	}
+
+	if from, ok := rules.Fields[target.FieldName]; ok {
+		return from, "rule", from == "-"
+	}
+
+	return "", "", false
+}
+
+// fieldByPath returns the field with the giving access path (e.g Base.ID) or name.
+func fieldByPath(fields Fields, path string, ignoreCase bool) (FieldDeclaration, bool) {
+	equal := func(a, b string) bool {
+		if ignoreCase {
+			return strings.EqualFold(a, b)
+		}
+		return a == b
+	}
+
+	for _, field := range fields {
+		if equal(strings.Join(field.Path, "."), path) {
+			return field, true
+		}
+	}
+
+	for _, field := range fields {
+		if equal(field.FieldName, path) {
+			return field, true
+		}
+	}
+
+	return FieldDeclaration{}, false
+}
+
+// pointerEmbedding returns the first embedded pointer field the field is promoted through.
+func pointerEmbedding(field FieldDeclaration) (FieldDeclaration, bool) {
+	for _, embedding := range field.Embeddings {
+		if isPointerField(embedding) {
+			return embedding, true
+		}
+	}
+	return FieldDeclaration{}, false
+}
+
+func isPointerField(field FieldDeclaration) bool {
+	if field.Field == nil {
+		return false
+	}
+
+	_, ok := field.Field.Type.(*ast.StarExpr)
+	return ok
+}
+
+// qualifiedType returns the type of the field with named types qualified by their package path.
+func qualifiedType(field FieldDeclaration) string {
+	if field.Field == nil {
+		return field.FieldTypeName
+	}
+
+	var out bytes.Buffer
+	writeQualifiedType(&out, field.Field.Type, field.Declr)
+	return out.String()
+}
+
+// mappingVar returns a variable name for the target field with the giving suffix.
+func mappingVar(field FieldDeclaration, suffix string) string {
+	name := []rune(strings.Join(field.Path, ""))
+	if len(name) != 0 {
+		name[0] = unicode.ToLower(name[0])
+	}
+	return string(name) + suffix
+}
+
+//===========================================================================================================
+
+// numericBits defines the bits of numeric types as a conversion source and target, where the platform
+// dependent int, uint and uintptr types are taken as their largest size as sources and smallest size
+// as targets, so conversions are widening on any platform.
+var numericBits = map[string]struct {
+	kind   byte
+	source int
+	target int
+}{
+	"int8":    {'i', 8, 8},
+	"int16":   {'i', 16, 16},
+	"int32":   {'i', 32, 32},
+	"rune":    {'i', 32, 32},
+	"int64":   {'i', 64, 64},
+	"int":     {'i', 64, 32},
+	"uint8":   {'u', 8, 8},
+	"byte":    {'u', 8, 8},
+	"uint16":  {'u', 16, 16},
+	"uint32":  {'u', 32, 32},
+	"uint64":  {'u', 64, 64},
+	"uint":    {'u', 64, 32},
+	"uintptr": {'u', 64, 32},
+	"float32": {'f', 24, 24},
+	"float64": {'f', 53, 53},
+}
+
+// conversionFor returns the conversion of a source type into a target type and true/false if one
+// exists.
+func conversionFor(source string, target string) (MappingConversion, bool) {
+	switch {
+	case source == target:
+		return MapAssign, true
+	case "*"+source == target:
+		return MapAddress, true
+	case source == "*"+target:
+		return MapDereference, true
+	case source == "time.Time" && target == "string":
+		return MapFormatTime, true
+	case source == "string" && target == "time.Time":
+		return MapParseTime, true
+	}
+
+	from, ok := numericBits[source]
+	if !ok {
+		return 0, false
+	}
+
+	to, ok := numericBits[target]
+	if !ok {
+		return 0, false
+	}
+
+	switch {
+	case from.kind == to.kind:
+		return MapConvert, from.source <= to.target
+	case from.kind == 'u' && to.kind == 'i':
+		return MapConvert, from.source < to.target
+	case to.kind == 'f' && from.kind != 'f':
+		return MapConvert, from.source <= to.target
+	}
+
+	return 0, false
+}
@@ -0,0 +1,112 @@
+package ast_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+)
+
+var mappingSource = `package users
+
+import "time"
+
+// Audit defines audit fields.
+type Audit struct {
+	Created time.Time
+}
+
+// UserRequest defines a user as received.
+type UserRequest struct {
+	*Audit
+	Name     string  ` + "`json:\"name\"`" + `
+	Email    *string ` + "`json:\"email\"`" + `
+	Age      int32
+	Score    float32
+	Joined   string
+	Nickname string
+	Count    int64
+	Extra    bool
+}
+
+// User defines a user.
+type User struct {
+	FullName string ` + "`json:\"name\"`" + `
+	Email    string ` + "`json:\"email\"`" + `
+	Age      int64
+	Score    float64
+	Created  string
+	Joined   time.Time
+	Alias    *string // @map(from => Nickname)
+	Count    int32
+	Internal string // @map(-)
+	Missing  string
+}
+`
+
+// TestMapStructs validates mapping plans match fields and render compilable converters.
+func TestMapStructs(t *testing.T) {
+	fx := newFixture(t, sourceFile{Name: "users.go", Source: mappingSource})
+	pkgs := fx.Packages
+
+	from, _ := pkgs[0].StructFor("UserRequest")
+	to, _ := pkgs[0].StructFor("User")
+
+	plan, err := ast.MapStructs(from, to, ast.MappingRules{Tag: "json"})
+	if err != nil {
+		tests.Failed("Should have successfully created mapping plan: %+q", err)
+	}
+	tests.Passed("Should have successfully created mapping plan")
+
+	expected := map[string]string{
+		"FullName": "Name tag assign",
+		"Email":    "Email tag dereference",
+		"Age":      "Age name convert",
+		"Score":    "Score name convert",
+		"Created":  "Audit.Created name format-time",
+		"Joined":   "Joined name parse-time",
+		"Alias":    "Nickname annotation address",
+	}
+
+	for _, mapping := range plan.Fields {
+		got := strings.Join([]string{strings.Join(mapping.Source.Path, "."), mapping.MatchedBy, mapping.Conversion.String()}, " ")
+		if expected[mapping.Target.FieldName] != got {
+			tests.Info("Field: %s", mapping.Target.FieldName)
+			tests.Info("Mapping: %s", got)
+			tests.Failed("Should have successfully matched field mapping")
+		}
+		delete(expected, mapping.Target.FieldName)
+	}
+
+	if len(expected) != 0 {
+		tests.Info("Unmapped: %+q", expected)
+		tests.Failed("Should have successfully mapped all matching fields")
+	}
+	tests.Passed("Should have successfully mapped all matching fields")
+
+	var diagnostics []string
+	for _, diagnostic := range plan.Unmatched {
+		diagnostics = append(diagnostics, diagnostic.String())
+	}
+
+	if strings.Join(diagnostics, "; ") != "Count: can not convert int64 to int32; Missing: no source field found" {
+		tests.Info("Diagnostics: %s", strings.Join(diagnostics, "; "))
+		tests.Failed("Should have successfully reported unmatched fields")
+	}
+	tests.Passed("Should have successfully reported unmatched fields")
+
+	if len(plan.Unused) != 2 || plan.Unused[0].FieldName != "Count" || plan.Unused[1].FieldName != "Extra" {
+		tests.Failed("Should have successfully reported unused source fields")
+	}
+	tests.Passed("Should have successfully reported unused source fields")
+
+	var source bytes.Buffer
+	if _, err := plan.Function("ToUser", "UserRequest", "User").WriteTo(&source); err != nil {
+		tests.Failed("Should have successfully rendered mapping function: %+q", err)
+	}
+
+	fx.Check(sourceFile{Name: "users.go", Source: mappingSource + "\n" + source.String()})
+	tests.Passed("Should have successfully rendered compilable mapping function")
+}
@@ -0,0 +1,492 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// MethodDeclaration defines a method within the method set of a type, either declared on the type,
+// promoted from an embedded type or declared by an embedded interface.
+type MethodDeclaration struct {
+	Name      string
+	Signature string
+	Pointer   bool
+	Promoted  bool
+	Depth     int
+	Path      []string
+	Func      FuncDeclaration
+	Type      *ast.FuncType
+	Declr     *PackageDeclaration
+}
+
+// Selector returns the selector expression calling the method from the giving root
+// (e.g root.Base.Close for a method Close promoted through Base).
+func (m MethodDeclaration) Selector(root string) string {
+	return strings.Join(append(append([]string{root}, m.Path...), m.Name), ".")
+}
+
+// MethodSet defines a slice of MethodDeclaration ordered by name.
+type MethodSet []MethodDeclaration
+
+// Find returns the method with the giving name.
+func (ms MethodSet) Find(name string) (MethodDeclaration, bool) {
+	for _, method := range ms {
+		if method.Name == name {
+			return method, true
+		}
+	}
+	return MethodDeclaration{}, false
+}
+
+// Has returns true/false if the method set has a method with the giving name.
+func (ms MethodSet) Has(name string) bool {
+	_, ok := ms.Find(name)
+	return ok
+}
+
+// Missing returns all methods of the giving set which are not in this set with the same signature.
+func (ms MethodSet) Missing(other MethodSet) MethodSet {
+	var missing MethodSet
+
+	for _, method := range other {
+		if found, ok := ms.Find(method.Name); ok && found.Signature == method.Signature {
+			continue
+		}
+		missing = append(missing, method)
+	}
+
+	return missing
+}
+
+// Implements returns true/false if the method set has all methods of the giving interface.
+func (ms MethodSet) Implements(intr MethodSet) bool {
+	return len(ms.Missing(intr)) == 0
+}
+
+//===========================================================================================================
+
+// MethodsFor returns all methods declared with typeName as receiver, by value or pointer,
+// across all files of the package.
+func (pkg Package) MethodsFor(typeName string) []FuncDeclaration {
+	var methods []FuncDeclaration
+
+	for _, declr := range pkg.Packages {
+		methods = append(methods, declr.methodsFor(typeName)...)
+	}
+
+	return methods
+}
+
+// MethodSet returns the method set of the named type declared in the package, or of a pointer to it
+// if pointer is true, as defined by Go. Methods promoted from embedded types are included unless
+// shadowed by a field or method at a shallower depth, and methods declared with a pointer receiver
+// are only included if the type is a pointer or they are promoted through an embedded pointer.
+// Interface types return the methods of the interface.
+func (pkg Package) MethodSet(typeName string, pointer bool) (MethodSet, error) {
+	if intr, ok := pkg.InterfaceFor(typeName); ok {
+		return pkg.InterfaceMethods(intr), nil
+	}
+
+	var candidates []methodCandidate
+	for _, method := range pkg.MethodsFor(typeName) {
+		candidates = append(candidates, methodCandidate{name: method.FuncName, method: declaredMethod(method)})
+	}
+
+	if str, ok := pkg.StructFor(typeName); ok {
+		fields, within, err := collectFields(str, pkg)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates = append(candidates, embeddedMethods(fields, within)...)
+	} else if _, ok := pkg.TypeFor(typeName); !ok && len(candidates) == 0 {
+		return nil, fmt.Errorf("Type %q not found in package %q", typeName, pkg.Name)
+	}
+
+	return dominantMethods(candidates, pointer), nil
+}
+
+// InterfaceMethods returns the methods of the interface including those of embedded interfaces
+// declared in the package, imported packages or the predeclared error interface.
+func (pkg Package) InterfaceMethods(intr InterfaceDeclaration) MethodSet {
+	set := interfaceMethods(intr.Interface, intr.Declr, pkg, map[*ast.InterfaceType]bool{})
+
+	sort.Slice(set, func(i, j int) bool {
+		return set[i].Name < set[j].Name
+	})
+
+	return set
+}
+
+// Implements returns true/false if the named type declared in the package, or a pointer to it if pointer
+// is true, has all methods of the interface with matching signatures.
+func (pkg Package) Implements(typeName string, pointer bool, intr InterfaceDeclaration) (bool, error) {
+	missing, err := pkg.MissingMethods(typeName, pointer, intr)
+	if err != nil {
+		return false, err
+	}
+
+	return len(missing) == 0, nil
+}
+
+// MissingMethods returns the methods of the interface which the named type declared in the package,
+// or a pointer to it if pointer is true, does not have with matching signatures. Generators can use it
+// to only emit methods which do not exist yet.
+func (pkg Package) MissingMethods(typeName string, pointer bool, intr InterfaceDeclaration) (MethodSet, error) {
+	set, err := pkg.MethodSet(typeName, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	return set.Missing(pkg.InterfaceMethods(intr)), nil
+}
+
+// methodsFor returns all methods declared within the file with typeName as receiver. Receivers of
+// types declared in other files have no object, hence they are matched by name.
+func (pkg PackageDeclaration) methodsFor(typeName string) []FuncDeclaration {
+	var methods []FuncDeclaration
+
+	for _, set := range pkg.ObjectFunc {
+		for _, method := range set {
+			if method.RecieverName == typeName {
+				methods = append(methods, method)
+			}
+		}
+	}
+
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Position < methods[j].Position
+	})
+
+	return methods
+}
+
+//===========================================================================================================
+
+// methodCandidate defines a field or method competing for a name within a method set.
+type methodCandidate struct {
+	name     string
+	depth    int
+	indirect bool
+	method   *MethodDeclaration
+}
+
+// declaredMethod returns the MethodDeclaration of a method declared on a type.
+func declaredMethod(fn FuncDeclaration) *MethodDeclaration {
+	return &MethodDeclaration{
+		Name:      fn.FuncName,
+		Signature: signatureOf(fn.Type, fn.Declr),
+		Pointer:   fn.RecieverPointer != nil,
+		Func:      fn,
+		Type:      fn.Type,
+		Declr:     fn.Declr,
+	}
+}
+
+// embeddedMethods returns the fields collected for a struct and the methods of the types embedded
+// by them as candidates of its method set.
+func embeddedMethods(fields []FieldDeclaration, within []Package) []methodCandidate {
+	var candidates []methodCandidate
+
+	for index, field := range fields {
+		candidates = append(candidates, methodCandidate{name: field.FieldName, depth: field.Depth})
+
+		if !field.Embedded || field.Field == nil || field.Declr == nil {
+			continue
+		}
+
+		var indirect bool
+		for _, embedding := range append(append([]FieldDeclaration{}, field.Embeddings...), field) {
+			if _, ok := embedding.Field.Type.(*ast.StarExpr); ok {
+				indirect = true
+			}
+		}
+
+		var promoted MethodSet
+
+		switch expr := unstar(field.Field.Type).(type) {
+		case *ast.Ident:
+			promoted = namedMethods(expr.Name, field.Declr, within[index])
+		case *ast.SelectorExpr:
+			if pkgName, ok := expr.X.(*ast.Ident); ok {
+				if imported, ok := field.Declr.ImportedPackageFor(pkgName.Name); ok {
+					promoted = namedMethods(expr.Sel.Name, nil, imported)
+				}
+			}
+		}
+
+		for _, method := range promoted {
+			method := method
+			method.Promoted = true
+			method.Depth = field.Depth + 1
+			method.Path = field.Path
+
+			candidates = append(candidates, methodCandidate{
+				name:     method.Name,
+				depth:    method.Depth,
+				indirect: indirect,
+				method:   &method,
+			})
+		}
+	}
+
+	return candidates
+}
+
+// namedMethods returns the methods declared on the named type, or declared by it if it is an interface,
+// looking into the file first and then the package.
+func namedMethods(typeName string, declr *PackageDeclaration, pkg Package) MethodSet {
+	if declr != nil {
+		if intr, ok := declr.InterfaceFor(typeName); ok {
+			return interfaceMethods(intr.Interface, intr.Declr, pkg, map[*ast.InterfaceType]bool{})
+		}
+	}
+
+	if intr, ok := pkg.InterfaceFor(typeName); ok {
+		return interfaceMethods(intr.Interface, intr.Declr, pkg, map[*ast.InterfaceType]bool{})
+	}
+
+	methods := pkg.MethodsFor(typeName)
+	if len(pkg.Packages) == 0 && declr != nil {
+		methods = declr.methodsFor(typeName)
+	}
+
+	var set MethodSet
+	for _, method := range methods {
+		set = append(set, *declaredMethod(method))
+	}
+
+	return set
+}
+
+// interfaceMethods returns the methods declared by the interface and the interfaces it embeds.
+func interfaceMethods(intr *ast.InterfaceType, declr *PackageDeclaration, pkg Package, visited map[*ast.InterfaceType]bool) MethodSet {
+	if intr == nil || intr.Methods == nil || visited[intr] {
+		return nil
+	}
+
+	visited[intr] = true
+
+	var set MethodSet
+	for _, field := range intr.Methods.List {
+		if ftype, ok := field.Type.(*ast.FuncType); ok {
+			for _, name := range field.Names {
+				set = append(set, MethodDeclaration{
+					Name:      name.Name,
+					Signature: signatureOf(ftype, declr),
+					Type:      ftype,
+					Declr:     declr,
+				})
+			}
+			continue
+		}
+
+		switch expr := field.Type.(type) {
+		case *ast.Ident:
+			if spec, ok := identDecl(expr).(*ast.TypeSpec); ok {
+				if embedded, ok := spec.Type.(*ast.InterfaceType); ok {
+					set = append(set, interfaceMethods(embedded, declr, pkg, visited)...)
+					continue
+				}
+			}
+
+			if embedded, ok := pkg.InterfaceFor(expr.Name); ok {
+				set = append(set, interfaceMethods(embedded.Interface, embedded.Declr, pkg, visited)...)
+				continue
+			}
+
+			if expr.Name == "error" {
+				set = append(set, MethodDeclaration{
+					Name:      "Error",
+					Signature: "func() string",
+					Type: &ast.FuncType{
+						Params:  &ast.FieldList{},
+						Results: &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent("string")}}},
+					},
+				})
+			}
+		case *ast.SelectorExpr:
+			pkgName, ok := expr.X.(*ast.Ident)
+			if !ok || declr == nil {
+				continue
+			}
+
+			imported, ok := declr.ImportedPackageFor(pkgName.Name)
+			if !ok {
+				continue
+			}
+
+			if embedded, ok := imported.InterfaceFor(expr.Sel.Name); ok {
+				set = append(set, interfaceMethods(embedded.Interface, embedded.Declr, imported, visited)...)
+			}
+		}
+	}
+
+	return set
+}
+
+// dominantMethods returns the methods which dominate all other fields and methods with the same name,
+// where the shallowest wins and names declared more than once at that depth are ambiguous. Methods
+// with pointer receivers are dropped unless the type is a pointer or they are promoted through one.
+func dominantMethods(candidates []methodCandidate, pointer bool) MethodSet {
+	byName := map[string][]methodCandidate{}
+	for _, candidate := range candidates {
+		byName[candidate.name] = append(byName[candidate.name], candidate)
+	}
+
+	var set MethodSet
+	for _, named := range byName {
+		depth := named[0].depth
+		for _, candidate := range named {
+			if candidate.depth < depth {
+				depth = candidate.depth
+			}
+		}
+
+		var shallowest []methodCandidate
+		for _, candidate := range named {
+			if candidate.depth == depth {
+				shallowest = append(shallowest, candidate)
+			}
+		}
+
+		if len(shallowest) != 1 || shallowest[0].method == nil {
+			continue
+		}
+
+		method := shallowest[0]
+		if method.method.Pointer && !pointer && !method.indirect {
+			continue
+		}
+
+		set = append(set, *method.method)
+	}
+
+	sort.Slice(set, func(i, j int) bool {
+		return set[i].Name < set[j].Name
+	})
+
+	return set
+}
+
+//===========================================================================================================
+
+// signatureOf returns the signature of the function type without parameter names, where named types
+// are qualified by the path of the package declaring them so signatures compare across packages.
+func signatureOf(ftype *ast.FuncType, declr *PackageDeclaration) string {
+	if ftype == nil {
+		return "func()"
+	}
+
+	var out bytes.Buffer
+	out.WriteString("func")
+	writeSignature(&out, ftype, declr)
+	return out.String()
+}
+
+func writeSignature(out *bytes.Buffer, ftype *ast.FuncType, declr *PackageDeclaration) {
+	out.WriteString("(")
+	writeFieldTypes(out, ftype.Params, declr)
+	out.WriteString(")")
+
+	if ftype.Results != nil && len(ftype.Results.List) != 0 {
+		out.WriteString(" (")
+		writeFieldTypes(out, ftype.Results, declr)
+		out.WriteString(")")
+	}
+}
+
+func writeFieldTypes(out *bytes.Buffer, list *ast.FieldList, declr *PackageDeclaration) {
+	if list == nil {
+		return
+	}
+
+	var count int
+	for _, field := range list.List {
+		names := len(field.Names)
+		if names == 0 {
+			names = 1
+		}
+
+		for index := 0; index < names; index++ {
+			if count > 0 {
+				out.WriteString(", ")
+			}
+			count++
+
+			writeQualifiedType(out, field.Type, declr)
+		}
+	}
+}
+
+// writeQualifiedType writes the type expression with named types qualified by their package path.
+func writeQualifiedType(out *bytes.Buffer, expr ast.Expr, declr *PackageDeclaration) {
+	switch item := expr.(type) {
+	case *ast.Ident:
+		if types.Universe.Lookup(item.Name) != nil || declr == nil {
+			out.WriteString(item.Name)
+			return
+		}
+
+		path := declr.Path
+		if path == "" {
+			path = declr.Package
+		}
+
+		out.WriteString(path + "." + item.Name)
+	case *ast.SelectorExpr:
+		path := getName(item.X)
+		if declr != nil {
+			if imp, err := declr.ImportFor(path); err == nil {
+				path = imp.Path
+			}
+		}
+
+		out.WriteString(path + "." + item.Sel.Name)
+	case *ast.StarExpr:
+		out.WriteString("*")
+		writeQualifiedType(out, item.X, declr)
+	case *ast.Ellipsis:
+		out.WriteString("...")
+		writeQualifiedType(out, item.Elt, declr)
+	case *ast.ArrayType:
+		out.WriteString("[")
+		if item.Len != nil {
+			out.WriteString(exprString(item.Len))
+		}
+		out.WriteString("]")
+		writeQualifiedType(out, item.Elt, declr)
+	case *ast.MapType:
+		out.WriteString("map[")
+		writeQualifiedType(out, item.Key, declr)
+		out.WriteString("]")
+		writeQualifiedType(out, item.Value, declr)
+	case *ast.ChanType:
+		switch item.Dir {
+		case ast.SEND:
+			out.WriteString("chan<- ")
+		case ast.RECV:
+			out.WriteString("<-chan ")
+		default:
+			out.WriteString("chan ")
+		}
+		writeQualifiedType(out, item.Value, declr)
+	case *ast.FuncType:
+		out.WriteString("func")
+		writeSignature(out, item, declr)
+	case *ast.ParenExpr:
+		writeQualifiedType(out, item.X, declr)
+	case *ast.InterfaceType:
+		if item.Methods == nil || len(item.Methods.List) == 0 {
+			out.WriteString("interface{}")
+			return
+		}
+		out.WriteString(exprString(item))
+	default:
+		out.WriteString(exprString(expr))
+	}
+}
@@ -0,0 +1,146 @@
+package ast_test
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+)
+
+var methodSources = []sourceFile{
+	{Name: "order.go", Source: `package shop
+
+// Base defines common behaviour.
+type Base struct{}
+
+// Name returns the name.
+func (Base) Name() string { return "" }
+
+// Close closes the base.
+func (*Base) Close() error { return nil }
+
+// Logger logs messages.
+type Logger struct{}
+
+// Log logs a message.
+func (l *Logger) Log(msg string, args ...interface{}) {}
+
+// Flush flushes the logger.
+func (l *Logger) Flush() {}
+
+// Shadow shadows Log.
+type Shadow struct {
+	Log int
+}
+
+// Order defines an order.
+type Order struct {
+	Base
+	*Logger
+	Shadow
+	Reader
+}
+
+// Save saves the order.
+func (o *Order) Save(items map[string]*Order, done chan<- bool) error { return nil }
+
+// Saver saves and closes.
+type Saver interface {
+	Save(map[string]*Order, chan<- bool) error
+	Close() error
+}
+
+// Named defines a named error.
+type Named interface {
+	error
+	Name() string
+}
+`},
+	{Name: "reader.go", Source: `package shop
+
+// Reader reads bytes.
+type Reader interface {
+	Read(p []byte) (n int, err error)
+}
+
+// Total returns the total.
+func (o Order) Total() float64 { return 0 }
+`},
+}
+
+// TestMethodSets validates method sets are resolved across files as Go resolves them.
+func TestMethodSets(t *testing.T) {
+	fx := newFixture(t, methodSources...)
+	pkgs, checked := fx.Packages, fx.Check()
+
+	order := checked.Scope().Lookup("Order").Type()
+
+	for _, pointer := range []bool{false, true} {
+		set, err := pkgs[0].MethodSet("Order", pointer)
+		if err != nil {
+			tests.Failed("Should have successfully retrieved method set: %+q", err)
+		}
+
+		typ := order
+		if pointer {
+			typ = types.NewPointer(order)
+		}
+
+		var expected, names []string
+
+		methods := types.NewMethodSet(typ)
+		for index := 0; index < methods.Len(); index++ {
+			expected = append(expected, methods.At(index).Obj().Name())
+		}
+
+		for _, method := range set {
+			names = append(names, method.Name)
+		}
+
+		sort.Strings(expected)
+
+		if strings.Join(names, ",") != strings.Join(expected, ",") {
+			tests.Info("Pointer: %t", pointer)
+			tests.Info("Methods: %s", strings.Join(names, ","))
+			tests.Info("Expected: %s", strings.Join(expected, ","))
+			tests.Failed("Should have successfully resolved method set as Go does")
+		}
+	}
+	tests.Passed("Should have successfully resolved method set as Go does")
+
+	if !pkgs[0].HasFunctionFor(mustStruct(pkgs[0], "Order"), "Total") {
+		tests.Failed("Should have successfully found methods declared in other files")
+	}
+	tests.Passed("Should have successfully found methods declared in other files")
+
+	saver, _ := pkgs[0].InterfaceFor("Saver")
+	named, _ := pkgs[0].InterfaceFor("Named")
+
+	if ok, err := pkgs[0].Implements("Order", false, saver); err != nil || ok {
+		tests.Failed("Should have successfully reported Order does not implement Saver")
+	}
+	tests.Passed("Should have successfully reported Order does not implement Saver")
+
+	if ok, err := pkgs[0].Implements("Order", true, saver); err != nil || !ok {
+		tests.Failed("Should have successfully reported *Order implements Saver")
+	}
+	tests.Passed("Should have successfully reported *Order implements Saver")
+
+	missing, err := pkgs[0].MissingMethods("Order", true, named)
+	if err != nil || len(missing) != 1 || missing[0].Name != "Error" {
+		tests.Info("Missing: %#v", missing)
+		tests.Failed("Should have successfully reported Error as missing from *Order")
+	}
+	tests.Passed("Should have successfully reported Error as missing from *Order")
+}
+
+func mustStruct(pkg ast.Package, name string) ast.StructDeclaration {
+	str, ok := pkg.StructFor(name)
+	if !ok {
+		tests.Failed("Should have successfully found %s struct", name)
+	}
+	return str
+}
@@ -0,0 +1,140 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+
+	"github.com/influx6/moz/gen"
+)
+
+// MockAnnotation defines the annotation which marks an interface to have a mock implementation
+// generated by MockAnnotationGenerator.
+const MockAnnotation = "@mock"
+
+// MockFor returns the declaration of a struct with the giving name implementing the interface
+// within its package, along with the imports required by it. Methods of embedded interfaces,
+// whether declared in the package, imported packages or the predeclared error, are included,
+// with the types of imported interfaces qualified by their package.
+func MockFor(intr InterfaceDeclaration, name string, pkg Package) (gen.MockDeclr, ImportSet, error) {
+	if intr.Interface == nil || intr.Object == nil {
+		return gen.MockDeclr{}, nil, errors.New("InterfaceDeclaration has no interface type")
+	}
+
+	pkgPath := intr.Path
+	if intr.Declr != nil {
+		pkgPath = intr.Declr.Path
+	}
+
+	imports := ImportSet{}
+	imports.Add("sync", "sync")
+
+	methods := pkg.InterfaceMethods(intr)
+
+	declared := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		declared[method.Name] = true
+	}
+
+	mock := gen.Mock(name, intr.Object.Name.Name)
+
+	for _, method := range methods {
+		if method.Type == nil {
+			return gen.MockDeclr{}, nil, fmt.Errorf("Method %q of interface %q has no function type", method.Name, intr.Object.Name.Name)
+		}
+
+		for _, suffix := range []string{"Func", "Calls", "Count"} {
+			if declared[method.Name+suffix] {
+				return gen.MockDeclr{}, nil, fmt.Errorf("Mock of interface %q can not declare %q for method %q", intr.Object.Name.Name, method.Name+suffix, method.Name)
+			}
+		}
+
+		// Interfaces embedding the same interface more than once declare its methods for each.
+		if len(mock.Methods) != 0 && mock.Methods[len(mock.Methods)-1].Name == method.Name {
+			continue
+		}
+
+		var args []gen.MockArgDeclr
+		for _, field := range fieldsOf(method.Type.Params) {
+			arg := gen.MockArgDeclr{Name: field.Name}
+
+			if ellipsis, ok := field.Type.(*ast.Ellipsis); ok {
+				arg.Variadic = true
+				arg.Type = imports.Qualify(ellipsis.Elt, method.Declr, pkgPath)
+			} else {
+				arg.Type = imports.Qualify(field.Type, method.Declr, pkgPath)
+			}
+
+			args = append(args, arg)
+		}
+
+		var returns []string
+		for _, field := range fieldsOf(method.Type.Results) {
+			returns = append(returns, imports.Qualify(field.Type, method.Declr, pkgPath))
+		}
+
+		mock.Methods = append(mock.Methods, gen.MockMethod(method.Name, args, returns...))
+	}
+
+	return mock, imports, nil
+}
+
+// MockAnnotationGenerator defines a InterfaceAnnotationGenerator for the @mock annotation, which writes
+// the mock declared by MockFor into a "<interface>_mock.go" file of the interface's package. The mock
+// is named "<Interface>Mock" unless given with @mock(name => FakeStore).
+func MockAnnotationGenerator(toDir string, an AnnotationDeclaration, intr InterfaceDeclaration, pkgDeclr PackageDeclaration, pkg Package) ([]gen.WriteDirective, error) {
+	if intr.Object == nil {
+		return nil, errors.New("InterfaceDeclaration has no type specification")
+	}
+
+	name := an.Param("name")
+	if name == "" {
+		name = intr.Object.Name.Name + "Mock"
+	}
+
+	mock, imports, err := MockFor(intr, name, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	mock.Comments = gen.Text(fmt.Sprintf("// %s defines a mock implementation of %s, whose methods call the function\n// field of the same name and record each call.", name, intr.Object.Name.Name))
+
+	return []gen.WriteDirective{
+		{
+			FileName: fmt.Sprintf("%s_mock.go", snakeCase(intr.Object.Name.Name)),
+			Writer: gen.Package(
+				gen.Name(pkgDeclr.Package),
+				gen.Imports(imports.Items()...),
+				mock,
+			),
+		},
+	}, nil
+}
+
+// namedField defines a single parameter or result of a field list.
+type namedField struct {
+	Name string
+	Type ast.Expr
+}
+
+// fieldsOf returns the parameters or results of the field list, with fields declaring several
+// names expanded into one per name.
+func fieldsOf(list *ast.FieldList) []namedField {
+	if list == nil {
+		return nil
+	}
+
+	var fields []namedField
+	for _, field := range list.List {
+		if len(field.Names) == 0 {
+			fields = append(fields, namedField{Type: field.Type})
+			continue
+		}
+
+		for _, name := range field.Names {
+			fields = append(fields, namedField{Name: name.Name, Type: field.Type})
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,120 @@
+package ast_test
+
+import (
+	goast "go/ast"
+	"go/types"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+)
+
+var mockSource = `package mof
+
+import (
+	ctx "context"
+	"io"
+	"net/http"
+)
+
+// Ignitable defines a interface to ignite a Mof.
+type Ignitable interface {
+	Ignite() string
+}
+
+// MofInitable defines a interface for a Mof.
+// @mock
+type MofInitable interface {
+	Ignitable
+	io.ReadCloser
+	http.Handler
+	error
+	Crunch() (cr string)
+	Close() error
+	Location(string) (GPSLoc, error)
+	Log(ctx ctx.Context, format string, args ...interface{})
+	MapsIn(string) (map[string]*GPSLoc, error)
+	MapsOut(string) (map[*GPSLoc]string, error)
+	Drop() (*GPSLoc, *[]byte, *[5]byte)
+	Chans() (chan struct{}, chan<- string, <-chan []byte, chan (<-chan int))
+	Bob() chan chan struct{}
+	Apply(fn func(int) (string, error), m, n int) func() error
+	Ignore(_ string, _ int)
+}
+
+// GPSLoc defines a struct to hold long and lat values for a gps location.
+type GPSLoc struct {
+	Lat  float64
+	Long float64
+}
+`
+
+// TestMockAnnotationGenerator validates @mock annotated interfaces generate compilable mocks.
+func TestMockAnnotationGenerator(t *testing.T) {
+	fx := newFixture(t, sourceFile{Name: "mof.go", Source: mockSource})
+	pkgs := fx.Packages
+
+	declr := pkgs[0].Packages[0]
+	intr, err := ast.FindInterfaceType(declr, "MofInitable")
+	if err != nil {
+		tests.Failed("Should have successfully found interface: %+q", err)
+	}
+
+	methods := map[string]ast.FunctionDefinition{}
+	for _, method := range intr.Methods(&declr) {
+		methods[method.Name] = method
+	}
+
+	if methods["Log"].GetArgsAt(2).Type != "...interface{}" || methods["Apply"].GetArgsAt(0).FuncType == nil {
+		tests.Failed("Should have successfully described variadic and function parameters")
+	}
+
+	// Type of channels keeps naming their element, while ChanType holds the full channel type.
+	bob, chans := methods["Bob"].GetReturnsAt(0), methods["Chans"].GetReturnsAt(2)
+	if bob.Type != "chan struct{}" || chans.Type != "[]byte" {
+		tests.Info("Bob: %s", bob.Type)
+		tests.Info("Chans: %s", chans.Type)
+		tests.Failed("Should have successfully kept element type of channel returns")
+	}
+
+	if bob.ChanType == nil || chans.ChanType == nil {
+		tests.Failed("Should have successfully described channel returns")
+	}
+
+	if dir := methods["Chans"].GetReturnsAt(1).ChanType.Dir; dir != goast.SEND {
+		tests.Failed("Should have successfully kept direction of channel returns")
+	}
+	tests.Passed("Should have successfully described interface methods")
+
+	registry := ast.NewAnnotationRegistry()
+	registry.RegisterInterfaceType(ast.MockAnnotation, ast.MockAnnotationGenerator)
+
+	directives, err := registry.ParseDeclr(pkgs[0], declr, fx.Dir)
+	if err != nil {
+		tests.Failed("Should have successfully generated mock directives: %+q", err)
+	}
+
+	if len(directives) != 1 || directives[0].FileName != "mof_initable_mock.go" {
+		tests.Failed("Should have successfully generated mock file")
+	}
+	tests.Passed("Should have successfully generated mock file")
+
+	checked := fx.Check(directiveFiles(directives)...)
+	tests.Passed("Should have successfully rendered compilable mock file")
+
+	mock := checked.Scope().Lookup("MofInitableMock").Type()
+	set := types.NewMethodSet(types.NewPointer(mock))
+	for _, name := range []string{"ServeHTTP", "Read", "Error", "IgniteCalls", "LogCount"} {
+		if set.Lookup(checked, name) == nil {
+			tests.Info("Method: %s", name)
+			tests.Failed("Should have successfully generated mock methods")
+		}
+	}
+	tests.Passed("Should have successfully generated mock methods")
+
+	call := checked.Scope().Lookup("MofInitableMockLogCall").Type().Underlying().(*types.Struct)
+	if call.NumFields() != 3 || call.Field(0).Name() != "Ctx" || call.Field(2).Type().String() != "[]interface{}" {
+		tests.Failed("Should have successfully recorded call arguments")
+	}
+	tests.Passed("Should have successfully recorded call arguments")
+}
@@ -0,0 +1,146 @@
+package ast
+
+import (
+	"go/ast"
+	"go/types"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/influx6/moz/gen"
+)
+
+// ImportSet defines the imports required by generated source, holding the name each import
+// path is referred by.
+type ImportSet map[string]string
+
+// Add records the import path to be referred by the giving name, returning the name to use,
+// which is numbered if another path already uses the name.
+func (is ImportSet) Add(importPath string, name string) string {
+	if existing, ok := is[importPath]; ok {
+		return existing
+	}
+
+	used := make(map[string]bool, len(is))
+	for _, other := range is {
+		used[other] = true
+	}
+
+	unique := name
+	for index := 2; used[unique]; index++ {
+		unique = name + strconv.Itoa(index)
+	}
+
+	is[importPath] = unique
+	return unique
+}
+
+// Items returns the imports sorted by path, named only where the name differs from the last
+// element of the path.
+func (is ImportSet) Items() []gen.ImportItemDeclr {
+	paths := make([]string, 0, len(is))
+	for importPath := range is {
+		paths = append(paths, importPath)
+	}
+
+	sort.Strings(paths)
+
+	items := make([]gen.ImportItemDeclr, 0, len(paths))
+	for _, importPath := range paths {
+		var namespace string
+		if name := is[importPath]; name != path.Base(importPath) {
+			namespace = name
+		}
+
+		items = append(items, gen.Import(importPath, namespace))
+	}
+
+	return items
+}
+
+// Qualify returns the source of the type expression declared within declr as written by a file
+// of the package with the import path pkgPath. Named types of other packages are qualified by
+// the name recorded for their import path, while those of the package itself are not.
+func (is ImportSet) Qualify(expr ast.Expr, declr *PackageDeclaration, pkgPath string) string {
+	return exprString(is.qualify(expr, declr, pkgPath))
+}
+
+// qualify returns a copy of the type expression with its named types qualified for pkgPath.
+func (is ImportSet) qualify(expr ast.Expr, declr *PackageDeclaration, pkgPath string) ast.Expr {
+	switch item := expr.(type) {
+	case *ast.Ident:
+		if declr == nil || declr.Path == pkgPath || types.Universe.Lookup(item.Name) != nil {
+			return item
+		}
+
+		return &ast.SelectorExpr{X: ast.NewIdent(is.Add(declr.Path, declr.Package)), Sel: ast.NewIdent(item.Name)}
+	case *ast.SelectorExpr:
+		pkgName, ok := item.X.(*ast.Ident)
+		if !ok || declr == nil {
+			return item
+		}
+
+		imp, err := declr.ImportFor(pkgName.Name)
+		if err != nil {
+			return item
+		}
+
+		if imp.Path == pkgPath {
+			return ast.NewIdent(item.Sel.Name)
+		}
+
+		return &ast.SelectorExpr{X: ast.NewIdent(is.Add(imp.Path, pkgName.Name)), Sel: ast.NewIdent(item.Sel.Name)}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: is.qualify(item.X, declr, pkgPath)}
+	case *ast.ParenExpr:
+		return &ast.ParenExpr{X: is.qualify(item.X, declr, pkgPath)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: is.qualify(item.Elt, declr, pkgPath)}
+	case *ast.ArrayType:
+		array := &ast.ArrayType{Elt: is.qualify(item.Elt, declr, pkgPath)}
+		if item.Len != nil {
+			array.Len = is.qualify(item.Len, declr, pkgPath)
+		}
+		return array
+	case *ast.MapType:
+		return &ast.MapType{Key: is.qualify(item.Key, declr, pkgPath), Value: is.qualify(item.Value, declr, pkgPath)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: item.Dir, Value: is.qualify(item.Value, declr, pkgPath)}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: is.qualifyFields(item.Params, declr, pkgPath), Results: is.qualifyFields(item.Results, declr, pkgPath)}
+	case *ast.InterfaceType:
+		if item.Methods == nil || len(item.Methods.List) == 0 {
+			return item
+		}
+		return &ast.InterfaceType{Methods: is.qualifyFields(item.Methods, declr, pkgPath)}
+	case *ast.StructType:
+		if item.Fields == nil || len(item.Fields.List) == 0 {
+			return item
+		}
+		return &ast.StructType{Fields: is.qualifyFields(item.Fields, declr, pkgPath)}
+	case *ast.IndexExpr:
+		return &ast.IndexExpr{X: is.qualify(item.X, declr, pkgPath), Index: is.qualify(item.Index, declr, pkgPath)}
+	case *ast.IndexListExpr:
+		indices := make([]ast.Expr, 0, len(item.Indices))
+		for _, index := range item.Indices {
+			indices = append(indices, is.qualify(index, declr, pkgPath))
+		}
+		return &ast.IndexListExpr{X: is.qualify(item.X, declr, pkgPath), Indices: indices}
+	}
+
+	return expr
+}
+
+// qualifyFields returns a copy of the field list with the types of its fields qualified for pkgPath.
+func (is ImportSet) qualifyFields(list *ast.FieldList, declr *PackageDeclaration, pkgPath string) *ast.FieldList {
+	if list == nil {
+		return nil
+	}
+
+	fields := make([]*ast.Field, 0, len(list.List))
+	for _, field := range list.List {
+		fields = append(fields, &ast.Field{Names: field.Names, Type: is.qualify(field.Type, declr, pkgPath), Tag: field.Tag})
+	}
+
+	return &ast.FieldList{List: fields}
+}
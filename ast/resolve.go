@@ -0,0 +1,208 @@
+package ast
+
+import (
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/influx6/faux/metrics"
+	"github.com/influx6/gobuild/build"
+)
+
+// DefaultImportResolver defines the ImportResolver used to load packages which were not loaded along
+// with a PackageDeclaration, such as standard library packages.
+var DefaultImportResolver = NewImportResolver(metrics.New(), build.Default)
+
+// ImportResolver loads packages on demand by import path or directory, caching each package by the
+// directory it is found in, so every package is parsed at most once.
+type ImportResolver struct {
+	log  metrics.Metrics
+	ctx  build.Context
+	ml   sync.Mutex
+	pkgs map[string]*resolvedImport
+}
+
+// resolvedImport defines a package loaded once for a directory.
+type resolvedImport struct {
+	once sync.Once
+	pkg  Package
+	err  error
+}
+
+// NewImportResolver returns a new ImportResolver which locates packages with the giving build.Context.
+func NewImportResolver(log metrics.Metrics, ctx build.Context) *ImportResolver {
+	return &ImportResolver{
+		log:  log,
+		ctx:  ctx,
+		pkgs: make(map[string]*resolvedImport),
+	}
+}
+
+// Package returns the Package for the import path as imported from a file within srcDir, which
+// is used to locate vendored packages and may be empty.
+func (ir *ImportResolver) Package(importPath string, srcDir string) (Package, error) {
+	buildPkg, err := ir.ctx.Import(importPath, srcDir, build.FindOnly)
+	if err != nil {
+		return Package{}, err
+	}
+
+	return ir.load(importPath, buildPkg.Dir)
+}
+
+// PackageDir returns the Package declared within the giving directory.
+func (ir *ImportResolver) PackageDir(dir string) (Package, error) {
+	importPath, err := relativeToSrc(dir)
+	if err != nil || strings.HasPrefix(importPath, "..") {
+		importPath = ""
+	}
+
+	return ir.load(filepath.ToSlash(importPath), dir)
+}
+
+// load returns the cached Package for the directory, parsing it on first use.
+func (ir *ImportResolver) load(importPath string, dir string) (Package, error) {
+	ir.ml.Lock()
+	resolved, ok := ir.pkgs[dir]
+	if !ok {
+		resolved = new(resolvedImport)
+		ir.pkgs[dir] = resolved
+	}
+	ir.ml.Unlock()
+
+	resolved.once.Do(func() {
+		pkgs, err := FilteredPackageWithBuildCtx(ir.log, dir, ir.ctx)
+		if err != nil {
+			resolved.err = err
+			return
+		}
+
+		for _, pkg := range pkgs {
+			if len(pkg.Packages) == 0 {
+				continue
+			}
+
+			if importPath != "" {
+				pkg.Path = importPath
+			}
+
+			pkg.Dir = dir
+			resolved.pkg = pkg
+			return
+		}
+
+		resolved.err = fmt.Errorf("No package found in %q", dir)
+	})
+
+	return resolved.pkg, resolved.err
+}
+
+//===========================================================================================================
+
+// ResolvedType defines the declaration of a named type within the package declaring it, where only
+// one of Struct, Interface or Type is set.
+type ResolvedType struct {
+	Name      string
+	Package   Package
+	Struct    *StructDeclaration
+	Interface *InterfaceDeclaration
+	Type      *TypeDeclaration
+}
+
+// Spec returns the ast.TypeSpec declaring the type.
+func (rt ResolvedType) Spec() *ast.TypeSpec {
+	switch {
+	case rt.Struct != nil:
+		return rt.Struct.Object
+	case rt.Interface != nil:
+		return rt.Interface.Object
+	case rt.Type != nil:
+		return rt.Type.Object
+	}
+	return nil
+}
+
+// ResolveType returns the declaration of the named type the expression refers to within the file,
+// where pointers are dereferenced. Types declared in other files of the package or imported
+// packages, including the standard library, are loaded on demand with DefaultImportResolver.
+func (pkg PackageDeclaration) ResolveType(expr ast.Expr) (ResolvedType, error) {
+	switch item := unstar(expr).(type) {
+	case *ast.ParenExpr:
+		return pkg.ResolveType(item.X)
+	case *ast.Ident:
+		if resolved, ok := resolveIn(Package{Name: pkg.Package, Path: pkg.Path, Packages: []PackageDeclaration{pkg}}, item.Name); ok {
+			return resolved, nil
+		}
+
+		own, err := DefaultImportResolver.PackageDir(filepath.Dir(pkg.FilePath))
+		if err != nil {
+			return ResolvedType{}, err
+		}
+
+		if resolved, ok := resolveIn(own, item.Name); ok {
+			return resolved, nil
+		}
+
+		return ResolvedType{}, fmt.Errorf("Type %q not found in package %q", item.Name, pkg.Package)
+	case *ast.SelectorExpr:
+		pkgName, ok := item.X.(*ast.Ident)
+		if !ok {
+			return ResolvedType{}, fmt.Errorf("Unsupported selector %q", exprString(item))
+		}
+
+		imported, ok := pkg.ImportedPackageFor(pkgName.Name)
+		if !ok {
+			return ResolvedType{}, fmt.Errorf("Package %q not found for %q", pkgName.Name, exprString(item))
+		}
+
+		if resolved, ok := resolveIn(imported, item.Sel.Name); ok {
+			return resolved, nil
+		}
+
+		return ResolvedType{}, fmt.Errorf("Type %q not found in package %q", item.Sel.Name, imported.Path)
+	}
+
+	return ResolvedType{}, fmt.Errorf("Expression %q is not a named type", exprString(expr))
+}
+
+// Resolve returns the declaration of the named type of the argument, as declared in the giving
+// PackageDeclaration.
+func (a ArgType) Resolve(pkg *PackageDeclaration) (ResolvedType, error) {
+	if a.Expr == nil || pkg == nil {
+		return ResolvedType{}, fmt.Errorf("ArgType %q has no type expression or PackageDeclaration", a.Name)
+	}
+
+	return pkg.ResolveType(a.Expr)
+}
+
+// ResolveType returns the declaration of the named type of the field.
+func (f FieldDeclaration) ResolveType() (ResolvedType, error) {
+	if f.Field == nil || f.Declr == nil {
+		return ResolvedType{}, fmt.Errorf("Field %q has no PackageDeclaration", f.FieldName)
+	}
+
+	return f.Declr.ResolveType(f.Field.Type)
+}
+
+// resolveIn returns the declaration of the type with the giving name within the package.
+func resolveIn(pkg Package, typeName string) (ResolvedType, bool) {
+	resolved := ResolvedType{Name: typeName, Package: pkg}
+
+	if str, ok := pkg.StructFor(typeName); ok {
+		resolved.Struct = &str
+		return resolved, true
+	}
+
+	if intr, ok := pkg.InterfaceFor(typeName); ok {
+		resolved.Interface = &intr
+		return resolved, true
+	}
+
+	if typ, ok := pkg.TypeFor(typeName); ok {
+		resolved.Type = &typ
+		return resolved, true
+	}
+
+	return ResolvedType{}, false
+}
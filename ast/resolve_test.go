@@ -0,0 +1,104 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+)
+
+var resolveSources = []sourceFile{
+	{Name: "event.go", Source: `package events
+
+import (
+	"io"
+	stdtime "time"
+)
+
+// Event defines an event.
+type Event struct {
+	Source  io.Reader
+	Created stdtime.Time
+	Timer   *stdtime.Timer
+	Kind    Kind
+	Entry
+}
+`},
+	{Name: "kind.go", Source: `package events
+
+// Kind defines the kind of an event.
+type Kind string
+
+// Entry defines a log entry.
+type Entry struct {
+	Line string
+}
+`},
+}
+
+// TestResolveTypes validates types declared in other files and imported packages are resolved.
+func TestResolveTypes(t *testing.T) {
+	pkgs := newFixture(t, resolveSources...).Packages
+
+	event, ok := pkgs[0].StructFor("Event")
+	if !ok {
+		tests.Failed("Should have successfully found Event struct")
+	}
+
+	fields, err := event.Fields()
+	if err != nil {
+		tests.Failed("Should have successfully retrieved Event fields: %+q", err)
+	}
+
+	resolved := map[string]ast.ResolvedType{}
+	for _, field := range fields {
+		res, err := field.ResolveType()
+		if err != nil {
+			tests.Info("Field: %s", field.FieldName)
+			tests.Failed("Should have successfully resolved field type: %+q", err)
+		}
+
+		if argRes, err := field.Arg.Resolve(field.Declr); err != nil || argRes.Spec() != res.Spec() {
+			tests.Failed("Should have successfully resolved argument type as field type")
+		}
+
+		resolved[field.FieldName] = res
+	}
+	tests.Passed("Should have successfully resolved field types")
+
+	if res := resolved["Source"]; res.Interface == nil || res.Package.Path != "io" || res.Spec().Name.Name != "Reader" {
+		tests.Info("Resolved: %#v", res)
+		tests.Failed("Should have successfully resolved io.Reader interface")
+	}
+	tests.Passed("Should have successfully resolved io.Reader interface")
+
+	if res := resolved["Created"]; res.Struct == nil || res.Package.Path != "time" {
+		tests.Info("Resolved: %#v", res)
+		tests.Failed("Should have successfully resolved aliased time.Time struct")
+	}
+	tests.Passed("Should have successfully resolved aliased time.Time struct")
+
+	if res := resolved["Timer"]; res.Struct == nil || res.Spec().Name.Name != "Timer" {
+		tests.Failed("Should have successfully resolved pointer to time.Timer struct")
+	}
+	tests.Passed("Should have successfully resolved pointer to time.Timer struct")
+
+	if resolved["Kind"].Type == nil || resolved["Entry"].Struct == nil {
+		tests.Failed("Should have successfully resolved types declared in other files")
+	}
+	tests.Passed("Should have successfully resolved types declared in other files")
+
+	first, err := ast.DefaultImportResolver.Package("time", "")
+	if err != nil {
+		tests.Failed("Should have successfully loaded time package: %+q", err)
+	}
+
+	second, _ := ast.DefaultImportResolver.Package("time", "")
+	firstTime, _ := first.StructFor("Time")
+	secondTime, _ := second.StructFor("Time")
+
+	if firstTime.Struct == nil || firstTime.Struct != secondTime.Struct || firstTime.Struct != resolved["Created"].Struct.Struct {
+		tests.Failed("Should have successfully reused cached package")
+	}
+	tests.Passed("Should have successfully reused cached package")
+}
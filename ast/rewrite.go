@@ -0,0 +1,334 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/influx6/moz/gen"
+)
+
+//===========================================================================================================
+
+// SourceSpan defines the region of a source file occupied by a giving declaration.
+// From and Length mark the declaration itself, while Leading marks the offset where
+// the declaration's doc comments begin, which equals From when it has none.
+type SourceSpan struct {
+	FilePath string
+	From     int
+	Length   int
+	Leading  int
+	Source   string
+}
+
+// End returns the offset just after the last byte of the span.
+func (sp SourceSpan) End() int {
+	return sp.From + sp.Length
+}
+
+// Replace returns a RewriteDirective which replaces the declaration within the span
+// with the content of the provided writer. Doc comments of the declaration are kept.
+func (sp SourceSpan) Replace(w io.WriterTo) gen.RewriteDirective {
+	return gen.RewriteDirective{
+		FilePath: sp.FilePath,
+		From:     sp.From,
+		Length:   sp.Length,
+		Original: sp.Source,
+		Writer:   w,
+	}
+}
+
+// InsertBefore returns a RewriteDirective which inserts the content of the provided writer
+// before the declaration within the span and its doc comments.
+func (sp SourceSpan) InsertBefore(w io.WriterTo) gen.RewriteDirective {
+	return gen.RewriteDirective{
+		FilePath: sp.FilePath,
+		From:     sp.Leading,
+		Writer:   w,
+	}
+}
+
+// InsertAfter returns a RewriteDirective which inserts the content of the provided writer
+// right after the declaration within the span.
+func (sp SourceSpan) InsertAfter(w io.WriterTo) gen.RewriteDirective {
+	return gen.RewriteDirective{
+		FilePath: sp.FilePath,
+		From:     sp.End(),
+		Writer:   w,
+	}
+}
+
+// newSourceSpan returns a SourceSpan for the node, where base is the position of the declaration
+// found at the from offset with the provided source. It relies on the fact that positions within
+// a single file are offsets from the base of that file.
+func newSourceSpan(filePath string, from int, source string, base token.Pos, node ast.Node, doc *ast.CommentGroup) SourceSpan {
+	start := from + int(node.Pos()-base)
+	end := from + int(node.End()-base)
+
+	span := SourceSpan{
+		FilePath: filePath,
+		From:     start,
+		Length:   end - start,
+		Leading:  start,
+	}
+
+	if low, high := start-from, end-from; low >= 0 && high <= len(source) {
+		span.Source = source[low:high]
+	}
+
+	if doc != nil {
+		span.Leading = from + int(doc.Pos()-base)
+	}
+
+	return span
+}
+
+// genDeclSpan returns the SourceSpan for a spec of a general declaration, which is
+// the spec itself if the declaration groups several specs in parenthesis.
+func genDeclSpan(filePath string, from int, length int, source string, genObj *ast.GenDecl, spec ast.Spec, specDoc *ast.CommentGroup) SourceSpan {
+	if genObj == nil {
+		return SourceSpan{FilePath: filePath, From: from, Length: length, Leading: from, Source: source}
+	}
+
+	if genObj.Lparen.IsValid() && spec != nil {
+		return newSourceSpan(filePath, from, source, genObj.Pos(), spec, specDoc)
+	}
+
+	return newSourceSpan(filePath, from, source, genObj.Pos(), genObj, genObj.Doc)
+}
+
+// Span returns the SourceSpan of the struct declaration.
+func (str StructDeclaration) Span() SourceSpan {
+	var doc *ast.CommentGroup
+	if str.Object != nil {
+		doc = str.Object.Doc
+	}
+	return genDeclSpan(str.FilePath, str.From, str.Length, str.Source, str.GenObj, str.Object, doc)
+}
+
+// Span returns the SourceSpan of the interface declaration.
+func (i InterfaceDeclaration) Span() SourceSpan {
+	var doc *ast.CommentGroup
+	if i.Object != nil {
+		doc = i.Object.Doc
+	}
+	return genDeclSpan(i.FilePath, i.From, i.Length, i.Source, i.GenObj, i.Object, doc)
+}
+
+// Span returns the SourceSpan of the type declaration.
+func (ty TypeDeclaration) Span() SourceSpan {
+	var doc *ast.CommentGroup
+	if ty.Object != nil {
+		doc = ty.Object.Doc
+	}
+	return genDeclSpan(ty.FilePath, ty.From, ty.Length, ty.Source, ty.GenObj, ty.Object, doc)
+}
+
+// Span returns the SourceSpan of the variable declaration.
+func (vr VariableDeclaration) Span() SourceSpan {
+	var doc *ast.CommentGroup
+	if vr.Object != nil {
+		doc = vr.Object.Doc
+	}
+	return genDeclSpan(vr.FilePath, vr.From, vr.Length, vr.Source, vr.GenObj, vr.Object, doc)
+}
+
+// Span returns the SourceSpan of the function declaration.
+func (fun FuncDeclaration) Span() SourceSpan {
+	if fun.FuncDeclr == nil {
+		return SourceSpan{FilePath: fun.FilePath, From: fun.From, Length: fun.Length, Leading: fun.From, Source: fun.Source}
+	}
+
+	return newSourceSpan(fun.FilePath, fun.From, fun.Source, fun.FuncDeclr.Pos(), fun.FuncDeclr, fun.FuncDeclr.Doc)
+}
+
+// AddMethod returns a RewriteDirective which adds the content of the provided writer,
+// expected to be a method declaration, right after the struct declaration.
+func (str StructDeclaration) AddMethod(method io.WriterTo) gen.RewriteDirective {
+	return gen.RewriteDirective{
+		FilePath: str.FilePath,
+		From:     str.From + str.Length,
+		Writer:   gen.WritersTo{gen.Text("\n\n"), method},
+	}
+}
+
+// AddTag returns a RewriteDirective which adds the key and value to the tag of the field
+// with the giving name, creating the tag if the field has none. If the tag already holds
+// the key, the returned directive leaves the tag untouched.
+func (str StructDeclaration) AddTag(fieldName string, key string, value string) (gen.RewriteDirective, error) {
+	if str.Struct == nil || str.GenObj == nil {
+		return gen.RewriteDirective{}, fmt.Errorf("struct %q has no parsed declaration", str.Name)
+	}
+
+	base := str.GenObj.Pos()
+
+	for _, field := range str.Struct.Fields.List {
+		if !hasFieldName(field, fieldName) {
+			continue
+		}
+
+		if field.Tag == nil {
+			return gen.RewriteDirective{
+				FilePath: str.FilePath,
+				From:     str.From + int(field.Type.End()-base),
+				Writer:   gen.Text(" " + quoteTag(fmt.Sprintf("%s:%s", key, strconv.Quote(value)))),
+			}, nil
+		}
+
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return gen.RewriteDirective{}, fmt.Errorf("field %q has invalid tag: %+q", fieldName, err)
+		}
+
+		if _, ok := reflect.StructTag(tag).Lookup(key); !ok {
+			tag = strings.TrimSpace(fmt.Sprintf("%s %s:%s", tag, key, strconv.Quote(value)))
+		}
+
+		return gen.RewriteDirective{
+			FilePath: str.FilePath,
+			From:     str.From + int(field.Tag.Pos()-base),
+			Length:   len(field.Tag.Value),
+			Original: field.Tag.Value,
+			Writer:   gen.Text(quoteTag(tag)),
+		}, nil
+	}
+
+	return gen.RewriteDirective{}, fmt.Errorf("struct %q has no field %q", str.Name, fieldName)
+}
+
+// hasFieldName returns true/false if the field declares the giving name, where embedded fields
+// are named by their type.
+func hasFieldName(field *ast.Field, name string) bool {
+	if len(field.Names) == 0 {
+		return getRealIdentName(field.Type) == name
+	}
+
+	for _, ident := range field.Names {
+		if ident.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// quoteTag returns the tag as a raw string literal, falling back to an interpreted
+// string literal when the tag itself holds a backquote.
+func quoteTag(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+//===========================================================================================================
+
+// ApplyRewrites applies all provided RewriteDirective to the files they target. All edits
+// for a file are applied against the file's content as it was before any of them, so
+// offsets retrieved from a single parse stay valid no matter how many edits are made.
+// Go source files are formatted after editing.
+func ApplyRewrites(rewrites ...gen.RewriteDirective) error {
+	var files []string
+	byFile := make(map[string][]gen.RewriteDirective)
+
+	for _, rw := range rewrites {
+		if rw.FilePath == "" {
+			return fmt.Errorf("RewriteDirective has no file path attached")
+		}
+
+		if _, ok := byFile[rw.FilePath]; !ok {
+			files = append(files, rw.FilePath)
+		}
+
+		byFile[rw.FilePath] = append(byFile[rw.FilePath], rw)
+	}
+
+	for _, file := range files {
+		if err := rewriteFile(file, byFile[file]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rewriteFile(file string, rewrites []gen.RewriteDirective) error {
+	stat, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	content, err := RewriteSource(src, rewrites...)
+	if err != nil {
+		return fmt.Errorf("RewriteError: %q: %+q", file, err)
+	}
+
+	if strings.HasSuffix(file, ".go") {
+		formatted, err := format.Source(content)
+		if err != nil {
+			return fmt.Errorf("RewriteError: %q produced invalid source: %+q", file, err)
+		}
+
+		content = formatted
+	}
+
+	return ioutil.WriteFile(file, content, stat.Mode().Perm())
+}
+
+// RewriteSource returns a copy of the source with all provided RewriteDirective applied.
+// Edits are ordered by offset, where inserts at the same offset keep the order they were
+// provided in. Edits which overlap one another are rejected.
+func RewriteSource(src []byte, rewrites ...gen.RewriteDirective) ([]byte, error) {
+	ordered := make([]gen.RewriteDirective, len(rewrites))
+	copy(ordered, rewrites)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].From < ordered[j].From
+	})
+
+	var cursor int
+	var out bytes.Buffer
+
+	for _, rw := range ordered {
+		end := rw.From + rw.Length
+
+		if rw.From < 0 || rw.Length < 0 || end > len(src) {
+			return nil, fmt.Errorf("edit at [%d:%d] is out of source range of %d bytes", rw.From, end, len(src))
+		}
+
+		if rw.From < cursor {
+			return nil, fmt.Errorf("edit at [%d:%d] overlaps a previous edit ending at %d", rw.From, end, cursor)
+		}
+
+		if rw.Original != "" && string(src[rw.From:end]) != rw.Original {
+			return nil, fmt.Errorf("edit at [%d:%d] targets content which has changed since parsing", rw.From, end)
+		}
+
+		out.Write(src[cursor:rw.From])
+
+		if rw.Writer != nil {
+			if _, err := rw.Writer.WriteTo(&out); err != nil && err != io.EOF {
+				return nil, err
+			}
+		}
+
+		cursor = end
+	}
+
+	out.Write(src[cursor:])
+	return out.Bytes(), nil
+}
@@ -0,0 +1,126 @@
+package ast_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+	"github.com/influx6/moz/gen"
+)
+
+var rewriteSource = `package store
+
+// User defines a user record.
+// @persist
+type User struct {
+	Name string
+	Age  int ` + "`db:\"age\"`" + `
+}
+
+// Config holds store settings.
+type Config struct {
+	Addr string // address to bind.
+}
+`
+
+var rewriteExpected = `package store
+
+// Record defines a marker for persisted types.
+type Record interface{}
+
+// User defines a user record.
+// @persist
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+	Age  int    ` + "`db:\"age\" json:\"age\"`" + `
+}
+
+// Table returns the table name of the user.
+func (u User) Table() string { return "users" }
+
+// Config holds store settings.
+type Config struct {
+	Addr string // address to bind.
+	Port int
+}
+`
+
+// TestStructRewrites validates multiple edits to a single file are applied against the parsed
+// offsets without drift, keeping the remaining source and comments intact.
+func TestStructRewrites(t *testing.T) {
+	fx := newFixture(t, sourceFile{Name: "store.go", Source: rewriteSource})
+	pkgs := fx.Packages
+
+	user, ok := pkgs[0].StructFor("User")
+	if !ok {
+		tests.Failed("Should have successfully found User struct")
+	}
+	tests.Passed("Should have successfully found User struct")
+
+	config, ok := pkgs[0].StructFor("Config")
+	if !ok {
+		tests.Failed("Should have successfully found Config struct")
+	}
+	tests.Passed("Should have successfully found Config struct")
+
+	nameTag, err := user.AddTag("Name", "json", "name")
+	if err != nil {
+		tests.Failed("Should have successfully created tag directive for Name: %+q", err)
+	}
+	tests.Passed("Should have successfully created tag directive for Name")
+
+	ageTag, err := user.AddTag("Age", "json", "age")
+	if err != nil {
+		tests.Failed("Should have successfully created tag directive for Age: %+q", err)
+	}
+	tests.Passed("Should have successfully created tag directive for Age")
+
+	configSpan := config.Span()
+	configSource := "type Config struct {\n\tAddr string // address to bind.\n\tPort int\n}"
+
+	err = ast.ApplyRewrites(
+		user.AddMethod(gen.Text("// Table returns the table name of the user.\nfunc (u User) Table() string { return \"users\" }")),
+		ageTag,
+		configSpan.Replace(gen.Text(configSource)),
+		user.Span().InsertBefore(gen.Text("// Record defines a marker for persisted types.\ntype Record interface{}\n\n")),
+		nameTag,
+	)
+	if err != nil {
+		tests.Failed("Should have successfully applied rewrites: %+q", err)
+	}
+	tests.Passed("Should have successfully applied rewrites")
+
+	content, err := ioutil.ReadFile(filepath.Join(fx.Dir, "store.go"))
+	if err != nil {
+		tests.Failed("Should have successfully read rewritten file: %+q", err)
+	}
+	tests.Passed("Should have successfully read rewritten file")
+
+	if string(content) != rewriteExpected {
+		tests.Info("Source: %s", content)
+		tests.Info("Expected: %s", rewriteExpected)
+		tests.Failed("Should have successfully matched rewritten source")
+	}
+	tests.Passed("Should have successfully matched rewritten source")
+
+	if err := ast.ApplyRewrites(configSpan.Replace(gen.Text(configSource))); err == nil {
+		tests.Failed("Should have failed to rewrite declaration changed since parsing")
+	}
+	tests.Passed("Should have failed to rewrite declaration changed since parsing")
+}
+
+// TestRewriteSourceOverlap validates overlapping edits are rejected.
+func TestRewriteSourceOverlap(t *testing.T) {
+	src := []byte("package store\n")
+
+	_, err := ast.RewriteSource(src,
+		gen.RewriteDirective{From: 0, Length: 7, Writer: gen.Text("package")},
+		gen.RewriteDirective{From: 3, Length: 2, Writer: gen.Text("ck")},
+	)
+	if err == nil {
+		tests.Failed("Should have failed to apply overlapping edits")
+	}
+	tests.Passed("Should have failed to apply overlapping edits")
+}
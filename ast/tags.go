@@ -0,0 +1,115 @@
+package ast
+
+import (
+	"fmt"
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+// TagOption defines a single comma separated option of a tag value, where options of the
+// form key=value are split into their key and value (e.g min=3).
+type TagOption struct {
+	Key   string
+	Value string
+}
+
+// String returns the option as written in the tag.
+func (t TagOption) String() string {
+	if t.Value == "" {
+		return t.Key
+	}
+	return t.Key + "=" + t.Value
+}
+
+// ParseStructTag parses the raw content of a struct tag into its key:"value" pairs following the
+// conventions of reflect.StructTag. Parsing stops at the first malformed pair, in which case the
+// pairs before it are returned along with an error.
+func ParseStructTag(raw string) ([]TagDeclaration, error) {
+	var tags []TagDeclaration
+
+	tag := raw
+	for tag != "" {
+		// Skip leading space.
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// Scan to colon. A space, a quote or a control character is a syntax error.
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return tags, fmt.Errorf("malformed struct tag %q at %q", raw, tag)
+		}
+		name := tag[:i]
+		tag = tag[i+1:]
+
+		// Scan quoted string to find value.
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return tags, fmt.Errorf("malformed struct tag %q: unterminated value for %q", raw, name)
+		}
+		quoted := tag[:i+1]
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			return tags, fmt.Errorf("malformed struct tag %q: invalid value for %q: %s", raw, name, err)
+		}
+
+		parts := strings.Split(value, ",")
+
+		declr := TagDeclaration{
+			Name:  name,
+			Value: parts[0],
+			Metas: parts[1:],
+			Raw:   value,
+			Base:  name + ":" + quoted,
+		}
+
+		for _, part := range parts {
+			if part == "" {
+				continue
+			}
+
+			option := TagOption{Key: part}
+			if index := strings.Index(part, "="); index != -1 {
+				option.Key = part[:index]
+				option.Value = part[index+1:]
+			}
+
+			declr.Options = append(declr.Options, option)
+		}
+
+		tags = append(tags, declr)
+	}
+
+	return tags, nil
+}
+
+// rawTag returns the unquoted content of the field's tag if any.
+func rawTag(field *ast.Field) string {
+	if field == nil || field.Tag == nil {
+		return ""
+	}
+
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+
+	return raw
+}
@@ -0,0 +1,66 @@
+package ast_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+)
+
+// TestParseStructTag validates tags are parsed as reflect.StructTag parses them.
+func TestParseStructTag(t *testing.T) {
+	raw := `json:"-" validate:"min=3,max=10" db:"user.id" path:"a/b:c" quote:"say \"hi\"" json:"shadowed"`
+
+	tags, err := ast.ParseStructTag(raw)
+	if err != nil {
+		tests.Info("Error: %+q", err)
+		tests.Failed("Should have successfully parsed struct tag")
+	}
+	tests.Passed("Should have successfully parsed struct tag")
+
+	if len(tags) != 6 {
+		tests.Info("Tags: %#v", tags)
+		tests.Failed("Should have successfully parsed all tag pairs")
+	}
+	tests.Passed("Should have successfully parsed all tag pairs")
+
+	structTag := reflect.StructTag(raw)
+	found := map[string]bool{}
+	for _, tag := range tags {
+		if found[tag.Name] {
+			continue
+		}
+		found[tag.Name] = true
+
+		if value, _ := structTag.Lookup(tag.Name); value != tag.Raw {
+			tests.Info("Tag %q: %q != %q", tag.Name, tag.Raw, value)
+			tests.Failed("Should have successfully matched reflect.StructTag values")
+		}
+	}
+	tests.Passed("Should have successfully matched reflect.StructTag values")
+
+	validate := tags[1]
+	if validate.Value != "min=3" || len(validate.Metas) != 1 || validate.Metas[0] != "max=10" {
+		tests.Info("Tag: %#v", validate)
+		tests.Failed("Should have successfully split tag value and metas")
+	}
+	tests.Passed("Should have successfully split tag value and metas")
+
+	if max, ok := validate.Option("max"); !ok || max != "10" {
+		tests.Failed("Should have successfully parsed key/value options")
+	}
+	tests.Passed("Should have successfully parsed key/value options")
+
+	if tags[0].Base != `json:"-"` || tags[4].Raw != `say "hi"` {
+		tests.Info("Tags: %#v", tags)
+		tests.Failed("Should have successfully kept base and unquoted raw values")
+	}
+	tests.Passed("Should have successfully kept base and unquoted raw values")
+
+	partial, err := ast.ParseStructTag(`json:"name" db:user`)
+	if err == nil || len(partial) != 1 || partial[0].Value != "name" {
+		tests.Failed("Should have successfully returned pairs before a malformed tag with an error")
+	}
+	tests.Passed("Should have successfully returned pairs before a malformed tag with an error")
+}
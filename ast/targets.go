@@ -0,0 +1,473 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/influx6/moz/gen"
+)
+
+//===========================================================================================================
+
+// TypeMapping defines a table which maps Go type names (e.g string, []byte, time.Time) to
+// the type names of another language. The "*" entry is used for any type without an entry.
+type TypeMapping map[string]string
+
+// With returns a new TypeMapping holding all entries of the mapping, overridden by
+// the entries of the provided overrides.
+func (tm TypeMapping) With(overrides TypeMapping) TypeMapping {
+	merged := make(TypeMapping, len(tm)+len(overrides))
+
+	for key, value := range tm {
+		merged[key] = value
+	}
+
+	for key, value := range overrides {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// Lookup returns the mapped type name for the giving Go type, falling back to the "*" entry.
+func (tm TypeMapping) Lookup(goType string) (string, bool) {
+	if value, ok := tm[goType]; ok {
+		return value, true
+	}
+
+	value, ok := tm["*"]
+	return value, ok
+}
+
+var (
+	// TypeScriptTypes defines the default mapping of Go types to typescript types.
+	TypeScriptTypes = TypeMapping{
+		"*":               "any",
+		"any":             "any",
+		"interface{}":     "any",
+		"error":           "string",
+		"string":          "string",
+		"bool":            "boolean",
+		"byte":            "number",
+		"rune":            "number",
+		"int":             "number",
+		"int8":            "number",
+		"int16":           "number",
+		"int32":           "number",
+		"int64":           "number",
+		"uint":            "number",
+		"uint8":           "number",
+		"uint16":          "number",
+		"uint32":          "number",
+		"uint64":          "number",
+		"float32":         "number",
+		"float64":         "number",
+		"[]byte":          "string",
+		"time.Time":       "string",
+		"time.Duration":   "number",
+		"json.RawMessage": "any",
+	}
+
+	// ProtobufTypes defines the default mapping of Go types to protobuf types.
+	ProtobufTypes = TypeMapping{
+		"*":             "bytes",
+		"any":           "google.protobuf.Any",
+		"interface{}":   "google.protobuf.Any",
+		"error":         "string",
+		"string":        "string",
+		"bool":          "bool",
+		"byte":          "uint32",
+		"rune":          "int32",
+		"int":           "int64",
+		"int8":          "int32",
+		"int16":         "int32",
+		"int32":         "int32",
+		"int64":         "int64",
+		"uint":          "uint64",
+		"uint8":         "uint32",
+		"uint16":        "uint32",
+		"uint32":        "uint32",
+		"uint64":        "uint64",
+		"float32":       "float",
+		"float64":       "double",
+		"[]byte":        "bytes",
+		"time.Time":     "google.protobuf.Timestamp",
+		"time.Duration": "google.protobuf.Duration",
+	}
+
+	// SQLTypes defines the default mapping of Go types to sql column types. Slices, maps
+	// and structs are stored with the "*" entry.
+	SQLTypes = TypeMapping{
+		"*":             "JSONB",
+		"string":        "TEXT",
+		"bool":          "BOOLEAN",
+		"byte":          "SMALLINT",
+		"rune":          "INTEGER",
+		"int":           "BIGINT",
+		"int8":          "SMALLINT",
+		"int16":         "SMALLINT",
+		"int32":         "INTEGER",
+		"int64":         "BIGINT",
+		"uint":          "NUMERIC(20)",
+		"uint8":         "SMALLINT",
+		"uint16":        "INTEGER",
+		"uint32":        "BIGINT",
+		"uint64":        "NUMERIC(20)",
+		"float32":       "REAL",
+		"float64":       "DOUBLE PRECISION",
+		"[]byte":        "BYTEA",
+		"time.Time":     "TIMESTAMP",
+		"time.Duration": "BIGINT",
+	}
+)
+
+//===========================================================================================================
+
+// TypeScriptInterfaceFor returns a gen.TSInterfaceDeclr for the giving struct, where property names
+// are taken from the tagName tag (e.g json) of each field. Embedded structs declared within the package
+// without a tag name become extended interfaces, as they map to interfaces of their own, while other
+// embedded types, such as named strings and imported types, become properties named after the type.
+// A nil TypeMapping uses TypeScriptTypes.
+func TypeScriptInterfaceFor(item StructDeclaration, tagName string, types TypeMapping) (gen.TSInterfaceDeclr, error) {
+	if item.Declr == nil || item.Struct == nil {
+		return gen.TSInterfaceDeclr{}, errors.New("StructDeclaration has no PackageDeclaration field")
+	}
+
+	if types == nil {
+		types = TypeScriptTypes
+	}
+
+	mapper := targetTypeMapper{
+		types:   types,
+		pointer: func(t string) string { return t + " | null" },
+		slice: func(t string) string {
+			if strings.ContainsAny(t, " |") {
+				return "(" + t + ")[]"
+			}
+			return t + "[]"
+		},
+		mapOf: func(k, v string) string { return fmt.Sprintf("Record<%s, %s>", k, v) },
+		named: func(name string) string { return name },
+	}
+
+	declr := gen.TSInterface(gen.Name(item.Name), nil)
+
+	for _, field := range targetFieldsOf(item.Struct, tagName, false, nil) {
+		if field.Embedded && !field.Tagged {
+			if spec, ok := identDecl(unstar(field.Expr)).(*ast.TypeSpec); ok {
+				if _, ok := spec.Type.(*ast.StructType); ok {
+					declr.Extends = append(declr.Extends, mapper.named(spec.Name.Name))
+					continue
+				}
+			}
+		}
+
+		_, pointer := field.Expr.(*ast.StarExpr)
+
+		declr.Fields = append(declr.Fields, gen.TSFieldDeclr{
+			Name:     field.nameOr(field.GoName),
+			Type:     mapper.typeOf(field.Expr, 0),
+			Optional: pointer || field.has("omitempty"),
+		})
+	}
+
+	return declr, nil
+}
+
+// ProtoMessageFor returns a gen.ProtoMessageDeclr for the giving struct, where field names are taken
+// from the tagName tag of each field or the snake-cased field name. Fields are numbered in declaration
+// order and the fields of embedded structs are included in place. A nil TypeMapping uses ProtobufTypes.
+func ProtoMessageFor(item StructDeclaration, tagName string, types TypeMapping) (gen.ProtoMessageDeclr, error) {
+	if item.Declr == nil || item.Struct == nil {
+		return gen.ProtoMessageDeclr{}, errors.New("StructDeclaration has no PackageDeclaration field")
+	}
+
+	if types == nil {
+		types = ProtobufTypes
+	}
+
+	mapper := targetTypeMapper{
+		types:   types,
+		pointer: func(t string) string { return t },
+		slice:   func(t string) string { return t },
+		mapOf:   func(k, v string) string { return fmt.Sprintf("map<%s, %s>", k, v) },
+		named:   func(name string) string { return name },
+	}
+
+	declr := gen.ProtoMessage(gen.Name(item.Name), nil)
+
+	for index, field := range targetFieldsOf(item.Struct, tagName, true, nil) {
+		pfield := gen.ProtoField(field.nameOr(snakeCase(field.GoName)), mapper.typeOf(field.Expr, 0), index+1)
+
+		switch expr := field.Expr.(type) {
+		case *ast.StarExpr:
+			pfield.Optional = true
+		case *ast.ArrayType:
+			if !isByteSlice(expr) {
+				pfield.Repeated = true
+				pfield.Type = mapper.typeOf(expr.Elt, 0)
+			}
+		}
+
+		declr.Fields = append(declr.Fields, pfield)
+	}
+
+	return declr, nil
+}
+
+// SQLTableFor returns a gen.SQLTableDeclr creating a table for the giving struct, where column names
+// are taken from the tagName tag (e.g db) of each field or the snake-cased field name. Pointer fields
+// are nullable, and the tag options "pk" and "unique" mark primary keys and unique columns. An empty
+// tableName uses the snake-cased struct name. A nil TypeMapping uses SQLTypes.
+func SQLTableFor(item StructDeclaration, tableName string, tagName string, types TypeMapping) (gen.SQLTableDeclr, error) {
+	if item.Declr == nil || item.Struct == nil {
+		return gen.SQLTableDeclr{}, errors.New("StructDeclaration has no PackageDeclaration field")
+	}
+
+	if types == nil {
+		types = SQLTypes
+	}
+
+	if tableName == "" {
+		tableName = snakeCase(item.Name)
+	}
+
+	composite := func(string) string {
+		value, _ := types.Lookup("*")
+		return value
+	}
+
+	mapper := targetTypeMapper{
+		types:   types,
+		pointer: func(t string) string { return t },
+		slice:   composite,
+		mapOf:   func(k, v string) string { return composite("") },
+		named:   composite,
+	}
+
+	declr := gen.SQLTable(tableName)
+	declr.IfNotExists = true
+
+	for _, field := range targetFieldsOf(item.Struct, tagName, true, nil) {
+		_, pointer := field.Expr.(*ast.StarExpr)
+
+		column := gen.SQLColumn(field.nameOr(snakeCase(field.GoName)), mapper.typeOf(field.Expr, 0), !pointer)
+		column.PrimaryKey = field.has("pk")
+		column.Unique = field.has("unique")
+
+		declr.Columns = append(declr.Columns, column)
+	}
+
+	return declr, nil
+}
+
+//===========================================================================================================
+
+// targetField defines an exported field of a struct as seen by the target language mappers.
+type targetField struct {
+	GoName   string
+	Name     string
+	Options  []string
+	Expr     ast.Expr
+	Embedded bool
+	Tagged   bool
+}
+
+func (tf targetField) nameOr(fallback string) string {
+	if tf.Name != "" {
+		return tf.Name
+	}
+	return fallback
+}
+
+func (tf targetField) has(option string) bool {
+	for _, item := range tf.Options {
+		if item == option {
+			return true
+		}
+	}
+	return false
+}
+
+// targetFieldsOf returns all exported fields of the struct which are not excluded by a "-" tagName tag.
+// If flatten is true, the fields of embedded structs declared within the package without a tag name are
+// returned in place of the embedded field.
+func targetFieldsOf(str *ast.StructType, tagName string, flatten bool, visited map[*ast.StructType]bool) []targetField {
+	if visited == nil {
+		visited = make(map[*ast.StructType]bool)
+	}
+
+	if visited[str] {
+		return nil
+	}
+
+	visited[str] = true
+	defer delete(visited, str)
+
+	var fields []targetField
+
+	for _, field := range str.Fields.List {
+		var tag targetField
+		tag.Expr = field.Type
+
+		if field.Tag != nil {
+			if raw, err := strconv.Unquote(field.Tag.Value); err == nil {
+				if value, ok := reflect.StructTag(raw).Lookup(tagName); ok {
+					if value == "-" {
+						continue
+					}
+
+					parts := strings.Split(value, ",")
+					tag.Name = parts[0]
+					tag.Options = parts[1:]
+					tag.Tagged = tag.Name != ""
+				}
+			}
+		}
+
+		if len(field.Names) == 0 {
+			// Embedded imported types are named after their type name, without the package.
+			name := getRealIdentName(unstar(field.Type))
+			if sel, ok := unstar(field.Type).(*ast.SelectorExpr); ok {
+				name = sel.Sel.Name
+			}
+
+			if name == "" || !unicode.IsUpper(rune(name[0])) {
+				continue
+			}
+
+			tag.GoName = name
+			tag.Embedded = true
+
+			if flatten && !tag.Tagged {
+				if _, embedded, err := GetStructSpec(identDecl(unstar(field.Type))); err == nil {
+					fields = append(fields, targetFieldsOf(embedded, tagName, flatten, visited)...)
+					continue
+				}
+			}
+
+			fields = append(fields, tag)
+			continue
+		}
+
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+
+			named := tag
+			named.GoName = ident.Name
+			fields = append(fields, named)
+		}
+	}
+
+	return fields
+}
+
+// targetTypeMapper maps Go type expressions into the types of a target language.
+type targetTypeMapper struct {
+	types   TypeMapping
+	pointer func(string) string
+	slice   func(string) string
+	mapOf   func(string, string) string
+	named   func(string) string
+}
+
+func (tm targetTypeMapper) typeOf(expr ast.Expr, depth int) string {
+	fallback, _ := tm.types.Lookup("*")
+	if depth > 10 {
+		return fallback
+	}
+
+	switch item := expr.(type) {
+	case *ast.Ident:
+		if value, ok := tm.types[item.Name]; ok {
+			return value
+		}
+
+		if spec, ok := identDecl(item).(*ast.TypeSpec); ok {
+			switch spec.Type.(type) {
+			case *ast.StructType, *ast.InterfaceType:
+				return tm.named(item.Name)
+			default:
+				return tm.typeOf(spec.Type, depth+1)
+			}
+		}
+
+		return fallback
+	case *ast.SelectorExpr:
+		if value, ok := tm.types.Lookup(getName(item)); ok {
+			return value
+		}
+		return fallback
+	case *ast.StarExpr:
+		return tm.pointer(tm.typeOf(item.X, depth+1))
+	case *ast.ArrayType:
+		if isByteSlice(item) {
+			if value, ok := tm.types["[]byte"]; ok {
+				return value
+			}
+		}
+		return tm.slice(tm.typeOf(item.Elt, depth+1))
+	case *ast.MapType:
+		return tm.mapOf(tm.typeOf(item.Key, depth+1), tm.typeOf(item.Value, depth+1))
+	case *ast.InterfaceType:
+		if value, ok := tm.types["interface{}"]; ok {
+			return value
+		}
+	}
+
+	return fallback
+}
+
+// identDecl returns the declaration of the object the expression refers to if it is an identifier.
+func identDecl(expr ast.Expr) interface{} {
+	if ident, ok := expr.(*ast.Ident); ok && ident.Obj != nil {
+		return ident.Obj.Decl
+	}
+	return nil
+}
+
+// unstar returns the expression pointed to if expr is a pointer type.
+func unstar(expr ast.Expr) ast.Expr {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return star.X
+	}
+	return expr
+}
+
+// isByteSlice returns true/false if the array type is a []byte.
+func isByteSlice(arr *ast.ArrayType) bool {
+	if arr.Len != nil {
+		return false
+	}
+
+	ident, ok := arr.Elt.(*ast.Ident)
+	return ok && (ident.Name == "byte" || ident.Name == "uint8")
+}
+
+// snakeCase returns the name in snake case, keeping acronyms together (e.g UserID => user_id).
+func snakeCase(name string) string {
+	runes := []rune(name)
+
+	var out []rune
+	for index, r := range runes {
+		if unicode.IsUpper(r) && index > 0 {
+			prev := runes[index-1]
+			nextLower := index+1 < len(runes) && unicode.IsLower(runes[index+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				out = append(out, '_')
+			}
+		}
+
+		out = append(out, unicode.ToLower(r))
+	}
+
+	return string(out)
+}
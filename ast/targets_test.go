@@ -0,0 +1,173 @@
+package ast_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+)
+
+var targetSource = `package store
+
+import "time"
+
+// Status defines the state of an account.
+type Status string
+
+// Base defines shared record fields.
+type Base struct {
+	ID      int64     ` + "`json:\"id\" db:\"id,pk\"`" + `
+	Created time.Time ` + "`json:\"created\"`" + `
+}
+
+// Account defines a user account.
+type Account struct {
+	Base
+	Email  string            ` + "`json:\"email\" db:\"email,unique\"`" + `
+	Status Status            ` + "`json:\"status\"`" + `
+	Tags   []string          ` + "`json:\"tags,omitempty\"`" + `
+	Avatar []byte            ` + "`json:\"avatar\"`" + `
+	Parent *Account          ` + "`json:\"parent\"`" + `
+	Meta   map[string]int    ` + "`json:\"meta\"`" + `
+	Secret string            ` + "`json:\"-\" db:\"-\"`" + `
+	hidden bool
+}
+
+// Code defines an identifier.
+type Code string
+
+// Entry defines a record embedding a named string and an imported type.
+type Entry struct {
+	Base
+	Code
+	*time.Location
+}
+`
+
+var tsEntryExpected = `export interface Entry extends Base {
+  Code: string;
+  Location?: any | null;
+}`
+
+var tsExpected = `export interface Account extends Base {
+  email: string;
+  status: string;
+  tags?: string[];
+  avatar: string;
+  parent?: Account | null;
+  meta: Record<string, number>;
+}`
+
+var protoExpected = `message Account {
+  int64 id = 1;
+  google.protobuf.Timestamp created = 2;
+  string email = 3;
+  string status = 4;
+  repeated string tags = 5;
+  bytes avatar = 6;
+  optional Account parent = 7;
+  map<string, int64> meta = 8;
+}`
+
+var sqlExpected = `CREATE TABLE IF NOT EXISTS account (
+  id BIGINT NOT NULL,
+  created TIMESTAMP NOT NULL,
+  email TEXT NOT NULL UNIQUE,
+  status TEXT NOT NULL,
+  tags JSONB NOT NULL,
+  avatar BYTEA NOT NULL,
+  parent JSONB,
+  meta JSONB NOT NULL,
+  PRIMARY KEY (id)
+);`
+
+// TestStructTargets validates structs are mapped into typescript, protobuf and sql declarations.
+func TestStructTargets(t *testing.T) {
+	pkgs := newFixture(t, sourceFile{Name: "store.go", Source: targetSource}).Packages
+
+	account, ok := pkgs[0].StructFor("Account")
+	if !ok {
+		tests.Failed("Should have successfully found Account struct")
+	}
+	tests.Passed("Should have successfully found Account struct")
+
+	tsDeclr, err := ast.TypeScriptInterfaceFor(account, "json", nil)
+	if err != nil {
+		tests.Failed("Should have successfully mapped typescript interface: %+q", err)
+	}
+	tests.Passed("Should have successfully mapped typescript interface")
+
+	var bu bytes.Buffer
+	if _, err := tsDeclr.WriteTo(&bu); err != nil {
+		tests.Failed("Should have successfully written typescript interface: %+q", err)
+	}
+	tests.Passed("Should have successfully written typescript interface")
+
+	if bu.String() != tsExpected {
+		tests.Info("Source: %s", bu.String())
+		tests.Info("Expected: %s", tsExpected)
+		tests.Failed("Should have successfully matched typescript interface")
+	}
+	tests.Passed("Should have successfully matched typescript interface")
+
+	entry, ok := pkgs[0].StructFor("Entry")
+	if !ok {
+		tests.Failed("Should have successfully found Entry struct")
+	}
+
+	entryDeclr, err := ast.TypeScriptInterfaceFor(entry, "json", nil)
+	if err != nil {
+		tests.Failed("Should have successfully mapped typescript interface: %+q", err)
+	}
+
+	bu.Reset()
+	if _, err := entryDeclr.WriteTo(&bu); err != nil {
+		tests.Failed("Should have successfully written typescript interface: %+q", err)
+	}
+
+	if bu.String() != tsEntryExpected {
+		tests.Info("Source: %s", bu.String())
+		tests.Info("Expected: %s", tsEntryExpected)
+		tests.Failed("Should have successfully extended only package structs")
+	}
+	tests.Passed("Should have successfully extended only package structs")
+
+	protoDeclr, err := ast.ProtoMessageFor(account, "json", nil)
+	if err != nil {
+		tests.Failed("Should have successfully mapped protobuf message: %+q", err)
+	}
+	tests.Passed("Should have successfully mapped protobuf message")
+
+	bu.Reset()
+	if _, err := protoDeclr.WriteTo(&bu); err != nil {
+		tests.Failed("Should have successfully written protobuf message: %+q", err)
+	}
+	tests.Passed("Should have successfully written protobuf message")
+
+	if bu.String() != protoExpected {
+		tests.Info("Source: %s", bu.String())
+		tests.Info("Expected: %s", protoExpected)
+		tests.Failed("Should have successfully matched protobuf message")
+	}
+	tests.Passed("Should have successfully matched protobuf message")
+
+	sqlDeclr, err := ast.SQLTableFor(account, "", "db", nil)
+	if err != nil {
+		tests.Failed("Should have successfully mapped sql table: %+q", err)
+	}
+	tests.Passed("Should have successfully mapped sql table")
+
+	bu.Reset()
+	if _, err := sqlDeclr.WriteTo(&bu); err != nil {
+		tests.Failed("Should have successfully written sql table: %+q", err)
+	}
+	tests.Passed("Should have successfully written sql table")
+
+	if bu.String() != sqlExpected {
+		tests.Info("Source: %s", bu.String())
+		tests.Info("Expected: %s", sqlExpected)
+		tests.Failed("Should have successfully matched sql table")
+	}
+	tests.Passed("Should have successfully matched sql table")
+}
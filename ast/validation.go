@@ -0,0 +1,306 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"github.com/influx6/moz/gen"
+)
+
+// ValidateAnnotation defines the annotation which marks a struct to have a Validate method generated
+// by ValidateAnnotationGenerator, and its fields to be validated by giving rules.
+const ValidateAnnotation = "@validate"
+
+// ValidateTag defines the struct tag holding the validation rules of a field.
+const ValidateTag = "validate"
+
+// validateKinds defines the kinds of the predeclared types.
+var validateKinds = map[string]gen.ValidateKind{
+	"string":     gen.ValidateString,
+	"int":        gen.ValidateInt,
+	"int8":       gen.ValidateInt,
+	"int16":      gen.ValidateInt,
+	"int32":      gen.ValidateInt,
+	"int64":      gen.ValidateInt,
+	"rune":       gen.ValidateInt,
+	"uint":       gen.ValidateUint,
+	"uint8":      gen.ValidateUint,
+	"uint16":     gen.ValidateUint,
+	"uint32":     gen.ValidateUint,
+	"uint64":     gen.ValidateUint,
+	"uintptr":    gen.ValidateUint,
+	"byte":       gen.ValidateUint,
+	"float32":    gen.ValidateFloat,
+	"float64":    gen.ValidateFloat,
+	"bool":       gen.ValidateBool,
+	"error":      gen.ValidateNilable,
+	"any":        gen.ValidateNilable,
+	"complex64":  gen.ValidateOther,
+	"complex128": gen.ValidateOther,
+}
+
+// ValidateFor returns the declaration of a Validate method for the struct, checking the rules given
+// to each field by a validate tag or @validate annotations in its comments:
+//
+//	Name  string   `validate:"required,min=3,max=64"`
+//	Email string   `validate:"regex=^[^@]+@[^@]+$"`
+//	Role  string   `validate:"oneof=admin user guest"`
+//	Tags  []string `validate:"len=3"`
+//
+//	// @validate(required, min => 18)
+//	Age *int
+//
+// Rules are required, min, max, len, regex and oneof, where min, max and len check the length of
+// strings, slices, arrays and maps and the value of numbers. As rules are separated by commas, a
+// regex can not contain one. Fields whose type has a Validate method, or is a struct annotated with
+// @validate, are validated through it along with elements of such types in slices, arrays and maps.
+// The nested rule forces this for types which can not be resolved and "-" skips a field.
+//
+// Fields are named by their Go name in errors, or by the value of the tag given with
+// @validate(tag => json) on the struct.
+func ValidateFor(str StructDeclaration, annotation AnnotationDeclaration, pkg Package) (gen.ValidateDeclr, error) {
+	if str.Object == nil {
+		return gen.ValidateDeclr{}, errors.New("StructDeclaration has no type specification")
+	}
+
+	typeName := str.Object.Name.Name
+	if str.Object.TypeParams != nil && len(str.Object.TypeParams.List) != 0 {
+		return gen.ValidateDeclr{}, fmt.Errorf("Struct %q can not have type parameters", typeName)
+	}
+
+	fields, err := str.EffectiveFieldsIn(pkg)
+	if err != nil {
+		return gen.ValidateDeclr{}, err
+	}
+
+	tagName := annotation.Param("tag")
+
+	validate := gen.Validate(typeName)
+
+	for _, field := range fields {
+		if field.Embedded && field.Promotes {
+			continue
+		}
+
+		declr, skip, err := validateFieldFor(field, tagName)
+		if err != nil {
+			return gen.ValidateDeclr{}, fmt.Errorf("Struct %q: %s", typeName, err)
+		}
+
+		if skip || !declr.Required && !declr.Nested && len(declr.Rules) == 0 {
+			continue
+		}
+
+		if embedding, ok := pointerEmbedding(field); ok {
+			return gen.ValidateDeclr{}, fmt.Errorf("Struct %q: Field %q is promoted through embedded pointer %q", typeName, declr.Path, embedding.FieldName)
+		}
+
+		if _, err := declr.Conditions(typeName); err != nil {
+			return gen.ValidateDeclr{}, fmt.Errorf("Struct %q: %s", typeName, err)
+		}
+
+		validate.Fields = append(validate.Fields, declr)
+	}
+
+	return validate, nil
+}
+
+// ValidateAnnotationGenerator defines a StructAnnotationGenerator for the @validate annotation, which
+// writes the Validate method declared by ValidateFor into a "<struct>_validate.go" file of the struct's
+// package.
+func ValidateAnnotationGenerator(toDir string, an AnnotationDeclaration, str StructDeclaration, pkgDeclr PackageDeclaration, pkg Package) ([]gen.WriteDirective, error) {
+	validate, err := ValidateFor(str, an, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	name := str.Object.Name.Name
+	validate.Comments = gen.Text(fmt.Sprintf("// Validate returns an error joining the failures of every field of %s breaking its\n// validation rules, or nil if none does.", name))
+
+	var imports []gen.ImportItemDeclr
+	for _, importPath := range validate.Imports() {
+		imports = append(imports, gen.Import(importPath, ""))
+	}
+
+	return []gen.WriteDirective{
+		{
+			FileName: fmt.Sprintf("%s_validate.go", snakeCase(name)),
+			Writer: gen.Package(
+				gen.Name(pkgDeclr.Package),
+				gen.Imports(imports...),
+				validate,
+			),
+		},
+	}, nil
+}
+
+// validateFieldFor returns the validation of the field from its tag and annotations, and whether it
+// is skipped.
+func validateFieldFor(field FieldDeclaration, tagName string) (gen.ValidateFieldDeclr, bool, error) {
+	declr := gen.ValidateFieldDeclr{
+		Path:     field.FieldName,
+		Selector: strings.Join(field.Path, "."),
+	}
+
+	if tagName != "" {
+		declr.Path = field.NameFor(tagName)
+	}
+
+	var options []TagOption
+	if tag, err := field.GetTag(ValidateTag); err == nil {
+		options = append(options, tag.Options...)
+	}
+
+	for _, annotation := range field.AnnotationsFor(ValidateAnnotation) {
+		for _, arg := range annotation.Arguments {
+			option := TagOption{Key: arg}
+			if index := strings.Index(arg, "=>"); index != -1 {
+				option.Key = strings.TrimSpace(arg[:index])
+				option.Value = strings.TrimSpace(arg[index+2:])
+			}
+
+			options = append(options, option)
+		}
+	}
+
+	var forceNested bool
+
+	for _, option := range options {
+		switch option.Key {
+		case "-":
+			return declr, true, nil
+		case "required":
+			declr.Required = true
+		case "nested":
+			forceNested = true
+		default:
+			declr.Rules = append(declr.Rules, gen.ValidateRuleDeclr{Name: option.Key, Value: option.Value})
+		}
+	}
+
+	if field.Field == nil {
+		return declr, false, nil
+	}
+
+	expr := field.Field.Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		declr.Pointer = true
+		expr = star.X
+	}
+
+	declr.Kind = validateKindOf(expr, field.Declr, 0)
+
+	elem := expr
+	switch item := expr.(type) {
+	case *ast.ArrayType:
+		elem = item.Elt
+	case *ast.MapType:
+		elem = item.Value
+	}
+
+	if star, ok := elem.(*ast.StarExpr); ok && elem != expr {
+		declr.ElemPointer = true
+		elem = star.X
+	}
+
+	switch declr.Kind {
+	case gen.ValidateStruct, gen.ValidateSlice, gen.ValidateArray, gen.ValidateMap:
+		declr.Nested = forceNested || hasValidate(elem, field.Declr)
+	default:
+		if forceNested {
+			return declr, false, fmt.Errorf("Field %q of kind %s can not be nested", declr.Path, declr.Kind)
+		}
+	}
+
+	if declr.Required && !declr.Pointer && declr.Kind == gen.ValidateStruct {
+		if resolved, err := field.Declr.ResolveType(expr); err != nil || !hasMethod(resolved, "IsZero") {
+			return declr, false, fmt.Errorf("Field %q requires an IsZero method to be required", declr.Path)
+		}
+	}
+
+	return declr, false, nil
+}
+
+// validateKindOf returns the kind of values of the type expression declared within the giving file,
+// following named types to their underlying type.
+func validateKindOf(expr ast.Expr, declr *PackageDeclaration, depth int) gen.ValidateKind {
+	if depth > maxFieldDepth {
+		return gen.ValidateOther
+	}
+
+	switch item := expr.(type) {
+	case *ast.ParenExpr:
+		return validateKindOf(item.X, declr, depth+1)
+	case *ast.ArrayType:
+		if item.Len == nil {
+			return gen.ValidateSlice
+		}
+		return gen.ValidateArray
+	case *ast.MapType:
+		return gen.ValidateMap
+	case *ast.StructType:
+		return gen.ValidateStruct
+	case *ast.InterfaceType, *ast.ChanType, *ast.FuncType, *ast.StarExpr:
+		return gen.ValidateNilable
+	case *ast.Ident:
+		if kind, ok := validateKinds[item.Name]; ok && item.Obj == nil {
+			return kind
+		}
+	case *ast.SelectorExpr:
+	default:
+		return gen.ValidateOther
+	}
+
+	if declr == nil {
+		return gen.ValidateOther
+	}
+
+	resolved, err := declr.ResolveType(expr)
+	if err != nil {
+		return gen.ValidateOther
+	}
+
+	switch {
+	case resolved.Struct != nil:
+		return gen.ValidateStruct
+	case resolved.Interface != nil:
+		return gen.ValidateNilable
+	case resolved.Type != nil && resolved.Type.Object != nil:
+		return validateKindOf(resolved.Type.Object.Type, resolved.Type.Declr, depth+1)
+	}
+
+	return gen.ValidateOther
+}
+
+// hasValidate returns true/false if the named type of the expression has a Validate method or is a
+// struct annotated with @validate.
+func hasValidate(expr ast.Expr, declr *PackageDeclaration) bool {
+	if declr == nil {
+		return false
+	}
+
+	switch expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+	default:
+		return false
+	}
+
+	resolved, err := declr.ResolveType(expr)
+	if err != nil {
+		return false
+	}
+
+	if resolved.Struct != nil && len(resolved.Struct.AnnotationsFor(ValidateAnnotation)) != 0 {
+		return true
+	}
+
+	return hasMethod(resolved, "Validate")
+}
+
+// hasMethod returns true/false if a pointer to the resolved type has a method with the giving name.
+func hasMethod(resolved ResolvedType, name string) bool {
+	set, err := resolved.Package.MethodSet(resolved.Name, true)
+	return err == nil && set.Has(name)
+}
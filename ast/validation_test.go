@@ -0,0 +1,138 @@
+package ast_test
+
+import (
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+)
+
+var validateSource = `package shop
+
+import (
+	"errors"
+	"time"
+)
+
+// Address defines a postal address.
+// @validate
+type Address struct {
+	Street string ` + "`validate:\"required\"`" + `
+	Zip    string ` + "`validate:\"len=5\"`" + `
+}
+
+// Item defines an ordered item validated by hand.
+type Item struct {
+	SKU string
+}
+
+// Validate returns an error if the item has no SKU.
+func (i *Item) Validate() error {
+	if i.SKU == "" {
+		return errors.New("SKU: is required")
+	}
+	return nil
+}
+
+// Base defines the fields shared by records.
+type Base struct {
+	ID int64 ` + "`validate:\"min=1\"`" + `
+}
+
+// Order defines an order of items.
+// @validate(tag => json)
+type Order struct {
+	Base
+	Email  string ` + "`json:\"email\" validate:\"required,regex=^[^@]+@[^@]+$\"`" + `
+	Status string ` + "`json:\"status\" validate:\"oneof=open closed\"`" + `
+
+	// @validate(min => 1, max => 10)
+	Quantity *int              ` + "`json:\"quantity\"`" + `
+	Ratio    float64           ` + "`json:\"ratio\" validate:\"max=1.5\"`" + `
+	Notes    []string          ` + "`json:\"notes\" validate:\"max=3\"`" + `
+	Created  time.Time         ` + "`json:\"created\" validate:\"required\"`" + `
+	Shipping Address           ` + "`json:\"shipping\"`" + `
+	Billing  *Address          ` + "`json:\"billing\" validate:\"required\"`" + `
+	Items    []*Item           ` + "`json:\"items\" validate:\"min=1\"`" + `
+	Extras   map[string]Item   ` + "`json:\"extras\"`" + `
+	Internal string            ` + "`json:\"-\" validate:\"-\"`" + `
+	Comment  string
+}
+
+// Invalid defines a struct with rules its fields do not support.
+type Invalid struct {
+	Active bool ` + "`validate:\"min=1\"`" + `
+}
+`
+
+// TestValidateAnnotationGenerator validates @validate annotated structs generate compilable Validate methods.
+func TestValidateAnnotationGenerator(t *testing.T) {
+	fx := newFixture(t, sourceFile{Name: "shop.go", Source: validateSource})
+	pkgs := fx.Packages
+
+	order, _ := pkgs[0].StructFor("Order")
+	validate, err := ast.ValidateFor(order, order.Annotations[0], pkgs[0])
+	if err != nil {
+		tests.Failed("Should have successfully created validate declaration: %+q", err)
+	}
+	tests.Passed("Should have successfully created validate declaration")
+
+	var fields []string
+	for _, field := range validate.Fields {
+		fields = append(fields, field.Path)
+	}
+
+	if strings.Join(fields, ",") != "ID,email,status,quantity,ratio,notes,created,shipping,billing,items,extras" {
+		tests.Info("Fields: %s", strings.Join(fields, ","))
+		tests.Failed("Should have successfully collected validated fields by tag name")
+	}
+	tests.Passed("Should have successfully collected validated fields by tag name")
+
+	if !validate.Fields[7].Nested || !validate.Fields[9].Nested || !validate.Fields[9].ElemPointer || !validate.Fields[10].Nested {
+		tests.Failed("Should have successfully detected nested validation")
+	}
+	tests.Passed("Should have successfully detected nested validation")
+
+	invalid, _ := pkgs[0].StructFor("Invalid")
+	if _, err := ast.ValidateFor(invalid, ast.AnnotationDeclaration{}, pkgs[0]); err == nil {
+		tests.Failed("Should have failed to validate rules not supported by a field")
+	}
+	tests.Passed("Should have failed to validate rules not supported by a field")
+
+	registry := ast.NewAnnotationRegistry()
+	registry.RegisterStructType(ast.ValidateAnnotation, ast.ValidateAnnotationGenerator)
+
+	directives, err := registry.ParseDeclr(pkgs[0], pkgs[0].Packages[0], fx.Dir)
+	if err != nil {
+		tests.Failed("Should have successfully generated validate directives: %+q", err)
+	}
+
+	if len(directives) != 2 || directives[0].FileName != "address_validate.go" || directives[1].FileName != "order_validate.go" {
+		tests.Failed("Should have successfully generated a file per struct")
+	}
+	tests.Passed("Should have successfully generated a file per struct")
+
+	files := directiveFiles(directives)
+	for _, file := range files {
+		if file.Name == "order_validate.go" && !strings.Contains(file.Source, `if o.Quantity != nil && *o.Quantity < 1 {
+		errs = append(errs, errors.New("quantity: must be at least 1"))
+	} else if o.Quantity != nil && *o.Quantity > 10 {`) {
+			tests.Info("Source: %s", file.Source)
+			tests.Failed("Should have successfully chained rules of a pointer field")
+		}
+	}
+
+	checked := fx.Check(files...)
+	tests.Passed("Should have successfully rendered compilable validate files")
+
+	for _, name := range []string{"Order", "Address"} {
+		methods := types.NewMethodSet(checked.Scope().Lookup(name).Type())
+		if methods.Lookup(checked, "Validate") == nil {
+			tests.Info("Struct: %s", name)
+			tests.Failed("Should have successfully generated Validate methods")
+		}
+	}
+	tests.Passed("Should have successfully generated Validate methods")
+}
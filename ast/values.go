@@ -0,0 +1,509 @@
+package ast
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icrowley/fake"
+	"github.com/influx6/moz/gen"
+)
+
+// fakeLock guards the global generator of the fake package, which is reseeded
+// before every use so values depend only on the ValueGenerator in use.
+var fakeLock sync.Mutex
+
+// DefaultValueGenerator defines the ValueGenerator used by the package level default and
+// random value functions. It is seeded from the current time, so values differ between runs.
+var DefaultValueGenerator = NewValueGenerator(time.Now().UnixNano(), nil)
+
+// ValueGenerator generates default and random values for Go types, drawing all randomness
+// from a source created from its seed and all times from its clock. Two generators with the
+// same seed and clock produce the same values for the same sequence of calls.
+type ValueGenerator struct {
+	// Samples sets the number of elements generated for random slices and maps, defaulting to 2.
+	Samples int
+
+	seed  int64
+	clock func() time.Time
+
+	ml     sync.Mutex
+	rnd    *rand.Rand
+	types  map[string]ValueProvider
+	fields map[string]ValueProvider
+}
+
+// NewValueGenerator returns a new ValueGenerator using the seed and clock, where a
+// nil clock uses time.Now.
+func NewValueGenerator(seed int64, clock func() time.Time) *ValueGenerator {
+	if clock == nil {
+		clock = time.Now
+	}
+
+	return &ValueGenerator{
+		seed:  seed,
+		clock: clock,
+		rnd:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// FixedClock returns a clock which always returns the giving time.
+func FixedClock(t time.Time) func() time.Time {
+	return func() time.Time {
+		return t
+	}
+}
+
+// Seed returns the seed of the generator.
+func (g *ValueGenerator) Seed() int64 {
+	return g.seed
+}
+
+// Reset restores the generator to the state it was created with, so the values it
+// produced since will be produced again.
+func (g *ValueGenerator) Reset() {
+	g.ml.Lock()
+	g.rnd = rand.New(rand.NewSource(g.seed))
+	g.ml.Unlock()
+}
+
+// Now returns the current time from the generator's clock.
+func (g *ValueGenerator) Now() time.Time {
+	return g.clock()
+}
+
+// TemplateFuncs returns the ASTTemplatFuncs with all value producing functions
+// bound to the generator.
+func (g *ValueGenerator) TemplateFuncs() map[string]interface{} {
+	funcs := make(map[string]interface{}, len(ASTTemplatFuncs))
+	for name, fn := range ASTTemplatFuncs {
+		funcs[name] = fn
+	}
+
+	funcs["mapJSON"] = g.MapOutFieldsToJSON
+	funcs["mapRandomJSON"] = g.MapOutFieldsWithRandomValuesToJSON
+	funcs["mapTypeJSON"] = g.MapOutTypeToJSON
+	funcs["mapRandomTypeJSON"] = g.MapOutTypeToJSONWriterWithRandomValues
+	funcs["randomValue"] = g.RandomFieldAssign
+	funcs["defaultValue"] = g.AssignDefaultValue
+	funcs["randomFieldValue"] = g.RandomFieldValue
+	funcs["defaultType"] = g.DefaultTypeValueString
+	funcs["defaultFieldValue"] = g.DefaultFieldValue
+	funcs["defaultLiteral"] = g.DefaultFieldLiteral
+	funcs["randomLiteral"] = g.RandomFieldLiteral
+
+	return funcs
+}
+
+//===========================================================================================================
+
+// int63n returns a random number in [0,n) from the generator's source.
+func (g *ValueGenerator) int63n(n int64) int64 {
+	g.ml.Lock()
+	defer g.ml.Unlock()
+	return g.rnd.Int63n(n)
+}
+
+// int63 returns a random non-negative int64 from the generator's source.
+func (g *ValueGenerator) int63() int64 {
+	g.ml.Lock()
+	defer g.ml.Unlock()
+	return g.rnd.Int63()
+}
+
+// uint64 returns a random uint64 from the generator's source.
+func (g *ValueGenerator) uint64() uint64 {
+	g.ml.Lock()
+	defer g.ml.Unlock()
+	return g.rnd.Uint64()
+}
+
+// float64 returns a random float64 in [0.0,1.0) from the generator's source.
+func (g *ValueGenerator) float64() float64 {
+	g.ml.Lock()
+	defer g.ml.Unlock()
+	return g.rnd.Float64()
+}
+
+// bool returns a random boolean from the generator's source.
+func (g *ValueGenerator) bool() bool {
+	g.ml.Lock()
+	defer g.ml.Unlock()
+	return g.rnd.Intn(2) == 0
+}
+
+// fake runs the function against the fake package after reseeding it from the generator's source.
+func (g *ValueGenerator) fake(fn func() string) string {
+	g.ml.Lock()
+	seed := g.rnd.Int63()
+	g.ml.Unlock()
+
+	fakeLock.Lock()
+	defer fakeLock.Unlock()
+
+	fake.Seed(seed)
+	return fn()
+}
+
+// timestamp returns the quoted current time of the generator's clock.
+func (g *ValueGenerator) timestamp() string {
+	return strconv.Quote(g.Now().Format(timeLayout))
+}
+
+// namedString returns a random string suited to a field with the giving name.
+func (g *ValueGenerator) namedString(varName string) string {
+	switch strings.ToLower(varName) {
+	case "username", "user_name", "login_name":
+		return g.fake(fake.UserName)
+	case "user-agent", "useragent":
+		return g.fake(fake.UserAgent)
+	case "domain", "url":
+		return g.fake(fake.DomainName)
+	case "zip", "zip_code", "zip-code":
+		return g.fake(fake.Zip)
+	case "title", "user_title":
+		return g.fake(fake.Title)
+	case "day":
+		return g.fake(fake.WeekDay)
+	case "week":
+		return g.fake(func() string { return fmt.Sprintf("%d Week", fake.WeekdayNum()) })
+	case "year":
+		return g.fake(func() string { return fmt.Sprintf("%d", fake.Year(1998, 10000)) })
+	case "date", "date_time", "time":
+		return g.Now().Format(timeLayout)
+	case "location", "location_address", "location_addr":
+		return g.fake(fake.Street)
+	case "company", "company_name", "companyname":
+		return g.fake(fake.Company)
+	case "subject", "subject_name", "subjectname":
+		return g.fake(fake.EmailSubject)
+	case "email", "email_address", "emailaddress":
+		return g.fake(fake.EmailAddress)
+	case "addr", "address", "streetaddress", "street_address", "main_address", "mainaddress":
+		return g.fake(fake.StreetAddress)
+	case "companyaddress", "company_address":
+		return g.fake(fake.StreetAddress)
+	case "first_name", "firstname":
+		return g.fake(fake.FirstName)
+	case "last_name", "lastname":
+		return g.fake(fake.LastName)
+	case "name", "fullname", "full_name":
+		return g.fake(fake.FullName)
+	case "public_id", "publicid", "private_id", "privateid", "user_id", "tenant_user_id", "tenant_id", "user_tenant_id":
+		return g.fake(func() string { return fake.CharactersN(30) })
+	case "creditcardnum", "credit_card_number", "credit_card_num", "creditcard", "credit_card":
+		return g.fake(func() string { return fake.CreditCardNum(fake.CreditCardType()) })
+	default:
+		return g.fake(func() string { return fake.CharactersN(20) })
+	}
+}
+
+// namedInt returns a random integer suited to a field with the giving name.
+func (g *ValueGenerator) namedInt(varName string) string {
+	switch varName {
+	case "week":
+		return g.fake(func() string { return fmt.Sprintf("%d", fake.WeekdayNum()) })
+	case "day":
+		return g.fake(func() string { return fmt.Sprintf("%d", fake.Day()) })
+	case "year":
+		return g.fake(func() string { return fmt.Sprintf("%d", fake.Year(1998, 10000)) })
+	default:
+		return fmt.Sprintf("%d", g.int63n(20))
+	}
+}
+
+// character returns a random character.
+func (g *ValueGenerator) character() rune {
+	return rune(g.fake(func() string { return fake.CharactersN(1) })[0])
+}
+
+//===========================================================================================================
+
+// RandomDataTypeValueJSON returns a random value of a giving typeName as a valid JSON value.
+func (g *ValueGenerator) RandomDataTypeValueJSON(typeName string, varName string) string {
+	switch typeName {
+	case "time.Time", "*time.Time", "Time", "time.time":
+		return g.timestamp()
+	case "uint", "uint32", "uint64":
+		return fmt.Sprintf("%d", g.uint64())
+	case "bool":
+		return fmt.Sprintf("%t", g.bool())
+	case "string":
+		return strconv.Quote(g.namedString(varName))
+	case "rune", "byte":
+		return fmt.Sprintf("%d", g.character())
+	case "float32", "float64":
+		return fmt.Sprintf("%.4f", g.float64())
+	case "int", "int32", "int64":
+		return g.namedInt(varName)
+	default:
+		return "null"
+	}
+}
+
+// RandomDataTypeValueWithName returns a random value of a giving typeName as Go source.
+func (g *ValueGenerator) RandomDataTypeValueWithName(typeName string, varName string) string {
+	if value, ok := g.provided(typeName, varName); ok {
+		return value
+	}
+
+	switch typeName {
+	case "time.Time", "*time.Time", "Time", "time.time":
+		return g.timestamp()
+	case "uint64":
+		return fmt.Sprintf("%d", g.uint64())
+	case "bool":
+		return fmt.Sprintf("%t", g.bool())
+	case "string":
+		return strconv.Quote(g.namedString(varName))
+	case "rune", "byte":
+		return fmt.Sprintf("%q", g.character())
+	case "float32", "float64":
+		return fmt.Sprintf("%.4f", g.float64())
+	case "int64":
+		return g.namedInt(varName)
+	default:
+		// Other integer types, such as int32 and uint32, are written within their range by typeNameLiteral.
+		if value, ok := g.typeNameLiteral(typeName, varName, true); ok {
+			return value
+		}
+		return g.DefaultTypeValueString(typeName)
+	}
+}
+
+// RandomDataTypeValue returns a random value of a giving typeName as Go source.
+func (g *ValueGenerator) RandomDataTypeValue(typeName string) string {
+	if value, ok := g.provided(typeName, ""); ok {
+		return value
+	}
+
+	switch typeName {
+	case "time.Time":
+		return g.Now().Format(timeLayout)
+	case "uint64":
+		return fmt.Sprintf("%d", g.uint64())
+	case "bool":
+		return fmt.Sprintf("%t", g.bool())
+	case "string":
+		return fmt.Sprintf("%q", g.fake(fake.Character))
+	case "rune", "byte":
+		return fmt.Sprintf("%q", g.character())
+	case "float32", "float64":
+		return fmt.Sprintf("%.4f", g.float64())
+	case "int64":
+		return fmt.Sprintf("%d", g.int63())
+	default:
+		// Other integer types, such as int32 and uint32, are written within their range by typeNameLiteral.
+		if value, ok := g.typeNameLiteral(typeName, "", true); ok {
+			return value
+		}
+		return g.DefaultTypeValueString(typeName)
+	}
+}
+
+// DefaultTypeValueString returns the default value of a giving typeName as Go source.
+func (g *ValueGenerator) DefaultTypeValueString(typeName string) string {
+	if value, ok := g.provided(typeName, ""); ok {
+		return value
+	}
+
+	switch typeName {
+	case "uint", "uint32", "uint64":
+		return "0"
+	case "bool":
+		return `false`
+	case "time.Time", "*time.Time", "Time", "time.time":
+		return g.timestamp()
+	case "string":
+		return `""`
+	case "rune":
+		return `rune(0)`
+	case "[]uint":
+		return `[]uint{}`
+	case "[]uint64":
+		return `[]uint64{}`
+	case "[]uint32":
+		return `[]uint32{}`
+	case "[]int":
+		return `[]int{}`
+	case "[]int64":
+		return `[]int64{}`
+	case "[]int32":
+		return `[]int32{}`
+	case "[]bool":
+		return `[]bool{}`
+	case "[]string":
+		return `[]string{}`
+	case "[]byte":
+		return `[]byte{}`
+	case "byte":
+		return `byte(rune(0))`
+	case "float32", "float64":
+		return "0.0"
+	case "int", "int32", "int64":
+		return "0"
+	case "map[string]interface{}":
+		return "map[string]interface{}{}"
+	case "map[string]string":
+		return "map[string]string{}"
+	default:
+		if value, ok := g.typeNameLiteral(typeName, "", false); ok {
+			return value
+		}
+		return "nil"
+	}
+}
+
+// DefaultTypeValueJSON returns the default value of a giving typeName as a valid JSON value.
+func (g *ValueGenerator) DefaultTypeValueJSON(typeName string) string {
+	switch typeName {
+	case "time.time", "*time.time", "time":
+		return g.timestamp()
+	case "bool":
+		return "false"
+	case "string":
+		return `""`
+	case "[]byte", "[]uint8":
+		return `""`
+	case "byte", "rune", "uint", "uint8", "uint16", "uint32", "uint64", "int", "int8", "int16", "int32", "int64":
+		return "0"
+	case "float32", "float64":
+		return "0.0"
+	}
+
+	switch {
+	case strings.HasPrefix(typeName, "[]"):
+		return "[]"
+	case strings.HasPrefix(typeName, "map["):
+		return "{}"
+	default:
+		return "null"
+	}
+}
+
+// RandomFieldValue returns a random value for a giving field.
+func (g *ValueGenerator) RandomFieldValue(fld FieldDeclaration) string {
+	if fld.Field != nil {
+		return g.RandomFieldLiteral(fld)
+	}
+	return g.RandomDataTypeValueWithName(fld.FieldTypeName, fld.FieldName)
+}
+
+// DefaultFieldValue returns the default value for a giving field.
+func (g *ValueGenerator) DefaultFieldValue(fld FieldDeclaration) string {
+	if fld.Field != nil {
+		return g.DefaultFieldLiteral(fld)
+	}
+	return g.DefaultTypeValueString(fld.FieldTypeName)
+}
+
+//===========================================================================================================
+
+// AssignDefaultValue will get the fieldName for a giving tag and tagVal and return a string of giving
+// variable name with fieldName equal to a random value.
+func (g *ValueGenerator) AssignDefaultValue(item StructDeclaration, tag string, tagVal string, varName string) (string, error) {
+	fieldName, defaultVal, err := g.DefaultFieldValueFor(item, tag, tagVal)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s.%s = %s", varName, fieldName, defaultVal), nil
+}
+
+// DefaultFieldValueFor returns the name and a random value of the field with the giving tag value.
+func (g *ValueGenerator) DefaultFieldValueFor(item StructDeclaration, tag string, tagVal string) (string, string, error) {
+	fields := Fields(GetFields(item, item.Declr))
+
+	for _, tag := range fields.TagFor(tag) {
+		if tag.Value != tagVal {
+			continue
+		}
+
+		return tag.Field.FieldName, g.RandomFieldValue(tag.Field), nil
+	}
+
+	return "", "", fmt.Errorf("Field for tag value %q not found", tagVal)
+}
+
+// RandomFieldAssign returns a variable assignment declaration of a random value to the first field
+// of the struct whose tag value is not within the exceptions.
+func (g *ValueGenerator) RandomFieldAssign(item StructDeclaration, varName string, tag string, exceptions ...string) (string, error) {
+	randomFieldVal, _, err := RandomFieldWithExcept(item, tag, exceptions...)
+	if err != nil {
+		return "", err
+	}
+
+	return g.AssignDefaultValue(item, tag, randomFieldVal, varName)
+}
+
+// MapOutFieldsToJSON returns the JSON document of the struct fields with default values.
+func (g *ValueGenerator) MapOutFieldsToJSON(item StructDeclaration, tagName, fallback string) (string, error) {
+	return writerToString(g.MapOutFieldsToJSONWriter(item, tagName, fallback))
+}
+
+// MapOutFieldsWithRandomValuesToJSON returns the JSON document of the struct fields with random values.
+func (g *ValueGenerator) MapOutFieldsWithRandomValuesToJSON(item StructDeclaration, tagName, fallback string) (string, error) {
+	return writerToString(g.MapOutFieldsToJSONWriterWithRandomValues(item, tagName, fallback))
+}
+
+// MapOutFieldsToJSONWriter returns the JSON document of the struct fields with default values.
+func (g *ValueGenerator) MapOutFieldsToJSONWriter(item StructDeclaration, tagName, fallback string) (io.WriterTo, error) {
+	return mapOutFieldsToJSONDocument(item, tagName, fallback, func(field FieldDeclaration) string {
+		return g.DefaultTypeValueJSON(strings.ToLower(field.FieldTypeName))
+	})
+}
+
+// MapOutFieldsToJSONWriterWithRandomValues returns the JSON document of the struct fields with random values.
+func (g *ValueGenerator) MapOutFieldsToJSONWriterWithRandomValues(item StructDeclaration, tagName, fallback string) (io.WriterTo, error) {
+	return mapOutFieldsToJSONDocument(item, tagName, fallback, func(field FieldDeclaration) string {
+		return g.RandomDataTypeValueJSON(field.FieldTypeName, field.FieldName)
+	})
+}
+
+// MapOutTypeToJSON returns the default JSON value of the type.
+func (g *ValueGenerator) MapOutTypeToJSON(item TypeDeclaration) (io.WriterTo, error) {
+	if item.Declr == nil {
+		return bytes.NewBuffer(nil), errors.New("TypeDeclaration has no PackageDeclaration field")
+	}
+
+	if item.TypeInfo == nil {
+		return gen.Text(g.DefaultTypeValueJSON(strings.ToLower(item.Type.Name))), nil
+	}
+
+	return gen.Text(g.DefaultTypeValueJSON(strings.ToLower(item.TypeInfo.ExType))), nil
+}
+
+// MapOutTypeToJSONWriterWithRandomValues returns a random JSON value of the type.
+func (g *ValueGenerator) MapOutTypeToJSONWriterWithRandomValues(item TypeDeclaration) (io.WriterTo, error) {
+	if item.Declr == nil {
+		return bytes.NewBuffer(nil), errors.New("TypeDeclaration has no PackageDeclaration field")
+	}
+
+	if item.TypeInfo == nil {
+		return gen.Text(g.RandomDataTypeValueJSON(item.Type.Name, item.Object.Name.Name)), nil
+	}
+
+	return gen.Text(g.RandomDataTypeValueJSON(item.TypeInfo.ExType, item.Object.Name.Name)), nil
+}
+
+// writerToString returns the content written by the writer, passing through any error
+// from the function creating it.
+func writerToString(w io.WriterTo, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+
+	var doc bytes.Buffer
+
+	if _, err := w.WriteTo(&doc); err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return doc.String(), nil
+}
@@ -0,0 +1,94 @@
+package ast_test
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/ast"
+)
+
+// TestValueGeneratorReproducible validates generators with the same seed and clock produce
+// the same values.
+func TestValueGeneratorReproducible(t *testing.T) {
+	clock := ast.FixedClock(time.Date(2017, 6, 1, 10, 30, 0, 0, time.UTC))
+
+	values := func(gen *ast.ValueGenerator) []string {
+		return []string{
+			gen.RandomDataTypeValueWithName("string", "email"),
+			gen.RandomDataTypeValueWithName("string", "full_name"),
+			gen.RandomDataTypeValueJSON("int", "year"),
+			gen.RandomDataTypeValueJSON("float64", "ratio"),
+			gen.RandomDataTypeValue("uint64"),
+			gen.RandomDataTypeValue("rune"),
+			gen.DefaultTypeValueString("time.Time"),
+		}
+	}
+
+	first := values(ast.NewValueGenerator(42, clock))
+	second := values(ast.NewValueGenerator(42, clock))
+
+	for index := range first {
+		if first[index] != second[index] {
+			tests.Info("First: %+q", first)
+			tests.Info("Second: %+q", second)
+			tests.Failed("Should have successfully produced the same values from the same seed")
+		}
+	}
+	tests.Passed("Should have successfully produced the same values from the same seed")
+
+	if first[len(first)-1] != `"2017-06-01T10:30:00Z"` {
+		tests.Info("Time: %s", first[len(first)-1])
+		tests.Failed("Should have successfully used generator clock for time values")
+	}
+	tests.Passed("Should have successfully used generator clock for time values")
+
+	gen := ast.NewValueGenerator(7, clock)
+	third := values(gen)
+	gen.Reset()
+
+	for index, value := range values(gen) {
+		if value != third[index] {
+			tests.Failed("Should have successfully reproduced values after reset")
+		}
+	}
+	tests.Passed("Should have successfully reproduced values after reset")
+}
+
+// TestValueGeneratorBool validates random booleans produce both values.
+func TestValueGeneratorBool(t *testing.T) {
+	gen := ast.NewValueGenerator(1, nil)
+
+	seen := map[string]bool{}
+	for i := 0; i < 64; i++ {
+		seen[gen.RandomDataTypeValue("bool")] = true
+	}
+
+	if !seen["true"] || !seen["false"] {
+		tests.Failed("Should have successfully produced both true and false values")
+	}
+	tests.Passed("Should have successfully produced both true and false values")
+}
+
+// TestValueGeneratorIntegers validates random integers of every sized type compile within
+// the range of their type.
+func TestValueGeneratorIntegers(t *testing.T) {
+	gen := ast.NewValueGenerator(3, nil)
+
+	var source []string
+	for _, typeName := range []string{"int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64"} {
+		for i := 0; i < 32; i++ {
+			source = append(source,
+				fmt.Sprintf("var _ %s = %s", typeName, gen.RandomDataTypeValue(typeName)),
+				fmt.Sprintf("var _ %s = %s", typeName, gen.RandomDataTypeValueWithName(typeName, "count")),
+			)
+		}
+	}
+
+	// Sizes of a 32-bit platform catch int and uint values overflowing there.
+	typeCheck(types.Config{Sizes: types.SizesFor("gc", "386")}, sourceFile{Name: "check.go", Source: "package check\n" + strings.Join(source, "\n")})
+	tests.Passed("Should have successfully generated integers within the range of their type")
+}
@@ -0,0 +1,462 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//======================================================================================================================
+
+// JSONDocumentDeclr defines a declaration which writes a value as a valid JSON document.
+// Values can be nested maps with string keys, slices, scalars, json.Number and io.WriterTo
+// values, where the latter must write a valid JSON fragment. Object keys are always written
+// in sorted order. An empty Indent writes the document in compact form.
+type JSONDocumentDeclr struct {
+	Value  interface{} `json:"value"`
+	Indent string      `json:"indent"`
+}
+
+// WriteTo writes to the provided writer the JSON document.
+func (j JSONDocumentDeclr) WriteTo(w io.Writer) (int64, error) {
+	value, err := normalizeDocument(j.Value, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	var bu bytes.Buffer
+	writeJSONValue(&bu, value, j.Indent, 0)
+
+	written, err := NewNoBOM(w).Write(bu.Bytes())
+	return int64(written), err
+}
+
+// YAMLDocumentDeclr defines a declaration which writes a value as a block style YAML document.
+// It accepts the same values as the JSONDocumentDeclr. Mapping keys are always written in
+// sorted order and Indent defaults to two spaces.
+type YAMLDocumentDeclr struct {
+	Value  interface{} `json:"value"`
+	Indent string      `json:"indent"`
+}
+
+// WriteTo writes to the provided writer the YAML document.
+func (y YAMLDocumentDeclr) WriteTo(w io.Writer) (int64, error) {
+	value, err := normalizeDocument(y.Value, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	indent := y.Indent
+	if indent == "" {
+		indent = "  "
+	}
+
+	var bu bytes.Buffer
+
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		if isEmptyDocument(value) {
+			writeYAMLScalar(&bu, value)
+			bu.WriteByte('\n')
+			break
+		}
+
+		writeYAMLBlock(&bu, value, indent, 0)
+	default:
+		writeYAMLScalar(&bu, value)
+		bu.WriteByte('\n')
+	}
+
+	written, err := NewNoBOM(w).Write(bu.Bytes())
+	return int64(written), err
+}
+
+//======================================================================================================================
+
+// maxDocumentDepth sets the maximum nesting allowed within a document to guard against cyclic values.
+const maxDocumentDepth = 512
+
+// normalizeDocument converts the value into a tree made only of nil, bool, string, json.Number,
+// map[string]interface{} and []interface{} values.
+func normalizeDocument(value interface{}, depth int) (interface{}, error) {
+	if depth > maxDocumentDepth {
+		return nil, errors.New("document exceeds maximum nesting depth, possible cyclic value")
+	}
+
+	switch item := value.(type) {
+	case nil:
+		return nil, nil
+	case bool:
+		return item, nil
+	case string:
+		return item, nil
+	case json.Number:
+		return parseJSONFragment([]byte(item), depth)
+	case json.RawMessage:
+		return parseJSONFragment(item, depth)
+	case float64:
+		return floatNumber(item, 64)
+	case float32:
+		return floatNumber(float64(item), 32)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return json.Number(fmt.Sprintf("%d", item)), nil
+	case JSONDocumentDeclr:
+		return normalizeDocument(item.Value, depth+1)
+	case YAMLDocumentDeclr:
+		return normalizeDocument(item.Value, depth+1)
+	case JSONBlock:
+		return normalizeDocument(item.Items, depth+1)
+	case JSONDeclr:
+		return normalizeDocument(item.Documents, depth+1)
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(item))
+		for key, elem := range item {
+			value, err := normalizeDocument(elem, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			normalized[key] = value
+		}
+
+		return normalized, nil
+	case []interface{}:
+		normalized := make([]interface{}, 0, len(item))
+		for _, elem := range item {
+			value, err := normalizeDocument(elem, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			normalized = append(normalized, value)
+		}
+
+		return normalized, nil
+	case io.WriterTo:
+		var bu bytes.Buffer
+		if _, err := item.WriteTo(&bu); IsNotDrainError(err) {
+			return nil, err
+		}
+
+		return parseJSONFragment(bu.Bytes(), depth)
+	}
+
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+
+		return normalizeDocument(rv.Elem().Interface(), depth+1)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		normalized := make(map[string]interface{}, rv.Len())
+		for _, key := range rv.MapKeys() {
+			value, err := normalizeDocument(rv.MapIndex(key).Interface(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			normalized[key.String()] = value
+		}
+
+		return normalized, nil
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return []interface{}{}, nil
+		}
+
+		normalized := make([]interface{}, 0, rv.Len())
+		for index := 0; index < rv.Len(); index++ {
+			value, err := normalizeDocument(rv.Index(index).Interface(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			normalized = append(normalized, value)
+		}
+
+		return normalized, nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	}
+
+	// Any other value is left to encoding/json, which handles structs and their tags.
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseJSONFragment(data, depth)
+}
+
+// parseJSONFragment parses a JSON fragment into a normalized document value, returning an error
+// if it is not a single valid JSON value.
+func parseJSONFragment(data []byte, depth int) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON fragment %q: %s", data, err)
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid JSON fragment %q: unexpected trailing data", data)
+	}
+
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return normalizeDocument(value, depth+1)
+	}
+
+	return value, nil
+}
+
+// floatNumber returns the float as a json.Number using the shortest representation.
+func floatNumber(value float64, bitSize int) (interface{}, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, fmt.Errorf("unsupported float value %v in document", value)
+	}
+
+	return json.Number(strconv.FormatFloat(value, 'g', -1, bitSize)), nil
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys(item map[string]interface{}) []string {
+	keys := make([]string, 0, len(item))
+	for key := range item {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// isEmptyDocument returns true/false if the value is an empty map or slice.
+func isEmptyDocument(value interface{}) bool {
+	switch item := value.(type) {
+	case map[string]interface{}:
+		return len(item) == 0
+	case []interface{}:
+		return len(item) == 0
+	}
+	return false
+}
+
+//======================================================================================================================
+
+// writeJSONValue writes the normalized value as JSON into the buffer.
+func writeJSONValue(bu *bytes.Buffer, value interface{}, indent string, depth int) {
+	switch item := value.(type) {
+	case map[string]interface{}:
+		if len(item) == 0 {
+			bu.WriteString("{}")
+			return
+		}
+
+		bu.WriteByte('{')
+		for index, key := range sortedKeys(item) {
+			if index > 0 {
+				bu.WriteByte(',')
+			}
+
+			writeJSONNewline(bu, indent, depth+1)
+			writeJSONString(bu, key)
+			bu.WriteByte(':')
+
+			if indent != "" {
+				bu.WriteByte(' ')
+			}
+
+			writeJSONValue(bu, item[key], indent, depth+1)
+		}
+
+		writeJSONNewline(bu, indent, depth)
+		bu.WriteByte('}')
+	case []interface{}:
+		if len(item) == 0 {
+			bu.WriteString("[]")
+			return
+		}
+
+		bu.WriteByte('[')
+		for index, elem := range item {
+			if index > 0 {
+				bu.WriteByte(',')
+			}
+
+			writeJSONNewline(bu, indent, depth+1)
+			writeJSONValue(bu, elem, indent, depth+1)
+		}
+
+		writeJSONNewline(bu, indent, depth)
+		bu.WriteByte(']')
+	case string:
+		writeJSONString(bu, item)
+	case json.Number:
+		bu.WriteString(string(item))
+	case bool:
+		bu.WriteString(strconv.FormatBool(item))
+	default:
+		bu.WriteString("null")
+	}
+}
+
+// writeJSONNewline writes a newline followed by the indentation for depth, if indent is set.
+func writeJSONNewline(bu *bytes.Buffer, indent string, depth int) {
+	if indent == "" {
+		return
+	}
+
+	bu.WriteByte('\n')
+	bu.WriteString(strings.Repeat(indent, depth))
+}
+
+// writeJSONString writes the string as a quoted and escaped JSON string.
+func writeJSONString(bu *bytes.Buffer, value string) {
+	bu.WriteByte('"')
+
+	for _, r := range value {
+		switch r {
+		case '"':
+			bu.WriteString(`\"`)
+		case '\\':
+			bu.WriteString(`\\`)
+		case '\n':
+			bu.WriteString(`\n`)
+		case '\r':
+			bu.WriteString(`\r`)
+		case '\t':
+			bu.WriteString(`\t`)
+		case '\u2028', '\u2029':
+			fmt.Fprintf(bu, `\u%04x`, r)
+		default:
+			if r < 0x20 || r == unicode.ReplacementChar {
+				fmt.Fprintf(bu, `\u%04x`, r)
+				continue
+			}
+
+			bu.WriteRune(r)
+		}
+	}
+
+	bu.WriteByte('"')
+}
+
+//======================================================================================================================
+
+// writeYAMLBlock writes a non-empty map or slice as a block style YAML node at the giving depth.
+func writeYAMLBlock(bu *bytes.Buffer, value interface{}, indent string, depth int) {
+	prefix := strings.Repeat(indent, depth)
+
+	switch item := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(item) {
+			bu.WriteString(prefix)
+			writeYAMLString(bu, key)
+			bu.WriteByte(':')
+			writeYAMLChild(bu, item[key], indent, depth)
+		}
+	case []interface{}:
+		for _, elem := range item {
+			bu.WriteString(prefix)
+			bu.WriteByte('-')
+			writeYAMLChild(bu, elem, indent, depth)
+		}
+	}
+}
+
+// writeYAMLChild writes the value of a mapping entry or sequence item, either inline for
+// scalars and empty collections or as a nested block on the following lines.
+func writeYAMLChild(bu *bytes.Buffer, value interface{}, indent string, depth int) {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		if !isEmptyDocument(value) {
+			bu.WriteByte('\n')
+			writeYAMLBlock(bu, value, indent, depth+1)
+			return
+		}
+	}
+
+	bu.WriteByte(' ')
+	writeYAMLScalar(bu, value)
+	bu.WriteByte('\n')
+}
+
+// writeYAMLScalar writes a scalar or empty collection in flow style.
+func writeYAMLScalar(bu *bytes.Buffer, value interface{}) {
+	switch item := value.(type) {
+	case map[string]interface{}:
+		bu.WriteString("{}")
+	case []interface{}:
+		bu.WriteString("[]")
+	case string:
+		writeYAMLString(bu, item)
+	case json.Number:
+		bu.WriteString(string(item))
+	case bool:
+		bu.WriteString(strconv.FormatBool(item))
+	default:
+		bu.WriteString("null")
+	}
+}
+
+// writeYAMLString writes the string as a plain scalar when it can not be mistaken for another
+// value, otherwise as a double quoted scalar using JSON escaping, which YAML accepts.
+func writeYAMLString(bu *bytes.Buffer, value string) {
+	if isPlainYAML(value) {
+		bu.WriteString(value)
+		return
+	}
+
+	writeJSONString(bu, value)
+}
+
+// yamlReserved contains plain scalars YAML resolves to non-string values.
+var yamlReserved = map[string]bool{
+	"": true, "~": true, "null": true, "true": true, "false": true, "yes": true, "no": true,
+	"on": true, "off": true, "y": true, "n": true, ".inf": true, "-.inf": true, "+.inf": true, ".nan": true,
+}
+
+// isPlainYAML returns true/false if the string can be written as a plain YAML scalar.
+func isPlainYAML(value string) bool {
+	if yamlReserved[strings.ToLower(value)] {
+		return false
+	}
+
+	if strings.TrimSpace(value) != value || strings.ContainsAny(value[:1], "-?:,[]{}#&*!|>'\"%@`.+0123456789") {
+		return false
+	}
+
+	if strings.Contains(value, ": ") || strings.Contains(value, " #") || strings.HasSuffix(value, ":") {
+		return false
+	}
+
+	for _, r := range value {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+
+	return true
+}
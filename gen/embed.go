@@ -0,0 +1,277 @@
+package gen
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/influx6/moz/gen/templates"
+)
+
+// EmbedFileDeclr defines a file, or a directory if Dir is true, embedded by an EmbedFSDeclr at its
+// slash separated path relative to the root (e.g app/src/main.go). A zero Mode gives files 0644 and
+// directories 0755.
+type EmbedFileDeclr struct {
+	Path    string      `json:"path"`
+	Dir     bool        `json:"dir"`
+	Mode    fs.FileMode `json:"mode"`
+	ModTime time.Time   `json:"mod_time"`
+	Data    []byte      `json:"data"`
+}
+
+// EmbedFSDeclr defines a declaration for a variable serving embedded files as a fs.FS, along with
+// the unexported types implementing it, which are prefixed with the name of the variable. Parent
+// directories of files are embedded even if not declared.
+//
+// If Gzip is true, contents which are made smaller by gzip are embedded compressed and decompressed
+// once when first read, with a Compressed method returning them as embedded. If Hashes is true, Hash
+// and ETag methods return the SHA-256 hash of contents. The generated source requires the packages
+// returned by Imports.
+type EmbedFSDeclr struct {
+	Name     NameDeclr        `json:"name"`
+	Comments io.WriterTo      `json:"comments"`
+	Files    []EmbedFileDeclr `json:"files"`
+	Gzip     bool             `json:"gzip"`
+	Hashes   bool             `json:"hashes"`
+}
+
+// Imports returns the import paths required by the generated source.
+func (e EmbedFSDeclr) Imports() []string {
+	return []string{"bytes", "compress/gzip", "errors", "io", "io/fs", "path", "strings", "sync", "time"}
+}
+
+// embedEntry defines an entry of the embedded map as written by the embed template.
+type embedEntry struct {
+	Path   string
+	Fields string
+}
+
+// WriteTo writes to the provided writer the embedded filesystem declaration.
+func (e EmbedFSDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("embedDeclr", templates.Must("embed.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(e.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	files, err := e.entries()
+	if err != nil {
+		return 0, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+
+	sort.Strings(paths)
+
+	entries := make([]embedEntry, 0, len(paths))
+	for _, filePath := range paths {
+		fields, err := e.fields(filePath, files[filePath])
+		if err != nil {
+			return 0, err
+		}
+
+		entries = append(entries, embedEntry{Path: strconv.Quote(filePath), Fields: fields})
+	}
+
+	name := e.Name.String()
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name     string
+		Prefix   string
+		Comments string
+		Hashes   bool
+		Entries  []embedEntry
+	}{
+		Name:     name,
+		Prefix:   embedPrefix(name),
+		Comments: strings.TrimRight(comments, "\n"),
+		Hashes:   e.Hashes,
+		Entries:  entries,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// embedFile defines a file or directory of an EmbedFSDeclr, with the names of the entries of a directory.
+type embedFile struct {
+	EmbedFileDeclr
+	entries map[string]bool
+}
+
+// entries returns the files and directories of the declaration by cleaned path, including the root
+// and parent directories which are not declared.
+func (e EmbedFSDeclr) entries() (map[string]*embedFile, error) {
+	files := map[string]*embedFile{
+		".": {EmbedFileDeclr: EmbedFileDeclr{Path: ".", Dir: true}, entries: map[string]bool{}},
+	}
+
+	var ensureDir func(dirPath string) (*embedFile, error)
+	ensureDir = func(dirPath string) (*embedFile, error) {
+		if dir, ok := files[dirPath]; ok {
+			if !dir.Dir {
+				return nil, fmt.Errorf("Embedded file %q is also a directory", dirPath)
+			}
+			return dir, nil
+		}
+
+		parent, err := ensureDir(path.Dir(dirPath))
+		if err != nil {
+			return nil, err
+		}
+
+		parent.entries[path.Base(dirPath)] = true
+
+		dir := &embedFile{EmbedFileDeclr: EmbedFileDeclr{Path: dirPath, Dir: true}, entries: map[string]bool{}}
+		files[dirPath] = dir
+
+		return dir, nil
+	}
+
+	for _, file := range e.Files {
+		filePath := path.Clean(file.Path)
+		if !fs.ValidPath(filePath) {
+			return nil, fmt.Errorf("Embedded path %q is not a valid relative path", file.Path)
+		}
+
+		file.Path = filePath
+
+		if file.Dir {
+			dir, err := ensureDir(filePath)
+			if err != nil {
+				return nil, err
+			}
+
+			dir.Mode, dir.ModTime = file.Mode, file.ModTime
+			continue
+		}
+
+		if filePath == "." {
+			return nil, fmt.Errorf("Embedded file %q has no name", file.Path)
+		}
+
+		if existing, ok := files[filePath]; ok && existing.Dir {
+			return nil, fmt.Errorf("Embedded file %q is also a directory", filePath)
+		}
+
+		parent, err := ensureDir(path.Dir(filePath))
+		if err != nil {
+			return nil, err
+		}
+
+		parent.entries[path.Base(filePath)] = true
+		files[filePath] = &embedFile{EmbedFileDeclr: file}
+	}
+
+	return files, nil
+}
+
+// fields returns the fields of the entry literal of the file, with its content compressed and
+// hashed as the declaration requires.
+func (e EmbedFSDeclr) fields(filePath string, file *embedFile) (string, error) {
+	var out bytes.Buffer
+
+	name := path.Base(filePath)
+	if filePath == "." {
+		name = "."
+	}
+
+	fmt.Fprintf(&out, "name: %s, mode: %s", strconv.Quote(name), embedMode(file.Mode, file.Dir))
+
+	if !file.ModTime.IsZero() {
+		fmt.Fprintf(&out, ", modTime: time.Unix(%d, %d)", file.ModTime.Unix(), file.ModTime.Nanosecond())
+	}
+
+	if file.Dir {
+		names := make([]string, 0, len(file.entries))
+		for entry := range file.entries {
+			names = append(names, strconv.Quote(entry))
+		}
+
+		sort.Strings(names)
+		fmt.Fprintf(&out, ", entries: []string{%s}", strings.Join(names, ", "))
+
+		return out.String(), nil
+	}
+
+	fmt.Fprintf(&out, ", size: %d", len(file.Data))
+
+	if e.Hashes {
+		sum := sha256.Sum256(file.Data)
+		fmt.Fprintf(&out, ", hash: %q", hex.EncodeToString(sum[:]))
+	}
+
+	data := file.Data
+
+	if e.Gzip && len(data) != 0 {
+		var compressed bytes.Buffer
+
+		gzw, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+		if err != nil {
+			return "", err
+		}
+
+		if _, err := gzw.Write(data); err != nil {
+			return "", err
+		}
+
+		if err := gzw.Close(); err != nil {
+			return "", err
+		}
+
+		if compressed.Len() < len(data) {
+			data = compressed.Bytes()
+			out.WriteString(", gzipped: true")
+		}
+	}
+
+	fmt.Fprintf(&out, ", data: %s", strconv.Quote(string(data)))
+
+	return out.String(), nil
+}
+
+// embedMode returns the Go expression of the mode of an embedded file or directory.
+func embedMode(mode fs.FileMode, dir bool) string {
+	perm := mode.Perm()
+
+	if dir {
+		if perm == 0 {
+			perm = 0755
+		}
+		return fmt.Sprintf("fs.ModeDir | %#o", uint32(perm))
+	}
+
+	if perm == 0 {
+		perm = 0644
+	}
+
+	return fmt.Sprintf("%#o", uint32(perm))
+}
+
+// embedPrefix returns the unexported prefix of the types of an embedded filesystem variable.
+func embedPrefix(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(first)) + name[size:]
+}
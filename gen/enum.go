@@ -0,0 +1,101 @@
+package gen
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/influx6/moz/gen/templates"
+)
+
+//======================================================================================================================
+
+// EnumValueDeclr defines a constant of an enum type along with the name it is written and
+// parsed as.
+type EnumValueDeclr struct {
+	Constant string `json:"constant"`
+	Name     string `json:"name"`
+}
+
+// EnumDeclr defines a declaration for the methods of a Go enum type, which are String,
+// Values, IsValid, MarshalText and UnmarshalText along with a Parse function for the
+// type. The generated source requires the "fmt" package and "strings" if IgnoreCase is set.
+type EnumDeclr struct {
+	Name       NameDeclr        `json:"name"`
+	Underlying TypeDeclr        `json:"underlying"`
+	IgnoreCase bool             `json:"ignore_case"`
+	Comments   io.WriterTo      `json:"comments"`
+	Values     []EnumValueDeclr `json:"values"`
+}
+
+// WriteTo writes to the provided writer the enum declaration.
+func (e EnumDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	if len(e.Values) == 0 {
+		return 0, errors.New("EnumDeclr requires at least one value")
+	}
+
+	tml, err := ToTemplate("enumDeclr", templates.Must("enum.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(e.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	type enumValue struct {
+		Constant string
+		Key      string
+		Text     string
+	}
+
+	var values []enumValue
+	var constants []string
+
+	for _, value := range e.Values {
+		key := value.Name
+		if e.IgnoreCase {
+			key = strings.ToLower(key)
+		}
+
+		constants = append(constants, value.Constant)
+		values = append(values, enumValue{
+			Constant: value.Constant,
+			Key:      strconv.Quote(key),
+			Text:     strconv.Quote(value.Name),
+		})
+	}
+
+	name := e.Name.String()
+	first, _ := utf8.DecodeRuneInString(name)
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name       string
+		Receiver   string
+		Underlying string
+		IgnoreCase bool
+		Comments   string
+		Constants  []string
+		Values     []enumValue
+	}{
+		Name:       name,
+		Receiver:   string(unicode.ToLower(first)),
+		Underlying: e.Underlying.String(),
+		IgnoreCase: e.IgnoreCase,
+		Comments:   strings.TrimRight(comments, "\n"),
+		Constants:  constants,
+		Values:     values,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
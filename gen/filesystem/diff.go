@@ -0,0 +1,438 @@
+package filesystem
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// diffContext defines the number of unchanged lines surrounding the changes of a hunk of a unified diff.
+const diffContext = 3
+
+// maxDiffEdits defines the number of line edits searched for when diffing contents, beyond which
+// the differing lines are replaced as a whole.
+const maxDiffEdits = 2000
+
+// ChangeKind defines the kind of change made to a file between two filesystems.
+type ChangeKind int
+
+// Kinds of changes made to a file.
+const (
+	Added ChangeKind = iota + 1
+	Removed
+	Modified
+)
+
+// String returns the name of the kind of change.
+func (kind ChangeKind) String() string {
+	switch kind {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	default:
+		return "unknown"
+	}
+}
+
+// Change defines a file which differs between two filesystems, at its path as given by
+// MemoryFileSystem.Files. From is the file before the change and To the file after it, either
+// being the zero FileWriter for added and removed files. Diff holds the unified diff of text
+// contents, and is empty if either content is binary or only the mode changed.
+type Change struct {
+	Path string
+	Kind ChangeKind
+	From FileWriter
+	To   FileWriter
+	Diff string
+}
+
+// Diff returns the changes which turn the files of from into those of to, sorted by path. Files
+// are modified if their content, link target or mode differ, while directories are not compared.
+// Contents are read as by ReadFile.
+func Diff(from MemoryFileSystem, to MemoryFileSystem) ([]Change, error) {
+	fromFiles, err := filesByPath(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toFiles, err := filesByPath(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+
+	for _, filePath := range unionPaths(fromFiles, toFiles) {
+		before, inFrom := fromFiles[filePath]
+		after, inTo := toFiles[filePath]
+
+		var change Change
+		change.Path = filePath
+
+		switch {
+		case !inFrom:
+			change.Kind = Added
+			change.To = after.file
+		case !inTo:
+			change.Kind = Removed
+			change.From = before.file
+		case before.equal(after):
+			continue
+		default:
+			change.Kind = Modified
+			change.From, change.To = before.file, after.file
+		}
+
+		if before.text() && after.text() {
+			fromName, toName := "a/"+filePath, "b/"+filePath
+			if !inFrom {
+				fromName = "/dev/null"
+			}
+			if !inTo {
+				toName = "/dev/null"
+			}
+
+			change.Diff = UnifiedDiff(fromName, toName, before.data, after.data)
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// UnifiedDiff returns the unified diff turning the from content into the to content, with the
+// giving names in its header, or an empty string if they do not differ. Contents needing more than
+// 2000 line edits are given a single hunk replacing their differing lines, which bounds the memory used.
+func UnifiedDiff(fromName string, toName string, from []byte, to []byte) string {
+	fromLines, toLines := splitLines(from), splitLines(to)
+	ops := diffLines(fromLines, toLines)
+
+	var out bytes.Buffer
+
+	for _, hunk := range diffHunks(ops) {
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+
+		var fromCount, toCount int
+		for _, op := range hunk {
+			if op.kind != '+' {
+				fromCount++
+			}
+			if op.kind != '-' {
+				toCount++
+			}
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(hunk[0].from, fromCount), hunkRange(hunk[0].to, toCount))
+
+		for _, op := range hunk {
+			var line string
+			if op.kind == '-' {
+				line = fromLines[op.from]
+			} else {
+				line = toLines[op.to]
+			}
+
+			out.WriteByte(op.kind)
+			out.WriteString(line)
+
+			if !strings.HasSuffix(line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	return out.String()
+}
+
+// hunkRange returns the range of lines of a hunk header, where start is the index of its first line.
+func hunkRange(start int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+//======================================================================================
+
+// pathFile defines a file of a filesystem along with its content.
+type pathFile struct {
+	file FileWriter
+	data []byte
+}
+
+// filesByPath returns the files of the filesystem by path, with their content, where earlier
+// files shadow later ones of the same path. Files are given their own copy of the content, which
+// is read as by ReadFile: the contents of Content and ContentFrom can be written again afterwards,
+// while other contents which can only be written once are consumed.
+func filesByPath(fsm MemoryFileSystem) (map[string]pathFile, error) {
+	files := make(map[string]pathFile)
+
+	err := fsm.Files(func(hostFilePath string, hostFile FileWriter) error {
+		if _, ok := files[hostFilePath]; ok {
+			return nil
+		}
+
+		data, err := contentBytes(hostFile)
+		if err != nil {
+			return fmt.Errorf("IOError: Unable to read content of %q: %+q", hostFilePath, err)
+		}
+
+		if hostFile.Content != nil {
+			hostFile.Content = ContentByte(data)
+		}
+
+		files[hostFilePath] = pathFile{file: hostFile, data: data}
+		return nil
+	})
+
+	return files, err
+}
+
+// unionPaths returns the sorted paths found in any of the giving sets of files.
+func unionPaths(sets ...map[string]pathFile) []string {
+	seen := make(map[string]bool)
+
+	var paths []string
+	for _, set := range sets {
+		for filePath := range set {
+			if !seen[filePath] {
+				seen[filePath] = true
+				paths = append(paths, filePath)
+			}
+		}
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// equal returns true/false if both files have the same content, link target and mode.
+func (pf pathFile) equal(other pathFile) bool {
+	return pf.file.Link == other.file.Link && pf.file.FileMode() == other.file.FileMode() && bytes.Equal(pf.data, other.data)
+}
+
+// text returns true/false if the file is not a link and its content is text, being valid UTF-8
+// without NUL bytes. Missing files are empty text.
+func (pf pathFile) text() bool {
+	return pf.file.Link == "" && utf8.Valid(pf.data) && bytes.IndexByte(pf.data, 0) == -1
+}
+
+//======================================================================================
+
+// lineOp defines an operation of a line diff, which keeps (' '), removes ('-') or adds ('+') a
+// line, with the indexes of the line in the from and to lines it is at.
+type lineOp struct {
+	kind byte
+	from int
+	to   int
+}
+
+// splitLines returns the lines of the content, each with its line feed if it has one.
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines returns the shortest edit script turning the from lines into the to lines, as found
+// by the Myers diff algorithm, after setting aside their common prefix and suffix.
+func diffLines(from []string, to []string) []lineOp {
+	var prefix int
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		prefix++
+	}
+
+	var suffix int
+	for suffix < len(from)-prefix && suffix < len(to)-prefix && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+
+	var ops []lineOp
+	for index := 0; index < prefix; index++ {
+		ops = append(ops, lineOp{kind: ' ', from: index, to: index})
+	}
+
+	for _, op := range myers(from[prefix:len(from)-suffix], to[prefix:len(to)-suffix]) {
+		op.from += prefix
+		op.to += prefix
+		ops = append(ops, op)
+	}
+
+	for index := suffix; index > 0; index-- {
+		ops = append(ops, lineOp{kind: ' ', from: len(from) - index, to: len(to) - index})
+	}
+
+	return ops
+}
+
+// myers returns the shortest edit script turning a into b. Removals carry the index in b the
+// following line is at, and additions the index in a, so every operation knows its position.
+//
+// The furthest reaching paths of each step are kept for the diagonals reached so far only, which
+// takes memory in the square of the number of edits. Beyond maxDiffEdits edits, the script removes
+// all lines of a and adds all lines of b instead.
+func myers(a []string, b []string) []lineOp {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+
+	v := make([]int, 2*max+3)
+
+	var trace [][]int
+	found := false
+
+search:
+	for d := 0; d <= max && d <= maxDiffEdits; d++ {
+		// Step d only reads the diagonals -d+1 to d-1 reached by the steps before it.
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				found = true
+				break search
+			}
+		}
+	}
+
+	if !found {
+		return replaceLines(n, m)
+	}
+
+	var ops []lineOp
+
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		// The snapshot of step d holds diagonal k at index k+d.
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[k-1+d] < v[k+1+d]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		var prevX int
+		if d > 0 {
+			prevX = v[prevK+d]
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, lineOp{kind: ' ', from: x, to: y})
+		}
+
+		if d == 0 {
+			break
+		}
+
+		if x == prevX {
+			ops = append(ops, lineOp{kind: '+', from: x, to: y - 1})
+		} else {
+			ops = append(ops, lineOp{kind: '-', from: x - 1, to: y})
+		}
+
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+// replaceLines returns the edit script removing all n lines of a and adding all m lines of b.
+func replaceLines(n int, m int) []lineOp {
+	ops := make([]lineOp, 0, n+m)
+	for index := 0; index < n; index++ {
+		ops = append(ops, lineOp{kind: '-', from: index, to: 0})
+	}
+
+	for index := 0; index < m; index++ {
+		ops = append(ops, lineOp{kind: '+', from: n, to: index})
+	}
+
+	return ops
+}
+
+// diffHunks groups the changes of the edit script into hunks, each surrounded by up to diffContext
+// unchanged lines, merging hunks whose context would overlap.
+func diffHunks(ops []lineOp) [][]lineOp {
+	var hunks [][]lineOp
+
+	start, end := -1, -1
+	for index, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+
+		if start != -1 && index-end <= 2*diffContext {
+			end = index + 1
+			continue
+		}
+
+		if start != -1 {
+			hunks = append(hunks, ops[start:minInt(end+diffContext, len(ops))])
+		}
+
+		start, end = maxInt(index-diffContext, 0), index+1
+	}
+
+	if start != -1 {
+		hunks = append(hunks, ops[start:minInt(end+diffContext, len(ops))])
+	}
+
+	return hunks
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,159 @@
+package filesystem_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/gen/filesystem"
+)
+
+func TestDiff(t *testing.T) {
+	from := filesystem.FileSystem(
+		filesystem.File("readme.md", filesystem.Content("# App\n\nBuilt by moz.\n")),
+		filesystem.File("logo.png", filesystem.ContentByte([]byte{0x89, 'P', 'N', 'G', 0})),
+		filesystem.Dir(
+			"app",
+			filesystem.File("main.go", filesystem.Content("package main\n\nfunc main() {\n\tprintln(\"v1\")\n}\n")),
+			filesystem.File("old.go", filesystem.Content("package main\n")),
+			filesystem.File("run.sh", filesystem.Content("#!/bin/sh\n")),
+		),
+	)
+
+	to := filesystem.FileSystem(
+		filesystem.File("readme.md", filesystem.Content("# App\n\nBuilt by moz.\n")),
+		filesystem.File("logo.png", filesystem.ContentByte([]byte{0x89, 'P', 'N', 'G', 1})),
+		filesystem.Dir(
+			"app",
+			filesystem.File("main.go", filesystem.Content("package main\n\nfunc main() {\n\tprintln(\"v2\")\n}\n")),
+			filesystem.File("new.go", filesystem.Content("package main")),
+			filesystem.Executable("run.sh", filesystem.Content("#!/bin/sh\n")),
+		),
+	)
+
+	changes, err := filesystem.Diff(from, to)
+	if err != nil {
+		tests.Failed("Should have successfully diffed filesystems: %+q", err)
+	}
+	tests.Passed("Should have successfully diffed filesystems")
+
+	expected := []struct {
+		path string
+		kind filesystem.ChangeKind
+		diff string
+	}{
+		{"app/main.go", filesystem.Modified, "--- a/app/main.go\n+++ b/app/main.go\n@@ -1,5 +1,5 @@\n package main\n \n func main() {\n-\tprintln(\"v1\")\n+\tprintln(\"v2\")\n }\n"},
+		{"app/new.go", filesystem.Added, "--- /dev/null\n+++ b/app/new.go\n@@ -0,0 +1 @@\n+package main\n\\ No newline at end of file\n"},
+		{"app/old.go", filesystem.Removed, "--- a/app/old.go\n+++ /dev/null\n@@ -1 +0,0 @@\n-package main\n"},
+		{"app/run.sh", filesystem.Modified, ""},
+		{"logo.png", filesystem.Modified, ""},
+	}
+
+	if len(changes) != len(expected) {
+		tests.Info("Changes: %+v", changes)
+		tests.Failed("Should have successfully found %d changes", len(expected))
+	}
+	tests.Passed("Should have successfully found %d changes", len(expected))
+
+	for index, item := range expected {
+		change := changes[index]
+		if change.Path != item.path || change.Kind != item.kind {
+			tests.Info("Change: %q %s", change.Path, change.Kind)
+			tests.Failed("Should have successfully found %q as %s", item.path, item.kind)
+		}
+
+		if change.Diff != item.diff {
+			tests.Info("Diff: %q", change.Diff)
+			tests.Failed("Should have successfully matched unified diff of %q", item.path)
+		}
+	}
+	tests.Passed("Should have successfully matched changes and unified diffs")
+
+	if file, err := from.GetFile("app/old.go"); err != nil || file.Content.(*bytes.Buffer).Len() == 0 {
+		tests.Failed("Should have successfully left contents unconsumed")
+	}
+	tests.Passed("Should have successfully left contents unconsumed")
+}
+
+func TestDiffReaderContent(t *testing.T) {
+	from := filesystem.FileSystem(filesystem.File("main.go", filesystem.Content("package main\n")))
+	to := filesystem.FileSystem(filesystem.File("main.go", filesystem.ContentFrom(strings.NewReader("package app\n"))))
+
+	if _, err := filesystem.Diff(from, to); err != nil {
+		tests.Failed("Should have successfully diffed filesystems: %+q", err)
+	}
+
+	var archive bytes.Buffer
+	if _, err := filesystem.ZipFS(to).WriteTo(&archive); err != nil {
+		tests.Failed("Should have successfully archived diffed filesystem: %+q", err)
+	}
+
+	archived, err := filesystem.FromZip(bytes.NewReader(archive.Bytes()), int64(archive.Len()), filesystem.DefaultReadLimits)
+	if err != nil {
+		tests.Failed("Should have successfully read archive: %+q", err)
+	}
+
+	if data, err := archived.ReadFile("main.go"); err != nil || string(data) != "package app\n" {
+		tests.Info("Content: %q", data)
+		tests.Failed("Should have successfully archived reader content after diff: %+q", err)
+	}
+	tests.Passed("Should have successfully archived reader content after diff")
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	var from, to bytes.Buffer
+	for line := 1; line <= 20; line++ {
+		fmt.Fprintf(&from, "line %d\n", line)
+		if line == 2 || line == 18 {
+			fmt.Fprintf(&to, "changed %d\n", line)
+			continue
+		}
+		fmt.Fprintf(&to, "line %d\n", line)
+	}
+
+	expected := "--- a\n+++ b\n" +
+		"@@ -1,5 +1,5 @@\n line 1\n-line 2\n+changed 2\n line 3\n line 4\n line 5\n" +
+		"@@ -15,6 +15,6 @@\n line 15\n line 16\n line 17\n-line 18\n+changed 18\n line 19\n line 20\n"
+
+	if diff := filesystem.UnifiedDiff("a", "b", from.Bytes(), to.Bytes()); diff != expected {
+		tests.Info("Diff: %q", diff)
+		tests.Failed("Should have successfully written separate hunks")
+	}
+	tests.Passed("Should have successfully written separate hunks")
+
+	if diff := filesystem.UnifiedDiff("a", "b", from.Bytes(), from.Bytes()); diff != "" {
+		tests.Failed("Should have successfully written no diff for equal contents")
+	}
+	tests.Passed("Should have successfully written no diff for equal contents")
+}
+
+func TestUnifiedDiffLargeFiles(t *testing.T) {
+	var from, to bytes.Buffer
+	for line := 0; line < 10000; line++ {
+		fmt.Fprintf(&from, "from %d\n", line)
+		fmt.Fprintf(&to, "to %d\n", line)
+	}
+
+	diff := filesystem.UnifiedDiff("a", "b", from.Bytes(), to.Bytes())
+	if !strings.HasPrefix(diff, "--- a\n+++ b\n@@ -1,10000 +1,10000 @@\n-from 0\n") || strings.Count(diff, "@@ -") != 1 {
+		tests.Failed("Should have successfully replaced files differing beyond the edit limit as a whole")
+	}
+	tests.Passed("Should have successfully replaced files differing beyond the edit limit as a whole")
+
+	to.Reset()
+	for line := 0; line < 10000; line++ {
+		if line%10 == 0 {
+			fmt.Fprintf(&to, "changed %d\n", line)
+			continue
+		}
+		fmt.Fprintf(&to, "from %d\n", line)
+	}
+
+	diff = filesystem.UnifiedDiff("a", "b", from.Bytes(), to.Bytes())
+	if strings.Count(diff, "\n-from") != 1000 || strings.Count(diff, "\n+changed") != 1000 {
+		tests.Failed("Should have successfully diffed large files within the edit limit line by line")
+	}
+	tests.Passed("Should have successfully diffed large files within the edit limit line by line")
+}
@@ -0,0 +1,127 @@
+package filesystem
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/influx6/moz/gen"
+)
+
+// FromDirectives returns a MemoryFileSystem holding the files of the giving directives, each placed at
+// its Dir and FileName. The content of every directive is rendered once when converted, hence the
+// returned filesystem can be read any number of times. Directives without a Writer, such as those
+// only carrying Rewrites, are skipped and Before and After hooks are not called.
+//
+// As when written by ast.WriteDirective, a later directive for the same file replaces the content of
+// an earlier one unless it sets DontOverride, which is kept on the FileWriter.
+func FromDirectives(directives []gen.WriteDirective, meta ...MetaOption) (MemoryFileSystem, error) {
+	fsm := FileSystem()
+	for _, op := range meta {
+		op(fsm.Meta)
+	}
+
+	for _, directive := range directives {
+		if directive.Writer == nil {
+			continue
+		}
+
+		if directive.FileName == "" {
+			return MemoryFileSystem{}, fmt.Errorf("WriteDirective in %q has no filename value attached", directive.Dir)
+		}
+
+		filePath := path.Join(directive.Dir, directive.FileName)
+		if err := validPath(filePath); err != nil {
+			return MemoryFileSystem{}, err
+		}
+
+		var bu bytes.Buffer
+		if _, err := directive.Writer.WriteTo(&bu); err != nil && err != io.EOF {
+			return MemoryFileSystem{}, fmt.Errorf("IOError: Unable to render content of %q: %+q", filePath, err)
+		}
+
+		file := File(path.Base(filePath), ContentByte(bu.Bytes()))
+		file.DontOverride = directive.DontOverride
+
+		if err := fsm.Dir.add(strings.Split(path.Dir(filePath), "/"), file); err != nil {
+			return MemoryFileSystem{}, err
+		}
+	}
+
+	return fsm, nil
+}
+
+// add places the file within the directory at the giving levels, creating missing directories.
+// An existing file of the same name is replaced unless the file sets DontOverride.
+func (dirs *DirWriter) add(levels []string, file FileWriter) error {
+	parent, err := dirs.ensureDir(levels)
+	if err != nil {
+		return err
+	}
+
+	for index, existing := range parent.ChildFiles {
+		if existing.Name != file.Name {
+			continue
+		}
+
+		if !file.DontOverride {
+			parent.ChildFiles[index] = file
+		}
+		return nil
+	}
+
+	for _, dir := range parent.ChildDirs {
+		if dir.Name == file.Name {
+			return fmt.Errorf("File %q conflicts with directory of the same name in %q", file.Name, parent.Name)
+		}
+	}
+
+	parent.ChildFiles = append(parent.ChildFiles, file)
+	return nil
+}
+
+// ensureDir returns the directory at the giving levels within the directory, creating missing
+// directories.
+func (dirs *DirWriter) ensureDir(levels []string) (*DirWriter, error) {
+	current := dirs
+
+levelLoop:
+	for _, level := range levels {
+		if level == "." || level == "" {
+			continue
+		}
+
+		for _, existing := range current.ChildFiles {
+			if existing.Name == level {
+				return nil, fmt.Errorf("Dir %q conflicts with file of the same name in %q", level, current.Name)
+			}
+		}
+
+		for index := range current.ChildDirs {
+			if current.ChildDirs[index].Name == level {
+				current = &current.ChildDirs[index]
+				continue levelLoop
+			}
+		}
+
+		current.ChildDirs = append(current.ChildDirs, Dir(level))
+		current = &current.ChildDirs[len(current.ChildDirs)-1]
+	}
+
+	return current, nil
+}
+
+// validPath returns an error if the slash separated path is absolute or leaves its root.
+func validPath(filePath string) error {
+	if path.IsAbs(filePath) {
+		return fmt.Errorf("Absolute path %q not allowed", filePath)
+	}
+
+	if cleaned := path.Clean(filePath); cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("Path %q leaves its root", filePath)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package filesystem
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/influx6/moz/gen"
+)
+
+// GoFileSystem implements io.WriteTo and transforms the MemoryFileSystem into the source of a Go
+// package declaring a variable of the giving Name, which serves the files as a fs.FS using a
+// gen.EmbedFSDeclr. Gzip and Hashes are set on the declaration, embedding compressed contents
+// decompressed when first read, and content hashes and ETags.
+//
+// Files and directories keep their mode and modification time. Symbolic links to files are
+// embedded as copies of their target, while links to directories and dangling links are skipped.
+type GoFileSystem struct {
+	FS      MemoryFileSystem
+	Package string
+	Name    string
+	Gzip    bool
+	Hashes  bool
+}
+
+// GoFS returns a new instance of the GoFileSystem.
+func GoFS(fs MemoryFileSystem, pkg string, name string) GoFileSystem {
+	return GoFileSystem{FS: fs, Package: pkg, Name: name}
+}
+
+// ToReader returns a reader streaming the contents of the GoFileSystem as it is read.
+// Each reader is unique and contains a complete data of all contents, and should be closed
+// through io.Closer if not read to its end.
+func (gfs GoFileSystem) ToReader() (io.Reader, error) {
+	return pipeReader(gfs), nil
+}
+
+// WriteTo implements io.WriterTo interface.
+func (gfs GoFileSystem) WriteTo(w io.Writer) (int64, error) {
+	declr, err := gfs.Declr()
+	if err != nil {
+		return 0, err
+	}
+
+	var imports []gen.ImportItemDeclr
+	for _, path := range declr.Imports() {
+		imports = append(imports, gen.Import(path, ""))
+	}
+
+	return gen.Package(gen.Name(gfs.Package), gen.Imports(imports...), declr).WriteTo(w)
+}
+
+// Directive returns a gen.WriteDirective writing the source into fileName within dir, as returned
+// by annotation generators.
+func (gfs GoFileSystem) Directive(dir string, fileName string) gen.WriteDirective {
+	return gen.WriteDirective{
+		Writer:   gfs,
+		Dir:      dir,
+		FileName: fileName,
+	}
+}
+
+// Declr returns the gen.EmbedFSDeclr of the filesystem, holding the content of its files.
+func (gfs GoFileSystem) Declr() (gen.EmbedFSDeclr, error) {
+	declr := gen.EmbedFS(gfs.Name)
+	declr.Gzip = gfs.Gzip
+	declr.Hashes = gfs.Hashes
+
+	err := fs.WalkDir(gfs.FS, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := gfs.FS.Stat(filePath)
+		if err != nil {
+			// Dangling links are skipped.
+			if entry.Type()&fs.ModeSymlink != 0 {
+				return nil
+			}
+			return err
+		}
+
+		file := gen.EmbedFileDeclr{
+			Path:    filePath,
+			Dir:     info.IsDir(),
+			Mode:    info.Mode(),
+			ModTime: info.ModTime(),
+		}
+
+		if file.Dir {
+			// Links to directories are skipped, as they may lead back to their parent.
+			if entry.Type()&fs.ModeSymlink != 0 {
+				return nil
+			}
+
+			declr.Files = append(declr.Files, file)
+			return nil
+		}
+
+		if file.Data, err = gfs.FS.ReadFile(filePath); err != nil {
+			return err
+		}
+
+		declr.Files = append(declr.Files, file)
+		return nil
+	})
+
+	return declr, err
+}
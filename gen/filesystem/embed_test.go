@@ -0,0 +1,91 @@
+package filesystem_test
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/gen/filesystem"
+)
+
+func TestGoFileSystem(t *testing.T) {
+	memFS := filesystem.FileSystem(
+		filesystem.Dir(
+			"app",
+			filesystem.File("readme.md", filesystem.Content(strings.Repeat("# App v1.0\n", 20))),
+			filesystem.Executable("run.sh", filesystem.Content("#!/bin/sh")),
+			filesystem.Symlink("start.sh", "run.sh"),
+			filesystem.Dir("empty"),
+		),
+		filesystem.Symlink("current", "app"),
+	)
+
+	goFS := filesystem.GoFS(memFS, "assets", "Assets")
+	goFS.Gzip = true
+	goFS.Hashes = true
+
+	directive := goFS.Directive("assets", "assets.go")
+	if directive.Dir != "assets" || directive.FileName != "assets.go" {
+		tests.Failed("Should have successfully created write directive")
+	}
+
+	var source bytes.Buffer
+	if _, err := directive.Writer.WriteTo(&source); err != nil {
+		tests.Failed("Should have successfully rendered embedded filesystem: %+q", err)
+	}
+	tests.Passed("Should have successfully rendered embedded filesystem")
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "assets.go", source.String(), 0)
+	if err != nil {
+		tests.Info("Source: %s", source.String())
+		tests.Failed("Should have successfully parsed embedded filesystem: %+q", err)
+	}
+
+	config := types.Config{Importer: importer.Default()}
+	checked, err := config.Check("assets", fset, []*ast.File{file}, nil)
+	if err != nil {
+		tests.Failed("Should have successfully type checked embedded filesystem: %+q", err)
+	}
+	tests.Passed("Should have successfully rendered compilable embedded filesystem")
+
+	assets := checked.Scope().Lookup("Assets")
+	if assets == nil {
+		tests.Failed("Should have successfully declared Assets variable")
+	}
+
+	methods := types.NewMethodSet(assets.Type())
+	for _, method := range []string{"Open", "ReadDir", "ReadFile", "Stat", "Compressed", "Hash", "ETag"} {
+		if methods.Lookup(checked, method) == nil {
+			tests.Info("Method: %s", method)
+			tests.Failed("Should have successfully declared methods of Assets")
+		}
+	}
+	tests.Passed("Should have successfully declared methods of Assets")
+
+	for _, expected := range []string{
+		`"app/readme.md": {name: "readme.md", mode: 0644, size: 220, hash: "`,
+		`gzipped: true`,
+		`"app/run.sh": {name: "run.sh", mode: 0755, size: 9`,
+		`"app/start.sh": {name: "start.sh", mode: 0755, size: 9`,
+		`"app/empty": {name: "empty", mode: fs.ModeDir | 0755, entries: []string{}}`,
+		`".": {name: ".", mode: fs.ModeDir | 0755, entries: []string{"app"}}`,
+	} {
+		if !strings.Contains(source.String(), expected) {
+			tests.Info("Expected: %s", expected)
+			tests.Info("Source: %s", source.String())
+			tests.Failed("Should have successfully embedded entries")
+		}
+	}
+
+	if strings.Contains(source.String(), `"current"`) {
+		tests.Failed("Should have successfully skipped links to directories")
+	}
+	tests.Passed("Should have successfully embedded entries")
+}
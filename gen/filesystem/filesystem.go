@@ -9,8 +9,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"path"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/influx6/moz/gen"
@@ -78,18 +81,77 @@ func ContentByte(data []byte) io.WriterTo {
 }
 
 // ContentFrom returns a io.WriterTo which wraps the io.Reader for piping
-// the data from the giving reader.
+// the data from the giving reader. The reader is read once, when the content
+// is first written or its length is needed, and its data is kept so the
+// content can be written again.
 func ContentFrom(r io.Reader) io.WriterTo {
-	return &gen.FromReader{R: r}
+	return &readerContent{reader: r}
+}
+
+// readerContent implements io.WriterTo over a io.Reader which can only be read
+// once, keeping the data read to write it again.
+type readerContent struct {
+	mu     sync.Mutex
+	reader io.Reader
+	data   []byte
+	err    error
+}
+
+// WriteTo implements io.WriterTo, writing the data of the reader to w.
+func (rc *readerContent) WriteTo(w io.Writer) (int64, error) {
+	data, err := rc.read()
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := w.Write(data)
+	return int64(written), err
+}
+
+// Len returns the length of the data of the reader.
+func (rc *readerContent) Len() int {
+	data, _ := rc.read()
+	return len(data)
+}
+
+// read returns the data of the reader, reading it on the first call.
+func (rc *readerContent) read() ([]byte, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.reader != nil {
+		rc.data, rc.err = io.ReadAll(rc.reader)
+		rc.reader = nil
+	}
+
+	return rc.data, rc.err
 }
 
 //================================================================================
 
+// Attrs defines the metadata of a file or directory, where a zero Mode or ModTime
+// leaves the default of the writer it is written by, and Owner and Group name the
+// UID and GID.
+type Attrs struct {
+	Mode    fs.FileMode
+	ModTime time.Time
+	UID     int
+	GID     int
+	Owner   string
+	Group   string
+}
+
 // FileWriter defines a structure that represent a file system file item
-// with associated content.
+// with associated content. DontOverride marks a file which is not to replace
+// an existing file when written to disk, as gen.WriteDirective does. A file
+// with a Link is a symbolic link to the slash separated path it holds, which
+// is relative to the directory of the file, and has no content.
 type FileWriter struct {
-	Name    string
-	Content io.WriterTo
+	Attrs
+	Name         string
+	Content      io.WriterTo
+	Link         string
+	DontOverride bool
 }
 
 // File returns a instance of a FileWriter with associated name and content.
@@ -101,9 +163,60 @@ func File(name string, content io.WriterTo) FileWriter {
 	return file
 }
 
+// Executable returns a instance of a FileWriter with associated name and content,
+// which is executable by everyone.
+func Executable(name string, content io.WriterTo) FileWriter {
+	return File(name, content).WithMode(0755)
+}
+
+// Symlink returns a instance of a FileWriter for a symbolic link with the giving
+// name to the target path.
+func Symlink(name string, target string) FileWriter {
+	var file FileWriter
+	file.Name = name
+	file.Link = target
+	file.Mode = fs.ModeSymlink | 0777
+
+	return file
+}
+
+// WithMode returns a copy of the file with the giving mode.
+func (file FileWriter) WithMode(mode fs.FileMode) FileWriter {
+	file.Mode = mode
+	return file
+}
+
+// WithModTime returns a copy of the file with the giving modification time.
+func (file FileWriter) WithModTime(modTime time.Time) FileWriter {
+	file.ModTime = modTime
+	return file
+}
+
+// WithOwner returns a copy of the file owned by the giving user and group.
+func (file FileWriter) WithOwner(uid int, gid int, owner string, group string) FileWriter {
+	file.UID, file.GID, file.Owner, file.Group = uid, gid, owner, group
+	return file
+}
+
+// FileMode returns the mode of the file, which defaults to FileMode and holds
+// fs.ModeSymlink for links.
+func (file FileWriter) FileMode() fs.FileMode {
+	mode := file.Mode
+	if mode == 0 {
+		mode = FileMode
+	}
+
+	if file.Link != "" {
+		return fs.ModeSymlink | mode.Perm()
+	}
+
+	return mode
+}
+
 // DirWriter implements a structure that represents a file system directory
 // with associated files and name.
 type DirWriter struct {
+	Attrs
 	Name       string
 	ChildFiles []FileWriter
 	ChildDirs  []DirWriter
@@ -128,6 +241,33 @@ func Dir(name string, files ...interface{}) DirWriter {
 	return dir
 }
 
+// WithMode returns a copy of the directory with the giving permissions.
+func (dirs DirWriter) WithMode(mode fs.FileMode) DirWriter {
+	dirs.Mode = mode
+	return dirs
+}
+
+// WithModTime returns a copy of the directory with the giving modification time.
+func (dirs DirWriter) WithModTime(modTime time.Time) DirWriter {
+	dirs.ModTime = modTime
+	return dirs
+}
+
+// WithOwner returns a copy of the directory owned by the giving user and group.
+func (dirs DirWriter) WithOwner(uid int, gid int, owner string, group string) DirWriter {
+	dirs.UID, dirs.GID, dirs.Owner, dirs.Group = uid, gid, owner, group
+	return dirs
+}
+
+// DirMode returns the mode of the directory, which defaults to DirMode.
+func (dirs DirWriter) DirMode() fs.FileMode {
+	if dirs.Mode.Perm() == 0 {
+		return DirMode
+	}
+
+	return fs.ModeDir | dirs.Mode.Perm()
+}
+
 // GetDir returns the associated DirWriter for the giving relative filepath.
 // Absolute path will be rejected.
 func (dirs DirWriter) GetDir(dirPath string) (DirWriter, error) {
@@ -289,7 +429,8 @@ type Filesystem interface {
 }
 
 // JSONFileSystem implements io.WriteTo and transforms the MemoryFileSystem into a
-// json hashmap using the encoding/json encoders.
+// json hashmap using the encoding/json encoders. The hashmap holds only the contents of files,
+// hence directories, file attributes and symbolic links are not kept.
 type JSONFileSystem struct {
 	FS     MemoryFileSystem
 	indent bool
@@ -300,65 +441,111 @@ func JSONFS(fs MemoryFileSystem, indent bool) JSONFileSystem {
 	return JSONFileSystem{FS: fs, indent: indent}
 }
 
-// ToReader returns a new reader from the contents of the GzipTarFileSystem.
-// Each reader is unique and contains a complete data of all contents.
+// ToReader returns a reader streaming the contents of the JSONFileSystem as it is read.
+// Each reader is unique and contains a complete data of all contents, and should be closed
+// through io.Closer if not read to its end.
 func (jfs JSONFileSystem) ToReader() (io.Reader, error) {
-	var comBuff bytes.Buffer
-	if _, err := jfs.WriteTo(&comBuff); err != nil {
-		return nil, err
-	}
-
-	return &comBuff, nil
+	return pipeReader(jfs), nil
 }
 
-// WriteTo implements io.WriterTo interface.
+// WriteTo implements io.WriterTo interface. The json hashmap is encoded as files are read, with
+// each file read once, hence the size of files does not bound the memory used. Its members are
+// written in path order, as encoding/json writes maps.
 func (jfs JSONFileSystem) WriteTo(w io.Writer) (int64, error) {
-	archive := make(map[string]string)
+	type member struct {
+		path string
+		file FileWriter
+	}
+
+	var members []member
 
 	if metaJSON, err := json.Marshal(jfs.FS.Meta); err == nil {
-		meta := File(".meta", ContentByte(metaJSON))
-		_, err := handleJSONForFileWriter(archive, ".meta", meta)
-		if err != nil {
-			return 0, err
-		}
+		members = append(members, member{path: ".meta", file: File(".meta", ContentByte(metaJSON))})
 	}
 
-	if err := jfs.FS.Files(func(hostFilePath string, hostFile FileWriter) error {
-		_, err := handleJSONForFileWriter(archive, hostFilePath, hostFile)
-		return err
-	}); err != nil {
-		return 0, err
+	jfs.FS.Files(func(hostFilePath string, hostFile FileWriter) error {
+		if hostFile.Link == "" {
+			members = append(members, member{path: hostFilePath, file: hostFile})
+		}
+		return nil
+	})
+
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].path < members[j].path
+	})
+
+	// Lines are laid out as json.Encoder lays them with the "\n" prefix and "\t" indent.
+	var newline, indent string
+	if jfs.indent {
+		newline, indent = "\n\n", "\t"
 	}
 
 	wc := gen.NewWriteCounter(w)
-	encoder := json.NewEncoder(wc)
+	separator := "{"
 
-	if jfs.indent {
-		encoder.SetIndent("\n", "\t")
+	for index, item := range members {
+		// Later files of the same path replace earlier ones, as they would in a map.
+		if index+1 < len(members) && members[index+1].path == item.path {
+			continue
+		}
+
+		if _, err := io.WriteString(wc, separator+newline+indent); err != nil {
+			return wc.Written(), err
+		}
+
+		if _, err := handleJSONForFileWriter(wc, item.path, item.file, jfs.indent); err != nil {
+			return wc.Written(), err
+		}
+
+		separator = ","
 	}
 
-	if err := encoder.Encode(archive); err != nil {
-		return 0, err
+	closing := newline + "}\n"
+	if len(members) == 0 {
+		closing = "{}\n"
 	}
 
-	return wc.Written(), nil
+	_, err := io.WriteString(wc, closing)
+	return wc.Written(), err
 }
 
-func handleJSONForFileWriter(archive map[string]string, hostFilePath string, file FileWriter) (int64, error) {
-	var bu bytes.Buffer
+// handleJSONForFileWriter writes the path and content of the file as a member of the json hashmap,
+// escaping the content as it is read.
+func handleJSONForFileWriter(w io.Writer, hostFilePath string, file FileWriter, indent bool) (int64, error) {
+	key, err := json.Marshal(hostFilePath)
+	if err != nil {
+		return 0, err
+	}
+
+	colon := ":"
+	if indent {
+		colon = ": "
+	}
+
+	if _, err := fmt.Fprintf(w, "%s%s\"", key, colon); err != nil {
+		return 0, err
+	}
+
+	content := &jsonStringWriter{w: w}
 
-	total, err := file.Content.WriteTo(&bu)
-	if err == nil {
-		archive[hostFilePath] = bu.String()
+	total, err := writeContent(content, file)
+	if err != nil && err != io.EOF {
+		return total, err
 	}
 
+	if err := content.Close(); err != nil {
+		return total, err
+	}
+
+	_, err = io.WriteString(w, "\"")
 	return total, err
 }
 
 //======================================================================================
 
 // ZipFileSystem implements io.WriteTo and transforms the MemoryFileSystem into a
-// tar archive using the archive/zip writers.
+// tar archive using the archive/zip writers. Entries carry the mode and modification time of
+// their files and directories, with symbolic links holding their target as content.
 type ZipFileSystem struct {
 	FS MemoryFileSystem
 }
@@ -368,15 +555,11 @@ func ZipFS(fs MemoryFileSystem) ZipFileSystem {
 	return ZipFileSystem{FS: fs}
 }
 
-// ToReader returns a new reader from the contents of the GzipTarFileSystem.
-// Each reader is unique and contains a complete data of all contents.
+// ToReader returns a reader streaming the contents of the ZipFileSystem as it is read.
+// Each reader is unique and contains a complete data of all contents, and should be closed
+// through io.Closer if not read to its end.
 func (zfs ZipFileSystem) ToReader() (io.Reader, error) {
-	var comBuff bytes.Buffer
-	if _, err := zfs.WriteTo(&comBuff); err != nil {
-		return nil, err
-	}
-
-	return &comBuff, nil
+	return pipeReader(zfs), nil
 }
 
 // WriteTo implements io.WriterTo interface.
@@ -387,7 +570,7 @@ func (zfs ZipFileSystem) WriteTo(w io.Writer) (int64, error) {
 	var totalWritten int64
 
 	if metaJSON, err := json.Marshal(zfs.FS.Meta); err == nil {
-		meta := File(".meta", ContentByte(metaJSON))
+		meta := File(".meta", ContentByte(metaJSON)).WithModTime(zfs.FS.Dir.ModTime)
 		total, err := handleZipForFileWriter(archive, ".meta", meta)
 		totalWritten += total
 
@@ -396,6 +579,12 @@ func (zfs ZipFileSystem) WriteTo(w io.Writer) (int64, error) {
 		}
 	}
 
+	if err := zfs.FS.Dirs(func(hostDirPath string, hostDir DirWriter) error {
+		return handleZipForDirWriter(archive, path.Join(hostDirPath, hostDir.Name), hostDir)
+	}); err != nil {
+		return 0, err
+	}
+
 	if err := zfs.FS.Files(func(hostFilePath string, hostFile FileWriter) error {
 		total, err := handleZipForFileWriter(archive, hostFilePath, hostFile)
 		totalWritten += total
@@ -405,31 +594,58 @@ func (zfs ZipFileSystem) WriteTo(w io.Writer) (int64, error) {
 		return 0, err
 	}
 
-	if err := archive.Flush(); err != nil {
+	if err := archive.Close(); err != nil {
 		return 0, err
 	}
 
 	return totalWritten, nil
 }
 
+func handleZipForDirWriter(archive *zip.Writer, hostDirPath string, dir DirWriter) error {
+	if hostDirPath == "" {
+		return nil
+	}
+
+	header := &zip.FileHeader{
+		Name:     hostDirPath + "/",
+		Flags:    utf8Encoding,
+		Method:   zip.Store,
+		Modified: dir.ModTime,
+	}
+	header.SetMode(dir.DirMode())
+
+	_, err := archive.CreateHeader(header)
+	return err
+}
+
 func handleZipForFileWriter(archive *zip.Writer, hostFilePath string, file FileWriter) (int64, error) {
-	fileWriter, err := archive.CreateHeader(&zip.FileHeader{
-		Name:   hostFilePath,
-		Flags:  utf8Encoding,
-		Method: zip.Deflate,
-	})
+	header := &zip.FileHeader{
+		Name:     hostFilePath,
+		Flags:    utf8Encoding,
+		Method:   zip.Deflate,
+		Modified: file.ModTime,
+	}
+	header.SetMode(file.FileMode())
 
+	fileWriter, err := archive.CreateHeader(header)
 	if err != nil {
 		return 0, err
 	}
 
-	return file.Content.WriteTo(fileWriter)
+	// Symbolic links hold their target as content, as zip(1) stores them.
+	if file.Link != "" {
+		n, err := io.WriteString(fileWriter, file.Link)
+		return int64(n), err
+	}
+
+	return writeContent(fileWriter, file)
 }
 
 //======================================================================================
 
 // TarFileSystem implements io.WriteTo and transforms the MemoryFileSystem into a
-// tar archive using the archive/tar writers.
+// tar archive using the archive/tar writers. Entries carry the attributes of their files and
+// directories, including symbolic links.
 type TarFileSystem struct {
 	FS MemoryFileSystem
 }
@@ -439,15 +655,11 @@ func TarFS(fs MemoryFileSystem) TarFileSystem {
 	return TarFileSystem{FS: fs}
 }
 
-// ToReader returns a new reader from the contents of the GzipTarFileSystem.
-// Each reader is unique and contains a complete data of all contents.
+// ToReader returns a reader streaming the contents of the TarFileSystem as it is read.
+// Each reader is unique and contains a complete data of all contents, and should be closed
+// through io.Closer if not read to its end.
 func (tfs TarFileSystem) ToReader() (io.Reader, error) {
-	var comBuff bytes.Buffer
-	if _, err := tfs.WriteTo(&comBuff); err != nil {
-		return nil, err
-	}
-
-	return &comBuff, nil
+	return pipeReader(tfs), nil
 }
 
 // WriteTo implements io.WriterTo interface.
@@ -458,7 +670,7 @@ func (tfs TarFileSystem) WriteTo(w io.Writer) (int64, error) {
 	var totalWritten int64
 
 	if metaJSON, err := json.Marshal(tfs.FS.Meta); err == nil {
-		meta := File(".meta", ContentByte(metaJSON))
+		meta := File(".meta", ContentByte(metaJSON)).WithModTime(tfs.FS.Dir.ModTime)
 		total, err := handleTarForFileWriter(archive, ".meta", meta)
 		totalWritten += total
 
@@ -467,6 +679,12 @@ func (tfs TarFileSystem) WriteTo(w io.Writer) (int64, error) {
 		}
 	}
 
+	if err := tfs.FS.Dirs(func(hostDirPath string, hostDir DirWriter) error {
+		return handleTarForDirWriter(archive, path.Join(hostDirPath, hostDir.Name), hostDir)
+	}); err != nil {
+		return 0, err
+	}
+
 	if err := tfs.FS.Files(func(hostFilePath string, hostFile FileWriter) error {
 		total, err := handleTarForFileWriter(archive, hostFilePath, hostFile)
 		totalWritten += total
@@ -475,7 +693,7 @@ func (tfs TarFileSystem) WriteTo(w io.Writer) (int64, error) {
 		return 0, err
 	}
 
-	if err := archive.Flush(); err != nil {
+	if err := archive.Close(); err != nil {
 		return 0, err
 	}
 
@@ -485,7 +703,8 @@ func (tfs TarFileSystem) WriteTo(w io.Writer) (int64, error) {
 //======================================================================================
 
 // GzipTarFileSystem implements io.WriteTo and transforms the MemoryFileSystem into a
-// tar archive using the archive/tar writer to wrap a compress/gzip writer.
+// tar archive using the archive/tar writer to wrap a compress/gzip writer, with entries as
+// TarFileSystem writes them.
 type GzipTarFileSystem struct {
 	FS MemoryFileSystem
 }
@@ -495,15 +714,11 @@ func GzipTarFS(fs MemoryFileSystem) GzipTarFileSystem {
 	return GzipTarFileSystem{FS: fs}
 }
 
-// ToReader returns a new reader from the contents of the GzipTarFileSystem.
-// Each reader is unique and contains a complete data of all contents.
+// ToReader returns a reader streaming the contents of the GzipTarFileSystem as it is read.
+// Each reader is unique and contains a complete data of all contents, and should be closed
+// through io.Closer if not read to its end.
 func (gfs GzipTarFileSystem) ToReader() (io.Reader, error) {
-	var comBuff bytes.Buffer
-	if _, err := gfs.WriteTo(&comBuff); err != nil {
-		return nil, err
-	}
-
-	return &comBuff, nil
+	return pipeReader(gfs), nil
 }
 
 // WriteTo implements io.WriterTo interface.
@@ -517,7 +732,7 @@ func (gfs GzipTarFileSystem) WriteTo(w io.Writer) (int64, error) {
 	var totalWritten int64
 
 	if metaJSON, err := json.Marshal(gfs.FS.Meta); err == nil {
-		meta := File(".meta", ContentByte(metaJSON))
+		meta := File(".meta", ContentByte(metaJSON)).WithModTime(gfs.FS.Dir.ModTime)
 		total, err := handleTarForFileWriter(archive, ".meta", meta)
 		totalWritten += total
 
@@ -526,6 +741,12 @@ func (gfs GzipTarFileSystem) WriteTo(w io.Writer) (int64, error) {
 		}
 	}
 
+	if err := gfs.FS.Dirs(func(hostDirPath string, hostDir DirWriter) error {
+		return handleTarForDirWriter(archive, path.Join(hostDirPath, hostDir.Name), hostDir)
+	}); err != nil {
+		return 0, err
+	}
+
 	if err := gfs.FS.Files(func(hostFilePath string, hostFile FileWriter) error {
 		total, err := handleTarForFileWriter(archive, hostFilePath, hostFile)
 		totalWritten += total
@@ -534,11 +755,11 @@ func (gfs GzipTarFileSystem) WriteTo(w io.Writer) (int64, error) {
 		return 0, err
 	}
 
-	if err := archive.Flush(); err != nil {
+	if err := archive.Close(); err != nil {
 		return 0, err
 	}
 
-	if err := gzw.Flush(); err != nil {
+	if err := gzw.Close(); err != nil {
 		return 0, err
 	}
 
@@ -547,20 +768,86 @@ func (gfs GzipTarFileSystem) WriteTo(w io.Writer) (int64, error) {
 
 //======================================================================================
 
+func handleTarForDirWriter(archive *tar.Writer, hostDirPath string, dir DirWriter) error {
+	if hostDirPath == "" {
+		return nil
+	}
+
+	header := tarHeader(hostDirPath+"/", dir.Attrs, dir.DirMode())
+	header.Typeflag = tar.TypeDir
+
+	return archive.WriteHeader(header)
+}
+
 func handleTarForFileWriter(archive *tar.Writer, hostFilePath string, file FileWriter) (int64, error) {
-	var bu bytes.Buffer
-	if n, err := file.Content.WriteTo(&bu); err != nil {
-		return n, err
+	header := tarHeader(hostFilePath, file.Attrs, file.FileMode())
+
+	if file.Link != "" {
+		header.Typeflag = tar.TypeSymlink
+		header.Linkname = file.Link
+		return 0, archive.WriteHeader(header)
 	}
 
-	if err := archive.WriteHeader(&tar.Header{
-		Name:    hostFilePath,
-		Mode:    0666,
-		ModTime: time.Now(),
-		Size:    int64(bu.Len()),
-	}); err != nil {
+	header.Typeflag = tar.TypeReg
+
+	// Content of known length is streamed, while other content is spooled once to learn its length.
+	if size, ok := contentLength(file); ok {
+		header.Size = size
+
+		if err := archive.WriteHeader(header); err != nil {
+			return 0, err
+		}
+
+		return writeContent(archive, file)
+	}
+
+	spooled, err := spool(file)
+	if err != nil {
 		return 0, err
 	}
 
-	return bu.WriteTo(archive)
+	defer spooled.cleanup()
+
+	content, err := spooled.reader()
+	if err != nil {
+		return 0, err
+	}
+
+	header.Size = spooled.size
+
+	if err := archive.WriteHeader(header); err != nil {
+		return 0, err
+	}
+
+	return io.Copy(archive, content)
+}
+
+// tarHeader returns the header of a tar entry with the giving attributes and mode, where entries
+// without a modification time are given the current time.
+func tarHeader(name string, attrs Attrs, mode fs.FileMode) *tar.Header {
+	perm := int64(mode.Perm())
+	if mode&fs.ModeSetuid != 0 {
+		perm |= 04000
+	}
+	if mode&fs.ModeSetgid != 0 {
+		perm |= 02000
+	}
+	if mode&fs.ModeSticky != 0 {
+		perm |= 01000
+	}
+
+	modTime := attrs.ModTime
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+
+	return &tar.Header{
+		Name:    name,
+		Mode:    perm,
+		ModTime: modTime,
+		Uid:     attrs.UID,
+		Gid:     attrs.GID,
+		Uname:   attrs.Owner,
+		Gname:   attrs.Group,
+	}
 }
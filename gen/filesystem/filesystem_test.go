@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/gen"
 	"github.com/influx6/moz/gen/filesystem"
 )
 
@@ -125,3 +126,56 @@ func TestFileSystem(t *testing.T) {
 	}
 	tests.Passed("Should have received expected result")
 }
+
+func TestFromDirectives(t *testing.T) {
+	memFS, err := filesystem.FromDirectives([]gen.WriteDirective{
+		{FileName: "main.go", Writer: gen.Text("package main")},
+		{Dir: "app/src", FileName: "app.go", Writer: gen.Text("package src")},
+		{Dir: "app", FileName: "readme.md", Writer: gen.Text("# App")},
+		{Dir: "app/src", FileName: "app.go", Writer: gen.Text("package app")},
+		{Dir: "app", FileName: "readme.md", Writer: gen.Text("# Ignored"), DontOverride: true},
+		{Dir: "app", Rewrites: []gen.RewriteDirective{{FilePath: "app.go"}}},
+	}, filesystem.Version("1.0"))
+	if err != nil {
+		tests.Failed("Should have successfully converted directives: %+q", err)
+	}
+	tests.Passed("Should have successfully converted directives")
+
+	if memFS.Meta["version"] != "1.0" {
+		tests.Failed("Should have successfully applied meta options")
+	}
+
+	expected := map[string]string{
+		"main.go":        "package main",
+		"app/src/app.go": "package app",
+		"app/readme.md":  "# App",
+	}
+
+	var found int
+	if err := memFS.Files(func(hostFilePath string, hostFile filesystem.FileWriter) error {
+		found++
+
+		var content bytes.Buffer
+		if _, err := hostFile.Content.WriteTo(&content); err != nil {
+			return err
+		}
+
+		if expected[hostFilePath] != content.String() {
+			tests.Info("File %q: %q", hostFilePath, content.String())
+			tests.Failed("Should have successfully placed directive content")
+		}
+		return nil
+	}); err != nil {
+		tests.Failed("Should have successfully run through files: %+q", err)
+	}
+
+	if found != len(expected) {
+		tests.Failed("Should have successfully converted a file per path")
+	}
+	tests.Passed("Should have successfully placed directive content")
+
+	if _, err := filesystem.FromDirectives([]gen.WriteDirective{{Dir: "../out", FileName: "main.go", Writer: gen.Text("")}}); err == nil {
+		tests.Failed("Should have failed to convert directive leaving its root")
+	}
+	tests.Passed("Should have failed to convert directive leaving its root")
+}
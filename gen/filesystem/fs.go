@@ -0,0 +1,454 @@
+package filesystem
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	_ fs.FS         = MemoryFileSystem{}
+	_ fs.ReadDirFS  = MemoryFileSystem{}
+	_ fs.StatFS     = MemoryFileSystem{}
+	_ fs.ReadFileFS = MemoryFileSystem{}
+)
+
+// Modes given to the files and directories of a MemoryFileSystem which set no Mode.
+const (
+	FileMode = fs.FileMode(0644)
+	DirMode  = fs.ModeDir | 0755
+)
+
+// maxLinkHops defines the number of symbolic links followed when resolving a path.
+const maxLinkHops = 40
+
+// Open implements fs.FS, opening the file or directory with the giving slash separated path
+// relative to the root of the filesystem (e.g app/src/main.go). A root directory with a name is
+// the single entry of the root. The content of a file is read when it is opened, and symbolic
+// links are followed.
+func (mfs MemoryFileSystem) Open(name string) (fs.File, error) {
+	file, dir, isDir, err := mfs.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if isDir {
+		return &memDir{info: dirInfo(dir).named(name), entries: dirEntries(dir)}, nil
+	}
+
+	data, err := contentBytes(file)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	// The content read gives the size of files whose length is not known ahead.
+	info := fileInfo(file).named(name)
+	info.file.Content = ContentByte(data)
+
+	return &memFile{info: info, reader: bytes.NewReader(data)}, nil
+}
+
+// ReadDir implements fs.ReadDirFS, returning the entries of the directory sorted by name.
+func (mfs MemoryFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	_, dir, isDir, err := mfs.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isDir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	return dirEntries(dir), nil
+}
+
+// Stat implements fs.StatFS, describing the file or directory a symbolic link points to.
+func (mfs MemoryFileSystem) Stat(name string) (fs.FileInfo, error) {
+	file, dir, isDir, err := mfs.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if isDir {
+		return dirInfo(dir).named(name), nil
+	}
+
+	return fileInfo(file).named(name), nil
+}
+
+// Lstat implements fs.ReadLinkFS, describing a symbolic link itself rather than what it points to.
+func (mfs MemoryFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	file, dir, isDir, err := mfs.resolve("lstat", name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if isDir {
+		return dirInfo(dir).named(name), nil
+	}
+
+	return fileInfo(file), nil
+}
+
+// ReadLink implements fs.ReadLinkFS, returning the target of the symbolic link.
+func (mfs MemoryFileSystem) ReadLink(name string) (string, error) {
+	file, _, isDir, err := mfs.resolve("readlink", name, false)
+	if err != nil {
+		return "", err
+	}
+
+	if isDir || file.Link == "" {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return file.Link, nil
+}
+
+// ReadFile implements fs.ReadFileFS, returning a copy of the content of the file.
+func (mfs MemoryFileSystem) ReadFile(name string) ([]byte, error) {
+	file, _, isDir, err := mfs.lookup("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if isDir {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+	}
+
+	data, err := contentBytes(file)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+
+	return append([]byte(nil), data...), nil
+}
+
+// lookup returns the file or directory with the giving path, following symbolic links.
+func (mfs MemoryFileSystem) lookup(op string, name string) (FileWriter, DirWriter, bool, error) {
+	return mfs.resolve(op, name, true)
+}
+
+// resolve returns the file or directory with the giving path, where files shadow directories of the
+// same name and earlier entries shadow later ones. Symbolic links met along the path are followed,
+// as is the last one if follow is true. Links which are absolute or leave the root do not exist.
+func (mfs MemoryFileSystem) resolve(op string, name string, follow bool) (FileWriter, DirWriter, bool, error) {
+	if !fs.ValidPath(name) {
+		return FileWriter{}, DirWriter{}, false, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	current := mfs.root()
+	levels := strings.Split(name, "/")
+
+	var hops int
+
+levelLoop:
+	for index := 0; index < len(levels); index++ {
+		level := levels[index]
+		if level == "." {
+			continue
+		}
+
+		for _, file := range current.ChildFiles {
+			if file.Name != level {
+				continue
+			}
+
+			last := index == len(levels)-1
+
+			if file.Link != "" && (follow || !last) {
+				if hops++; hops > maxLinkHops {
+					return FileWriter{}, DirWriter{}, false, &fs.PathError{Op: op, Path: name, Err: errors.New("too many links")}
+				}
+
+				target := path.Join(path.Join(levels[:index]...), file.Link, path.Join(levels[index+1:]...))
+				if path.IsAbs(file.Link) || !fs.ValidPath(target) {
+					return FileWriter{}, DirWriter{}, false, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+				}
+
+				current, levels, index = mfs.root(), strings.Split(target, "/"), -1
+				continue levelLoop
+			}
+
+			if !last {
+				return FileWriter{}, DirWriter{}, false, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+			}
+
+			return file, DirWriter{}, false, nil
+		}
+
+		for _, dir := range current.ChildDirs {
+			if dir.Name == level {
+				current = dir
+				continue levelLoop
+			}
+		}
+
+		return FileWriter{}, DirWriter{}, false, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+
+	return FileWriter{}, current, true, nil
+}
+
+// root returns the root directory of the filesystem, which holds the directory of the filesystem
+// if it is named, as the paths given by Files do.
+func (mfs MemoryFileSystem) root() DirWriter {
+	if mfs.Dir.Name == "" || mfs.Dir.Name == "." {
+		return mfs.Dir
+	}
+
+	return DirWriter{Name: ".", ChildDirs: []DirWriter{mfs.Dir}}
+}
+
+// linkInside returns true/false if the symbolic link at linkPath, linking to target, stays within the
+// root when followed, as it would once laid out on disk. Links met along the target are followed, so a
+// chain of links which are each harmless alone (e.g x/up -> .. and x/esc -> up/..) is caught, while
+// links which are absolute or loop do not stay within the root.
+func (mfs MemoryFileSystem) linkInside(linkPath string, target string) bool {
+	var levels []string
+	if dir := path.Dir(linkPath); dir != "." {
+		levels = strings.Split(dir, "/")
+	}
+
+	pending := strings.Split(target, "/")
+	if path.IsAbs(target) {
+		return false
+	}
+
+	var hops int
+	for len(pending) != 0 {
+		level := pending[0]
+		pending = pending[1:]
+
+		switch level {
+		case "", ".":
+			continue
+		case "..":
+			if len(levels) == 0 {
+				return false
+			}
+			levels = levels[:len(levels)-1]
+			continue
+		}
+
+		levels = append(levels, level)
+
+		file, _, isDir, err := mfs.resolve("readlink", path.Join(levels...), false)
+		if err != nil || isDir || file.Link == "" {
+			continue
+		}
+
+		if hops++; hops > maxLinkHops || path.IsAbs(file.Link) {
+			return false
+		}
+
+		levels = levels[:len(levels)-1]
+		pending = append(strings.Split(file.Link, "/"), pending...)
+	}
+
+	return true
+}
+
+// contentBytes returns the content of the file, which is taken without consuming it from contents
+// which expose their bytes, such as those returned by Content, or keep them, as those returned by
+// ContentFrom do.
+func contentBytes(file FileWriter) ([]byte, error) {
+	if file.Content == nil {
+		return nil, nil
+	}
+
+	if content, ok := file.Content.(interface{ Bytes() []byte }); ok {
+		return content.Bytes(), nil
+	}
+
+	var bu bytes.Buffer
+	if _, err := file.Content.WriteTo(&bu); err != nil {
+		return nil, err
+	}
+
+	return bu.Bytes(), nil
+}
+
+// contentSize returns the size of the content of the file, or 0 if its length is not known, as
+// reading it could consume contents which can only be written once.
+func contentSize(file FileWriter) int64 {
+	size, _ := contentLength(file)
+	return size
+}
+
+// dirEntries returns the entries of the directory sorted by name, without those shadowed by an
+// earlier entry of the same name.
+func dirEntries(dir DirWriter) []fs.DirEntry {
+	seen := make(map[string]bool, len(dir.ChildFiles)+len(dir.ChildDirs))
+
+	var entries []fs.DirEntry
+	for _, file := range dir.ChildFiles {
+		if !seen[file.Name] {
+			seen[file.Name] = true
+			entries = append(entries, fs.FileInfoToDirEntry(fileInfo(file)))
+		}
+	}
+
+	for _, child := range dir.ChildDirs {
+		if !seen[child.Name] {
+			seen[child.Name] = true
+			entries = append(entries, fs.FileInfoToDirEntry(dirInfo(child)))
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return entries
+}
+
+//======================================================================================
+
+// memFileInfo implements fs.FileInfo for the files and directories of a MemoryFileSystem, where
+// the size of a file is taken from its content when asked for.
+type memFileInfo struct {
+	name    string
+	mode    fs.FileMode
+	modTime time.Time
+	file    *FileWriter
+}
+
+func fileInfo(file FileWriter) memFileInfo {
+	return memFileInfo{name: file.Name, mode: file.FileMode(), modTime: file.ModTime, file: &file}
+}
+
+func dirInfo(dir DirWriter) memFileInfo {
+	name := dir.Name
+	if name == "" {
+		name = "."
+	}
+
+	return memFileInfo{name: name, mode: dir.DirMode(), modTime: dir.ModTime}
+}
+
+// named returns the info under the base name of the giving path, which differs from the name of
+// the entry when reached through a symbolic link.
+func (mi memFileInfo) named(name string) memFileInfo {
+	if name != "." {
+		mi.name = path.Base(name)
+	}
+	return mi
+}
+
+// Name returns the base name of the file or directory.
+func (mi memFileInfo) Name() string {
+	return mi.name
+}
+
+// Size returns the length in bytes of the content of a file or the target of a symbolic link,
+// and 0 for directories and files whose length is not known without reading their content.
+func (mi memFileInfo) Size() int64 {
+	if mi.file == nil {
+		return 0
+	}
+
+	if mi.file.Link != "" {
+		return int64(len(mi.file.Link))
+	}
+
+	return contentSize(*mi.file)
+}
+
+// Mode returns the mode of the file or directory.
+func (mi memFileInfo) Mode() fs.FileMode {
+	return mi.mode
+}
+
+// ModTime returns the modification time of the file or directory, which is the zero time if
+// it has none.
+func (mi memFileInfo) ModTime() time.Time {
+	return mi.modTime
+}
+
+// IsDir returns true/false if the info describes a directory.
+func (mi memFileInfo) IsDir() bool {
+	return mi.mode.IsDir()
+}
+
+// Sys returns the FileWriter of a file and nil for directories.
+func (mi memFileInfo) Sys() interface{} {
+	if mi.file == nil {
+		return nil
+	}
+	return *mi.file
+}
+
+//======================================================================================
+
+// memFile implements fs.File for an opened file of a MemoryFileSystem, along with io.Seeker and
+// io.ReaderAt as required by http.FileServer.
+type memFile struct {
+	info   memFileInfo
+	reader *bytes.Reader
+}
+
+func (mf *memFile) Stat() (fs.FileInfo, error) {
+	return mf.info, nil
+}
+
+func (mf *memFile) Read(p []byte) (int, error) {
+	return mf.reader.Read(p)
+}
+
+func (mf *memFile) Seek(offset int64, whence int) (int64, error) {
+	return mf.reader.Seek(offset, whence)
+}
+
+func (mf *memFile) ReadAt(p []byte, offset int64) (int, error) {
+	return mf.reader.ReadAt(p, offset)
+}
+
+func (mf *memFile) Close() error {
+	return nil
+}
+
+// memDir implements fs.ReadDirFile for an opened directory of a MemoryFileSystem.
+type memDir struct {
+	info    memFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (md *memDir) Stat() (fs.FileInfo, error) {
+	return md.info, nil
+}
+
+func (md *memDir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: md.info.name, Err: errors.New("is a directory")}
+}
+
+func (md *memDir) Close() error {
+	return nil
+}
+
+// ReadDir returns the next n entries of the directory, or all remaining entries if n <= 0, as
+// defined by fs.ReadDirFile.
+func (md *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := md.entries[md.offset:]
+
+	if n <= 0 {
+		md.offset = len(md.entries)
+		return append([]fs.DirEntry(nil), remaining...), nil
+	}
+
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+
+	md.offset += n
+	return append([]fs.DirEntry(nil), remaining[:n]...), nil
+}
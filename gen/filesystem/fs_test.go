@@ -0,0 +1,178 @@
+package filesystem_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/gen/filesystem"
+)
+
+func TestMemoryFileSystemFS(t *testing.T) {
+	memFS := filesystem.FileSystem(
+		filesystem.Meta("version", "1.0"),
+		filesystem.Dir(
+			"app",
+			filesystem.File(
+				"readme.md",
+				filesystem.Content("# App v1.0"),
+			),
+			filesystem.Dir(
+				"src",
+				filesystem.File(
+					"main.go",
+					filesystem.Content(`package main`),
+				),
+				filesystem.Dir(
+					"dest",
+					filesystem.File(
+						"main.js",
+						filesystem.Content(`strict;`),
+					),
+				),
+			),
+		),
+		filesystem.File(
+			"dockerfile",
+			filesystem.Content(`FROM alpine:latest`),
+		),
+	)
+
+	if err := fstest.TestFS(memFS, "app/readme.md", "app/src/main.go", "app/src/dest/main.js", "dockerfile"); err != nil {
+		tests.Failed("Should have successfully passed fstest.TestFS: %+q", err)
+	}
+	tests.Passed("Should have successfully passed fstest.TestFS")
+
+	data, err := fs.ReadFile(memFS, "app/src/dest/main.js")
+	if err != nil || string(data) != "strict;" {
+		tests.Failed("Should have successfully read file content: %+q", err)
+	}
+
+	if again, _ := fs.ReadFile(memFS, "app/src/dest/main.js"); string(again) != "strict;" {
+		tests.Failed("Should have successfully read file content more than once")
+	}
+	tests.Passed("Should have successfully read file content")
+
+	info, err := fs.Stat(memFS, "app/src")
+	if err != nil || !info.IsDir() || info.Mode() != filesystem.DirMode {
+		tests.Failed("Should have successfully retrieved directory info: %+q", err)
+	}
+	tests.Passed("Should have successfully retrieved directory info")
+
+	var walked []string
+	if err := fs.WalkDir(memFS, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			walked = append(walked, path)
+		}
+		return err
+	}); err != nil {
+		tests.Failed("Should have successfully walked filesystem: %+q", err)
+	}
+
+	if len(walked) != 4 || walked[0] != "app/readme.md" || walked[3] != "dockerfile" {
+		tests.Info("Walked: %+q", walked)
+		tests.Failed("Should have successfully walked files in lexical order")
+	}
+	tests.Passed("Should have successfully walked files in lexical order")
+
+	if _, err := memFS.Open("app/../dockerfile"); err == nil {
+		tests.Failed("Should have failed to open invalid path")
+	}
+
+	if _, err := memFS.Open("app/readme.md/x"); err == nil {
+		tests.Failed("Should have failed to open path through a file")
+	}
+	tests.Passed("Should have failed to open invalid paths")
+}
+
+func TestNamedMemoryFileSystemFS(t *testing.T) {
+	memFS := filesystem.FileSystem(
+		filesystem.File("main.go", filesystem.Content(`package main`)),
+	)
+	memFS.Dir.Name = "project"
+
+	if err := fstest.TestFS(memFS, "project/main.go"); err != nil {
+		tests.Failed("Should have successfully passed fstest.TestFS with named root: %+q", err)
+	}
+	tests.Passed("Should have successfully passed fstest.TestFS with named root")
+}
+
+func TestMemoryFileSystemLinks(t *testing.T) {
+	modTime := time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	memFS := filesystem.FileSystem(
+		filesystem.Dir(
+			"app",
+			filesystem.Executable("run.sh", filesystem.Content("#!/bin/sh")).WithModTime(modTime),
+			filesystem.Symlink("start.sh", "run.sh"),
+		).WithMode(0700),
+		filesystem.Symlink("current", "app"),
+	)
+
+	if err := fstest.TestFS(memFS, "app/run.sh", "app/start.sh", "current"); err != nil {
+		tests.Failed("Should have successfully passed fstest.TestFS with links: %+q", err)
+	}
+	tests.Passed("Should have successfully passed fstest.TestFS with links")
+
+	info, err := fs.Stat(memFS, "app/run.sh")
+	if err != nil || info.Mode() != 0755 || !info.ModTime().Equal(modTime) {
+		tests.Failed("Should have successfully retrieved file attributes: %+q", err)
+	}
+
+	if info, err := fs.Stat(memFS, "app"); err != nil || info.Mode() != fs.ModeDir|0700 {
+		tests.Failed("Should have successfully retrieved directory attributes: %+q", err)
+	}
+	tests.Passed("Should have successfully retrieved attributes")
+
+	if data, err := fs.ReadFile(memFS, "current/start.sh"); err != nil || string(data) != "#!/bin/sh" {
+		tests.Failed("Should have successfully read file through links: %+q", err)
+	}
+
+	if info, err := fs.Stat(memFS, "current/start.sh"); err != nil || info.Name() != "start.sh" || info.Mode() != 0755 {
+		tests.Failed("Should have successfully followed link on stat: %+q", err)
+	}
+	tests.Passed("Should have successfully followed links")
+
+	if info, err := memFS.Lstat("app/start.sh"); err != nil || info.Mode()&fs.ModeSymlink == 0 {
+		tests.Failed("Should have successfully retrieved link itself: %+q", err)
+	}
+
+	if target, err := memFS.ReadLink("current"); err != nil || target != "app" {
+		tests.Failed("Should have successfully read link target: %+q", err)
+	}
+	tests.Passed("Should have successfully read links")
+
+	memFS.Dir.ChildFiles = append(memFS.Dir.ChildFiles, filesystem.Symlink("escape", "../app"))
+	if _, err := memFS.Open("escape"); err == nil {
+		tests.Failed("Should have failed to follow link leaving the filesystem")
+	}
+	tests.Passed("Should have failed to follow link leaving the filesystem")
+}
+
+func TestMemoryFileSystemReaderContent(t *testing.T) {
+	memFS := filesystem.FileSystem(
+		filesystem.File("a.txt", filesystem.ContentFrom(strings.NewReader("hello"))),
+	)
+
+	if info, err := fs.Stat(memFS, "a.txt"); err != nil || info.Size() != 5 {
+		tests.Failed("Should have successfully retrieved size of reader content: %+q", err)
+	}
+
+	err := fs.WalkDir(memFS, ".", func(_ string, _ fs.DirEntry, err error) error {
+		return err
+	})
+	if err != nil {
+		tests.Failed("Should have successfully walked filesystem: %+q", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if data, err := memFS.ReadFile("a.txt"); err != nil || string(data) != "hello" {
+			tests.Info("Content: %q", data)
+			tests.Failed("Should have successfully read reader content after stat: %+q", err)
+		}
+	}
+	tests.Passed("Should have successfully read reader content after stat")
+}
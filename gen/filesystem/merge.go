@@ -0,0 +1,288 @@
+package filesystem
+
+import (
+	"path"
+	"reflect"
+	"strings"
+)
+
+// Conflict defines a file changed differently by both sides of a Merge, with the reason
+// they could not be reconciled.
+type Conflict struct {
+	Path   string
+	Reason string
+}
+
+// Merge returns the three-way merge of the local and upstream filesystems, which both derive from
+// base, along with the conflicts found. Files changed on one side only take that change, which applies
+// upstream changes to files left untouched locally while local edits are preserved. Text files changed
+// on both sides are merged line by line, where overlapping changes are written with conflict markers:
+//
+//	<<<<<<< local
+//	local lines
+//	=======
+//	upstream lines
+//	>>>>>>> upstream
+//
+// Other files changed on both sides keep the local file, or the upstream file if it was removed
+// locally, and are reported as conflicts. Files are matched by the paths given by Files, so the
+// filesystems should share the name of their root. Directories take the attributes of the local
+// directory, or the upstream one if it has none locally, and empty local directories are kept. Meta
+// keys take the upstream value if it changed from base, and the local value otherwise.
+func Merge(base MemoryFileSystem, local MemoryFileSystem, upstream MemoryFileSystem) (MemoryFileSystem, []Conflict, error) {
+	baseFiles, err := filesByPath(base)
+	if err != nil {
+		return MemoryFileSystem{}, nil, err
+	}
+
+	localFiles, err := filesByPath(local)
+	if err != nil {
+		return MemoryFileSystem{}, nil, err
+	}
+
+	upstreamFiles, err := filesByPath(upstream)
+	if err != nil {
+		return MemoryFileSystem{}, nil, err
+	}
+
+	var merged DirWriter
+	merged.Attrs = local.Dir.Attrs
+
+	var conflicts []Conflict
+
+	for _, filePath := range unionPaths(baseFiles, localFiles, upstreamFiles) {
+		baseFile, inBase := baseFiles[filePath]
+		localFile, inLocal := localFiles[filePath]
+		upstreamFile, inUpstream := upstreamFiles[filePath]
+
+		var file FileWriter
+		var keep bool
+		var reason string
+
+		switch {
+		case sameFile(localFile, inLocal, upstreamFile, inUpstream), sameFile(baseFile, inBase, upstreamFile, inUpstream):
+			file, keep = localFile.file, inLocal
+		case sameFile(baseFile, inBase, localFile, inLocal):
+			file, keep = upstreamFile.file, inUpstream
+		case inLocal && inUpstream && baseFile.text() && localFile.text() && upstreamFile.text():
+			lines, clean := mergeLines(splitLines(baseFile.data), splitLines(localFile.data), splitLines(upstreamFile.data))
+
+			file, keep = localFile.file, true
+			file.Content = Content(strings.Join(lines, ""))
+
+			// The upstream mode applies when the local mode is left unchanged.
+			if inBase && localFile.file.FileMode() == baseFile.file.FileMode() {
+				file.Mode = upstreamFile.file.Mode
+			}
+
+			if !clean {
+				reason = "Conflicting changes on both sides"
+			}
+		case !inUpstream:
+			file, keep = localFile.file, true
+			reason = "Modified locally and removed upstream"
+		case !inLocal:
+			file, keep = upstreamFile.file, true
+			reason = "Removed locally and modified upstream"
+		default:
+			file, keep = localFile.file, true
+			reason = "Binary content or link changed on both sides"
+		}
+
+		if reason != "" {
+			conflicts = append(conflicts, Conflict{Path: filePath, Reason: reason})
+		}
+
+		if !keep {
+			continue
+		}
+
+		if err := merged.add(strings.Split(path.Dir(filePath), "/"), file); err != nil {
+			conflicts = append(conflicts, Conflict{Path: filePath, Reason: err.Error()})
+		}
+	}
+
+	if err := mergeDirs(&merged, local, upstream); err != nil {
+		return MemoryFileSystem{}, nil, err
+	}
+
+	var mfs MemoryFileSystem
+	mfs.Dir = merged
+	mfs.Meta = mergeMeta(base.Meta, local.Meta, upstream.Meta)
+
+	// Files of a named root are held within a directory of its name, which becomes the root.
+	if name := local.Dir.Name; name != "" && name != "." && len(merged.ChildFiles) == 0 && len(merged.ChildDirs) == 1 && merged.ChildDirs[0].Name == name {
+		mfs.Dir = merged.ChildDirs[0]
+	}
+
+	return mfs, conflicts, nil
+}
+
+// sameFile returns true/false if both files are missing or equal.
+func sameFile(first pathFile, inFirst bool, second pathFile, inSecond bool) bool {
+	if !inFirst || !inSecond {
+		return inFirst == inSecond
+	}
+
+	return first.equal(second)
+}
+
+// mergeDirs gives the directories of the merged tree the attributes of the local directory of the same
+// path, or the upstream one, and adds the empty directories of the local filesystem.
+func mergeDirs(merged *DirWriter, local MemoryFileSystem, upstream MemoryFileSystem) error {
+	attrs := make(map[string]Attrs)
+
+	// Local attributes are collected last, taking precedence over upstream ones.
+	for index, fsm := range []MemoryFileSystem{upstream, local} {
+		isLocal := index == 1
+
+		if err := fsm.Dirs(func(parentPath string, hostDir DirWriter) error {
+			dirPath := path.Join(parentPath, hostDir.Name)
+			attrs[dirPath] = hostDir.Attrs
+
+			if isLocal && dirPath != "" && len(hostDir.ChildFiles) == 0 && len(hostDir.ChildDirs) == 0 {
+				// Empty directories conflicting with merged files are dropped.
+				merged.ensureDir(strings.Split(dirPath, "/"))
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	return runThroughDirs(*merged, "", func(parentPath string, hostDir DirWriter) error {
+		dirPath := path.Join(parentPath, hostDir.Name)
+		if dirAttrs, ok := attrs[dirPath]; ok && dirPath != "" {
+			dir, err := merged.ensureDir(strings.Split(dirPath, "/"))
+			if err != nil {
+				return err
+			}
+
+			dir.Attrs = dirAttrs
+		}
+
+		return nil
+	})
+}
+
+// mergeMeta returns the local Meta with the keys changed upstream from base taking the upstream value.
+func mergeMeta(base map[string]interface{}, local map[string]interface{}, upstream map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(local))
+	for key, value := range local {
+		merged[key] = value
+	}
+
+	for key, value := range upstream {
+		if baseValue, ok := base[key]; !ok || !reflect.DeepEqual(baseValue, value) {
+			merged[key] = value
+		}
+	}
+
+	for key := range base {
+		if _, ok := upstream[key]; !ok {
+			delete(merged, key)
+		}
+	}
+
+	return merged
+}
+
+// mergeLines returns the three-way merge of the local and upstream lines derived from the base lines,
+// with overlapping changes written between conflict markers, and true/false if it has no conflicts.
+//
+// Lines of base kept by both sides divide the lines into chunks, each of which takes the side which
+// changed it, or is a conflict if both sides changed it differently.
+func mergeLines(base []string, local []string, upstream []string) ([]string, bool) {
+	localMatches := matchLines(base, local)
+	upstreamMatches := matchLines(base, upstream)
+
+	var merged []string
+	clean := true
+
+	var baseAt, localAt, upstreamAt int
+	for {
+		next := baseAt
+		for next < len(base) && (localMatches[next] == -1 || upstreamMatches[next] == -1) {
+			next++
+		}
+
+		localNext, upstreamNext := len(local), len(upstream)
+		if next < len(base) {
+			localNext, upstreamNext = localMatches[next], upstreamMatches[next]
+		}
+
+		baseChunk := base[baseAt:next]
+		localChunk := local[localAt:localNext]
+		upstreamChunk := upstream[upstreamAt:upstreamNext]
+
+		switch {
+		case equalLines(localChunk, baseChunk), equalLines(localChunk, upstreamChunk):
+			merged = append(merged, upstreamChunk...)
+		case equalLines(upstreamChunk, baseChunk):
+			merged = append(merged, localChunk...)
+		default:
+			clean = false
+
+			merged = append(merged, "<<<<<<< local\n")
+			merged = append(merged, terminateLines(localChunk)...)
+			merged = append(merged, "=======\n")
+			merged = append(merged, terminateLines(upstreamChunk)...)
+			merged = append(merged, ">>>>>>> upstream\n")
+		}
+
+		if next == len(base) {
+			break
+		}
+
+		merged = append(merged, base[next])
+		baseAt, localAt, upstreamAt = next+1, localNext+1, upstreamNext+1
+	}
+
+	return merged, clean
+}
+
+// matchLines returns the index of the line of other matching each line of base, or -1 for lines
+// of base which other does not keep.
+func matchLines(base []string, other []string) []int {
+	matches := make([]int, len(base))
+	for index := range matches {
+		matches[index] = -1
+	}
+
+	for _, op := range diffLines(base, other) {
+		if op.kind == ' ' {
+			matches[op.from] = op.to
+		}
+	}
+
+	return matches
+}
+
+// equalLines returns true/false if both sets of lines are equal.
+func equalLines(first []string, second []string) bool {
+	if len(first) != len(second) {
+		return false
+	}
+
+	for index := range first {
+		if first[index] != second[index] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// terminateLines returns the lines with a line feed added to the last line if it has none, so
+// a conflict marker can follow it.
+func terminateLines(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+
+	terminated := append([]string(nil), lines...)
+	terminated[len(terminated)-1] += "\n"
+
+	return terminated
+}
@@ -0,0 +1,140 @@
+package filesystem_test
+
+import (
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/gen/filesystem"
+)
+
+func TestMerge(t *testing.T) {
+	base := filesystem.FileSystem(
+		filesystem.Meta("version", "1.0"),
+		filesystem.Meta("author", "moz"),
+		filesystem.File("readme.md", filesystem.Content("# App\n\nBuilt by moz.\n")),
+		filesystem.File("config.yml", filesystem.Content("port: 80\nhost: localhost\n")),
+		filesystem.File("removed.go", filesystem.Content("package main\n")),
+		filesystem.File("edited.go", filesystem.Content("package main\n")),
+		filesystem.Dir(
+			"app",
+			filesystem.File("main.go", filesystem.Content("package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"v1\")\n}\n")),
+		),
+	)
+
+	local := filesystem.FileSystem(
+		filesystem.Meta("version", "1.0"),
+		filesystem.Meta("author", "me"),
+		filesystem.File("readme.md", filesystem.Content("# My App\n\nBuilt by moz.\n")),
+		filesystem.File("config.yml", filesystem.Content("port: 8080\nhost: localhost\n")),
+		filesystem.File("notes.txt", filesystem.Content("local notes\n")),
+		filesystem.File("edited.go", filesystem.Content("package main\n\n// Edited locally.\n")),
+		filesystem.Dir(
+			"app",
+			filesystem.File("main.go", filesystem.Content("package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"v1\")\n\tfmt.Println(\"local\")\n}\n")),
+		),
+		filesystem.Dir("logs").WithMode(0700),
+	)
+
+	upstream := filesystem.FileSystem(
+		filesystem.Meta("version", "2.0"),
+		filesystem.Meta("author", "moz"),
+		filesystem.File("readme.md", filesystem.Content("# App\n\nBuilt by moz.\n\nSee docs.\n")),
+		filesystem.File("config.yml", filesystem.Content("port: 9090\nhost: localhost\n")),
+		filesystem.File("removed.go", filesystem.Content("package main\n")),
+		filesystem.File("added.go", filesystem.Content("package main\n")),
+		filesystem.File("edited.go", filesystem.Content("package main\n")),
+		filesystem.Dir(
+			"app",
+			filesystem.File("main.go", filesystem.Content("package main\n\nimport \"os\"\n\nfunc main() {\n\tfmt.Println(\"v1\")\n}\n")),
+		),
+	)
+
+	merged, conflicts, err := filesystem.Merge(base, local, upstream)
+	if err != nil {
+		tests.Failed("Should have successfully merged filesystems: %+q", err)
+	}
+	tests.Passed("Should have successfully merged filesystems")
+
+	expected := map[string]string{
+		"readme.md":   "# My App\n\nBuilt by moz.\n\nSee docs.\n",
+		"config.yml":  "<<<<<<< local\nport: 8080\n=======\nport: 9090\n>>>>>>> upstream\nhost: localhost\n",
+		"notes.txt":   "local notes\n",
+		"added.go":    "package main\n",
+		"edited.go":   "package main\n\n// Edited locally.\n",
+		"app/main.go": "package main\n\nimport \"os\"\n\nfunc main() {\n\tfmt.Println(\"v1\")\n\tfmt.Println(\"local\")\n}\n",
+	}
+
+	for filePath, content := range expected {
+		data, err := merged.ReadFile(filePath)
+		if err != nil {
+			tests.Failed("Should have successfully found merged file %q: %+q", filePath, err)
+		}
+
+		if string(data) != content {
+			tests.Info("Content: %q", data)
+			tests.Failed("Should have successfully merged content of %q", filePath)
+		}
+	}
+	tests.Passed("Should have successfully merged contents")
+
+	// removed.go is removed locally while unchanged upstream, so it stays removed.
+	if _, err := merged.Stat("removed.go"); err == nil {
+		tests.Failed("Should have successfully kept file removed locally")
+	}
+	tests.Passed("Should have successfully kept file removed locally")
+
+	if info, err := merged.Stat("logs"); err != nil || info.Mode().Perm() != 0700 {
+		tests.Failed("Should have successfully kept empty local directory with its mode")
+	}
+	tests.Passed("Should have successfully kept empty local directory with its mode")
+
+	if len(conflicts) != 1 || conflicts[0].Path != "config.yml" {
+		tests.Info("Conflicts: %+v", conflicts)
+		tests.Failed("Should have successfully reported conflict of config.yml")
+	}
+	tests.Passed("Should have successfully reported conflict of config.yml")
+
+	if merged.Meta["version"] != "2.0" || merged.Meta["author"] != "me" {
+		tests.Info("Meta: %+v", merged.Meta)
+		tests.Failed("Should have successfully merged Meta")
+	}
+	tests.Passed("Should have successfully merged Meta")
+}
+
+func TestMergeFileConflicts(t *testing.T) {
+	base := filesystem.FileSystem(
+		filesystem.File("logo.png", filesystem.ContentByte([]byte{0x89, 'P', 'N', 'G', 0})),
+		filesystem.File("main.go", filesystem.Content("package main\n")),
+	)
+
+	local := filesystem.FileSystem(
+		filesystem.File("logo.png", filesystem.ContentByte([]byte{0x89, 'P', 'N', 'G', 1})),
+	)
+
+	upstream := filesystem.FileSystem(
+		filesystem.File("logo.png", filesystem.ContentByte([]byte{0x89, 'P', 'N', 'G', 2})),
+		filesystem.File("main.go", filesystem.Content("package app\n")),
+	)
+
+	merged, conflicts, err := filesystem.Merge(base, local, upstream)
+	if err != nil {
+		tests.Failed("Should have successfully merged filesystems: %+q", err)
+	}
+	tests.Passed("Should have successfully merged filesystems")
+
+	if len(conflicts) != 2 || conflicts[0].Path != "logo.png" || conflicts[1].Path != "main.go" {
+		tests.Info("Conflicts: %+v", conflicts)
+		tests.Failed("Should have successfully reported binary and removal conflicts")
+	}
+	tests.Passed("Should have successfully reported binary and removal conflicts")
+
+	if data, err := merged.ReadFile("logo.png"); err != nil || data[4] != 1 {
+		tests.Failed("Should have successfully kept local binary file")
+	}
+	tests.Passed("Should have successfully kept local binary file")
+
+	if data, err := merged.ReadFile("main.go"); err != nil || string(data) != "package app\n" {
+		tests.Failed("Should have successfully kept upstream file modified after local removal")
+	}
+	tests.Passed("Should have successfully kept upstream file modified after local removal")
+}
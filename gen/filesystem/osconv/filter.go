@@ -0,0 +1,234 @@
+package osconv
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/influx6/moz/gen/filesystem"
+)
+
+// Filter defines a function which reports whether the file or directory at abs, described by info,
+// is kept when walking a directory, where rel is its slash separated path relative to the walked
+// directory. Directories which are not kept are skipped along with everything within them.
+type Filter func(rel string, abs string, info os.FileInfo) bool
+
+// keep returns true/false if all filters keep the path.
+func keep(filters []Filter, rel string, abs string, info os.FileInfo) bool {
+	for _, filter := range filters {
+		if !filter(rel, abs, info) {
+			return false
+		}
+	}
+	return true
+}
+
+// Path returns a Filter keeping paths for which fn returns true, given the path of the file joined
+// to the walked directory, as the filter function of ConvertDir is.
+func Path(fn func(string) bool) Filter {
+	return func(rel string, abs string, info os.FileInfo) bool {
+		return fn(abs)
+	}
+}
+
+// All returns a Filter keeping paths kept by all the filters.
+func All(filters ...Filter) Filter {
+	return func(rel string, abs string, info os.FileInfo) bool {
+		return keep(filters, rel, abs, info)
+	}
+}
+
+// Any returns a Filter keeping paths kept by any of the filters.
+func Any(filters ...Filter) Filter {
+	return func(rel string, abs string, info os.FileInfo) bool {
+		for _, filter := range filters {
+			if filter(rel, abs, info) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a Filter keeping paths the filter does not keep.
+func Not(filter Filter) Filter {
+	return func(rel string, abs string, info os.FileInfo) bool {
+		return !filter(rel, abs, info)
+	}
+}
+
+// Include returns a Filter keeping files which match any of the glob patterns, while directories
+// are kept so their files can be matched. Patterns follow Match.
+func Include(patterns ...string) Filter {
+	return func(rel string, abs string, info os.FileInfo) bool {
+		return info.IsDir() || matchAny(patterns, rel)
+	}
+}
+
+// Exclude returns a Filter skipping files and directories which match any of the glob patterns.
+// Patterns follow Match.
+func Exclude(patterns ...string) Filter {
+	return func(rel string, abs string, info os.FileInfo) bool {
+		return !matchAny(patterns, rel)
+	}
+}
+
+// NoHidden returns a Filter skipping files and directories whose name starts with a dot.
+func NoHidden() Filter {
+	return func(rel string, abs string, info os.FileInfo) bool {
+		return !strings.HasPrefix(path.Base(rel), ".")
+	}
+}
+
+// MaxSize returns a Filter skipping files larger than the giving size in bytes.
+func MaxSize(size int64) Filter {
+	return func(rel string, abs string, info os.FileInfo) bool {
+		return info.IsDir() || info.Size() <= size
+	}
+}
+
+// IgnoreFile returns a Filter skipping files and directories ignored by the ignore files of the giving
+// name (e.g .gitignore) found in the walked directory and the directories within it, which follow the
+// .gitignore format: patterns are matched relative to the directory holding the file, patterns without
+// a slash match names at any depth, a trailing slash matches only directories, a leading "!" keeps
+// paths ignored by earlier patterns, and patterns of deeper files take precedence.
+func IgnoreFile(name string) Filter {
+	var ml sync.Mutex
+	cache := make(map[string][]ignoreRule)
+
+	rulesIn := func(dir string) []ignoreRule {
+		ml.Lock()
+		defer ml.Unlock()
+
+		rules, ok := cache[dir]
+		if !ok {
+			rules = readIgnoreFile(filepath.Join(dir, name))
+			cache[dir] = rules
+		}
+
+		return rules
+	}
+
+	return func(rel string, abs string, info os.FileInfo) bool {
+		levels := strings.Split(rel, "/")
+		root := abs
+		for range levels {
+			root = filepath.Dir(root)
+		}
+
+		ignored := false
+
+		// Ignore files are consulted from the walked directory down to the parent of the path.
+		dir := root
+		for index := range levels {
+			within := path.Join(levels[index:]...)
+
+			for _, rule := range rulesIn(dir) {
+				if rule.matches(within, info.IsDir()) {
+					ignored = !rule.negate
+				}
+			}
+
+			dir = filepath.Join(dir, levels[index])
+		}
+
+		return !ignored
+	}
+}
+
+//=======================================================================================================================================
+
+// ignoreRule defines a pattern of an ignore file.
+type ignoreRule struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// matches returns true/false if the rule matches the slash separated path relative to the directory
+// of its ignore file.
+func (rule ignoreRule) matches(rel string, isDir bool) bool {
+	if rule.dirOnly && !isDir {
+		return false
+	}
+
+	if rule.anchored {
+		return Match(rule.pattern, rel)
+	}
+
+	return Match(rule.pattern, path.Base(rel))
+}
+
+// readIgnoreFile returns the rules of the ignore file at the giving path, or none if it can not be read.
+func readIgnoreFile(file string) []ignoreRule {
+	ignoreFile, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+
+	defer ignoreFile.Close()
+
+	var rules []ignoreRule
+
+	scanner := bufio.NewScanner(ignoreFile)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:]
+		}
+
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+
+		rule.anchored = strings.Contains(line, "/")
+		rule.pattern = strings.TrimPrefix(line, "/")
+
+		if rule.pattern != "" {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// matchAny returns true/false if the slash separated path matches any of the patterns.
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			if Match(pattern, path.Base(rel)) {
+				return true
+			}
+			continue
+		}
+
+		if Match(strings.TrimPrefix(pattern, "/"), rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns true/false if the slash separated path matches the glob pattern, as filesystem.Match
+// does, where each element of the pattern is matched as by path.Match and a "**" element matches any
+// number of elements, including none (e.g "assets/**/*.css" matches "assets/site.css" and
+// "assets/css/site.css"). Given to Include and Exclude, patterns without a slash match the name of a
+// path at any depth, while others match the path from the walked directory. Malformed patterns match
+// nothing.
+func Match(pattern string, rel string) bool {
+	return filesystem.Match(pattern, rel)
+}
@@ -0,0 +1,132 @@
+package osconv_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/gen/filesystem"
+	"github.com/influx6/moz/gen/filesystem/osconv"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		matches bool
+	}{
+		{"*.go", "main.go", true},
+		{"app/*.go", "app/main.go", true},
+		{"app/*.go", "app/src/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "app/src/main.go", true},
+		{"assets/**/*.css", "assets/site.css", true},
+		{"assets/**/*.css", "assets/css/theme/site.css", true},
+		{"assets/**/*.css", "app/site.css", false},
+		{"assets/**", "assets/css/site.css", true},
+		{"[", "main.go", false},
+	}
+
+	for _, item := range cases {
+		if osconv.Match(item.pattern, item.path) != item.matches {
+			tests.Info("Pattern: %q, Path: %q", item.pattern, item.path)
+			tests.Failed("Should have successfully matched glob pattern")
+		}
+	}
+	tests.Passed("Should have successfully matched glob patterns")
+}
+
+func TestFilters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "moz-osconv-filter")
+	if err != nil {
+		tests.Failed("Should have successfully created temporary directory: %+q", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		".gitignore":                "# build output\n*.log\nbuild/\n!keep.log\n/secret.txt\n",
+		"secret.txt":                "root secret",
+		"app/main.go":               "package main",
+		"app/main_test.go":          "package main",
+		"app/.gitignore":            "*_test.go\n",
+		"app/.env":                  "TOKEN=1",
+		"app/debug.log":             "debug",
+		"app/keep.log":              "keep",
+		"app/secret.txt":            "not anchored here",
+		"app/build/main":            "binary",
+		"assets/site.css":           "body {}",
+		"assets/css/theme/dark.css": "body {}",
+		"assets/large.png":          strings.Repeat("x", 2048),
+	}
+
+	for name, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+			tests.Failed("Should have successfully created fixture directory: %+q", err)
+		}
+
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			tests.Failed("Should have successfully written fixture file: %+q", err)
+		}
+	}
+
+	memFS, err := osconv.ConvertDirWith(dir, false, osconv.IgnoreFile(".gitignore"), osconv.NoHidden(), osconv.MaxSize(1024))
+	if err != nil {
+		tests.Failed("Should have successfully converted filtered directory: %+q", err)
+	}
+
+	var converted []string
+	memFS.Files(func(hostFilePath string, hostFile filesystem.FileWriter) error {
+		converted = append(converted, hostFilePath)
+		return nil
+	})
+
+	sort.Strings(converted)
+
+	expected := []string{"app/keep.log", "app/main.go", "app/secret.txt", "assets/css/theme/dark.css", "assets/site.css"}
+	if strings.Join(converted, ",") != strings.Join(expected, ",") {
+		tests.Info("Converted: %+q", converted)
+		tests.Failed("Should have successfully filtered converted files")
+	}
+	tests.Passed("Should have successfully filtered converted files")
+
+	custom := osconv.Filter(func(rel string, abs string, info os.FileInfo) bool {
+		return !strings.Contains(rel, "theme")
+	})
+
+	var walked []string
+	if err := osconv.WalkDirWith(dir, func(rel string, abs string, info os.FileInfo) error {
+		walked = append(walked, filepath.ToSlash(rel))
+		return nil
+	}, osconv.Include("**/*.css", "*.go"), osconv.Exclude("app/**"), custom); err != nil {
+		tests.Failed("Should have successfully walked filtered directory: %+q", err)
+	}
+
+	sort.Strings(walked)
+
+	if strings.Join(walked, ",") != "assets/site.css" {
+		tests.Info("Walked: %+q", walked)
+		tests.Failed("Should have successfully filtered walked files")
+	}
+	tests.Passed("Should have successfully filtered walked files")
+
+	either := osconv.Any(osconv.Include("*.css"), osconv.Not(osconv.MaxSize(1024)))
+	memFS, err = osconv.ConvertDirWith(dir, true, osconv.Exclude("app"), either)
+	if err != nil {
+		tests.Failed("Should have successfully converted filtered directory: %+q", err)
+	}
+
+	if _, err := memFS.GetFile("assets/large.png"); err != nil {
+		tests.Failed("Should have successfully composed filters: %+q", err)
+	}
+
+	if _, err := memFS.GetFile("secret.txt"); err == nil {
+		tests.Failed("Should have successfully composed filters")
+	}
+	tests.Passed("Should have successfully composed filters")
+}
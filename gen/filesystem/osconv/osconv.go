@@ -5,11 +5,14 @@ package osconv
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 
 	"github.com/influx6/moz/gen"
 	"github.com/influx6/moz/gen/filesystem"
@@ -20,8 +23,16 @@ import (
 // paths that do not.
 // If `deferData` is set to true, it returns structures whoes files do not load their data into memory but wait
 // till their `WriteTo` methods are called to improve memory usage.
+// The mode, modification time and owner of files and directories are kept, symbolic links are converted
+// into links to their target and other entries, such as devices and sockets, are skipped.
 func ConvertDir(dir string, deferData bool, filterFn func(string) bool) (filesystem.MemoryFileSystem, error) {
-	files, dirs, err := readDir(dir, deferData, filterFn)
+	return ConvertDirWith(dir, deferData, Path(filterFn))
+}
+
+// ConvertDirWith returns a filesystem.DirWriter structure for the giving directory path and its children, as
+// ConvertDir does, keeping only the files and directories kept by all the filters.
+func ConvertDirWith(dir string, deferData bool, filters ...Filter) (filesystem.MemoryFileSystem, error) {
+	files, dirs, err := readDir(dir, "", deferData, filters)
 	if err != nil {
 		return filesystem.MemoryFileSystem{}, err
 	}
@@ -37,8 +48,22 @@ func ConvertDir(dir string, deferData bool, filterFn func(string) bool) (filesys
 	return fsm, nil
 }
 
-func readDir(path string, deferData bool, filterFn func(string) bool) ([]filesystem.FileWriter, []filesystem.DirWriter, error) {
-	dir, err := os.Open(path)
+// DiffDir returns the changes which turn the files of the directory, kept by all the filters, into those
+// of the filesystem, as returned by filesystem.Diff. The root of the filesystem should not be named, as
+// the files of the directory are at paths relative to it.
+func DiffDir(dir string, fsm filesystem.MemoryFileSystem, filters ...Filter) ([]filesystem.Change, error) {
+	current, err := ConvertDirWith(dir, true, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return filesystem.Diff(current, fsm)
+}
+
+// readDir returns the files and directories kept by the filters within dirPath, which is at the slash separated
+// path rel within the walked directory.
+func readDir(dirPath string, rel string, deferData bool, filters []Filter) ([]filesystem.FileWriter, []filesystem.DirWriter, error) {
+	dir, err := os.Open(dirPath)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -57,21 +82,27 @@ func readDir(path string, deferData bool, filterFn func(string) bool) ([]filesys
 		return nil, nil, err
 	}
 
+	// Entries are taken in name order, so conversions of the same directory match.
+	sort.Slice(fileInfos, func(i, j int) bool {
+		return fileInfos[i].Name() < fileInfos[j].Name()
+	})
+
 	var files []filesystem.FileWriter
 	var dirs []filesystem.DirWriter
 
 	for _, info := range fileInfos {
-		if !filterFn(filepath.Join(path, info.Name())) {
+		if !keep(filters, path.Join(rel, info.Name()), filepath.Join(dirPath, info.Name()), info) {
 			continue
 		}
 
 		if info.IsDir() {
-			subFiles, subDirs, err := readDir(filepath.Join(path, info.Name()), deferData, filterFn)
+			subFiles, subDirs, err := readDir(filepath.Join(dirPath, info.Name()), path.Join(rel, info.Name()), deferData, filters)
 			if err != nil {
 				return nil, nil, err
 			}
 
 			mainDir := filesystem.Dir(info.Name())
+			mainDir.Attrs = attrsOf(info)
 			mainDir.ChildDirs = append(mainDir.ChildDirs, subDirs...)
 			mainDir.ChildFiles = append(mainDir.ChildFiles, subFiles...)
 
@@ -79,12 +110,29 @@ func readDir(path string, deferData bool, filterFn func(string) bool) ([]filesys
 			continue
 		}
 
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err := ConvertLink(filepath.Join(dirPath, info.Name()))
+			if err != nil {
+				return nil, nil, err
+			}
+
+			files = append(files, link)
+			continue
+		}
+
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
 		if deferData {
-			files = append(files, ConvertFile(filepath.Join(path, info.Name())))
+			file := ConvertFile(filepath.Join(dirPath, info.Name()))
+			file.Attrs = attrsOf(info)
+
+			files = append(files, file)
 			continue
 		}
 
-		dataFile, err := ConvertDataFile(filepath.Join(path, info.Name()))
+		dataFile, err := ConvertDataFile(filepath.Join(dirPath, info.Name()))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -95,10 +143,16 @@ func readDir(path string, deferData bool, filterFn func(string) bool) ([]filesys
 	return files, dirs, nil
 }
 
-// ConvertDataFile returns a filesystem.FileWriter structure for the giving file path with the data pulled in directly.
+// ConvertDataFile returns a filesystem.FileWriter structure for the giving file path with the data pulled in directly,
+// along with the mode, modification time and owner of the file.
 func ConvertDataFile(file string) (filesystem.FileWriter, error) {
 	var filew filesystem.FileWriter
 
+	info, err := os.Stat(file)
+	if err != nil {
+		return filew, err
+	}
+
 	var buf bytes.Buffer
 	reader := FromFile{FilePath: file}
 	if _, err := reader.WriteTo(&buf); err != nil {
@@ -107,10 +161,37 @@ func ConvertDataFile(file string) (filesystem.FileWriter, error) {
 
 	filew.Name = path.Base(file)
 	filew.Content = &buf
+	filew.Attrs = attrsOf(info)
 
 	return filew, nil
 }
 
+// ConvertLink returns a filesystem.FileWriter structure for the symbolic link at the giving file path, linking to
+// the slash separated target of the link.
+func ConvertLink(file string) (filesystem.FileWriter, error) {
+	info, err := os.Lstat(file)
+	if err != nil {
+		return filesystem.FileWriter{}, err
+	}
+
+	target, err := os.Readlink(file)
+	if err != nil {
+		return filesystem.FileWriter{}, err
+	}
+
+	link := filesystem.Symlink(filepath.Base(file), filepath.ToSlash(target))
+	link.Attrs = attrsOf(info)
+
+	return link, nil
+}
+
+// attrsOf returns the filesystem.Attrs of the giving file info, with the owner taken where the platform provides it.
+func attrsOf(info os.FileInfo) filesystem.Attrs {
+	attrs := filesystem.Attrs{Mode: info.Mode(), ModTime: info.ModTime()}
+	attrs.UID, attrs.GID = ownerOf(info)
+	return attrs
+}
+
 // ConvertFile returns a filesystem.FileWriter structure for the giving file path with its data pull in later during its
 // WriteTo call.
 func ConvertFile(file string) filesystem.FileWriter {
@@ -140,6 +221,17 @@ func (fm *FromFile) WriteTo(w io.Writer) (int64, error) {
 	return (&gen.FromReader{R: osFile}).WriteTo(w)
 }
 
+// Size returns the size of the file on disk, which lets archive writers stream it without reading it
+// ahead, or -1 if the file can not be stat'ed.
+func (fm *FromFile) Size() int64 {
+	info, err := os.Stat(fm.FilePath)
+	if err != nil {
+		return -1
+	}
+
+	return info.Size()
+}
+
 //=======================================================================================================================================
 
 var errStopWalking = errors.New("stop walking directory")
@@ -149,8 +241,15 @@ var errStopWalking = errors.New("stop walking directory")
 type DirWalker func(rel string, abs string, info os.FileInfo) error
 
 // WalkDir will run through the provided path which is expected to be a directory
-// and runs the provided callback with the current path and FileInfo.
+// and runs the provided callback with the current path and FileInfo of every regular
+// file and symbolic link, whose FileInfo describes the link itself.
 func WalkDir(dir string, callback DirWalker) error {
+	return WalkDirWith(dir, callback)
+}
+
+// WalkDirWith runs through the directory as WalkDir does, running the callback only for files
+// kept by all the filters, and skipping directories which are not kept.
+func WalkDirWith(dir string, callback DirWalker, filters ...Filter) error {
 	isWin := runtime.GOOS == "windows"
 
 	cerr := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
@@ -159,8 +258,23 @@ func WalkDir(dir string, callback DirWalker) error {
 			return err
 		}
 
-		// If its a symlink, don't deal with it.
-		if !info.Mode().IsRegular() {
+		// Apply the filters to everything but the directory walked.
+		if len(filters) != 0 && path != dir {
+			relPath, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+
+			if !keep(filters, filepath.ToSlash(relPath), path, info) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		// If its not a file or a symlink, don't deal with it.
+		if !info.Mode().IsRegular() && info.Mode()&os.ModeSymlink == 0 {
 			return nil
 		}
 
@@ -186,3 +300,214 @@ func WalkDir(dir string, callback DirWalker) error {
 
 	return cerr
 }
+
+//=======================================================================================================================================
+
+// WriteDir lays the files and directories of the filesystem out under the toDir directory, creating
+// missing directories. As with ast.WriteDirective, existing files are replaced unless their FileWriter
+// sets DontOverride, which is ignored if doFileOverwrite is true. A root directory with a name is
+// created within toDir, as the paths given by filesystem.MemoryFileSystem.Files are.
+//
+// Files and directories which set a Mode or ModTime are given them once written, and symbolic links
+// are created to their target, failing for links which lead out of toDir, including through other links.
+// Owners are not applied, as changing them requires privileges.
+func WriteDir(fsm filesystem.MemoryFileSystem, toDir string, doFileOverwrite bool) error {
+	if err := os.MkdirAll(toDir, 0700); err != nil {
+		return fmt.Errorf("IOError: Unable to create directory: %+q", err)
+	}
+
+	if err := fsm.Dirs(func(hostDirPath string, hostDir filesystem.DirWriter) error {
+		dirPath, err := hostPath(toDir, path.Join(hostDirPath, hostDir.Name))
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(dirPath, 0700); err != nil {
+			return fmt.Errorf("IOError: Unable to create directory: %+q", err)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var links []string
+
+	if err := fsm.Files(func(hostFilePath string, hostFile filesystem.FileWriter) error {
+		filePath, err := hostPath(toDir, hostFilePath)
+		if err != nil {
+			return err
+		}
+
+		fileStat, err := os.Lstat(filePath)
+		if err == nil && !fileStat.IsDir() {
+			if hostFile.DontOverride && !doFileOverwrite {
+				return nil
+			}
+
+			// Existing links are removed, so files are never written through them.
+			if hostFile.Link != "" || fileStat.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(filePath); err != nil {
+					return err
+				}
+			}
+		}
+
+		if hostFile.Link != "" {
+			if !linkInside(toDir, hostFilePath, hostFile.Link) {
+				return fmt.Errorf("Link %q to %q leaves %q", hostFilePath, hostFile.Link, toDir)
+			}
+
+			links = append(links, hostFilePath)
+			return os.Symlink(filepath.FromSlash(hostFile.Link), filePath)
+		}
+
+		if err := writeFile(filePath, hostFile); err != nil {
+			return err
+		}
+
+		return applyAttrs(filePath, hostFile.Attrs, hostFile.FileMode())
+	}); err != nil {
+		return err
+	}
+
+	// Links are checked again once all are created, as a later link may lead an earlier one out of toDir.
+	for _, linkPath := range links {
+		filePath := filepath.Join(toDir, filepath.FromSlash(linkPath))
+
+		target, err := os.Readlink(filePath)
+		if err != nil {
+			return err
+		}
+
+		if !linkInside(toDir, linkPath, filepath.ToSlash(target)) {
+			os.Remove(filePath)
+			return fmt.Errorf("Link %q to %q leaves %q", linkPath, target, toDir)
+		}
+	}
+
+	// Directories are given their attributes last, as writing their files changes them.
+	return fsm.Dirs(func(hostDirPath string, hostDir filesystem.DirWriter) error {
+		if hostDirPath == "" && hostDir.Name == "" {
+			return nil
+		}
+
+		dirPath, err := hostPath(toDir, path.Join(hostDirPath, hostDir.Name))
+		if err != nil {
+			return err
+		}
+
+		return applyAttrs(dirPath, hostDir.Attrs, hostDir.DirMode())
+	})
+}
+
+// writeFile creates the file at filePath with the content of the FileWriter.
+func writeFile(filePath string, hostFile filesystem.FileWriter) error {
+	newFile, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	defer newFile.Close()
+
+	if hostFile.Content == nil {
+		return nil
+	}
+
+	if _, err := hostFile.Content.WriteTo(newFile); err != nil && err != io.EOF {
+		return fmt.Errorf("IOError: Unable to write content to file: %+q", err)
+	}
+
+	return nil
+}
+
+// applyAttrs sets the mode and modification time of the file or directory at filePath, if the attributes
+// set them.
+func applyAttrs(filePath string, attrs filesystem.Attrs, mode os.FileMode) error {
+	if attrs.Mode != 0 {
+		if err := os.Chmod(filePath, mode); err != nil {
+			return fmt.Errorf("IOError: Unable to change mode of %q: %+q", filePath, err)
+		}
+	}
+
+	if !attrs.ModTime.IsZero() {
+		if err := os.Chtimes(filePath, attrs.ModTime, attrs.ModTime); err != nil {
+			return fmt.Errorf("IOError: Unable to change times of %q: %+q", filePath, err)
+		}
+	}
+
+	return nil
+}
+
+// maxLinkHops defines the number of symbolic links followed when checking where a link leads.
+const maxLinkHops = 40
+
+// linkInside returns true/false if the symbolic link at the slash separated path linkPath within
+// toDir, linking to target, stays within toDir when followed on disk, following the links it meets
+// along the way. Links which are absolute or loop do not stay within toDir.
+func linkInside(toDir string, linkPath string, target string) bool {
+	if path.IsAbs(target) || filepath.IsAbs(filepath.FromSlash(target)) {
+		return false
+	}
+
+	var levels []string
+	if dir := path.Dir(linkPath); dir != "." {
+		levels = strings.Split(dir, "/")
+	}
+
+	pending := strings.Split(target, "/")
+
+	var hops int
+	for len(pending) != 0 {
+		level := pending[0]
+		pending = pending[1:]
+
+		switch level {
+		case "", ".":
+			continue
+		case "..":
+			if len(levels) == 0 {
+				return false
+			}
+			levels = levels[:len(levels)-1]
+			continue
+		}
+
+		levels = append(levels, level)
+
+		levelPath := filepath.Join(toDir, filepath.FromSlash(path.Join(levels...)))
+		if info, err := os.Lstat(levelPath); err != nil || info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+
+		link, err := os.Readlink(levelPath)
+		if err != nil || filepath.IsAbs(link) {
+			return false
+		}
+
+		if hops++; hops > maxLinkHops {
+			return false
+		}
+
+		levels = levels[:len(levels)-1]
+		pending = append(strings.Split(filepath.ToSlash(link), "/"), pending...)
+	}
+
+	return true
+}
+
+// hostPath returns the path on disk of the slash separated path within toDir, rejecting paths which
+// would leave toDir.
+func hostPath(toDir string, relPath string) (string, error) {
+	if path.IsAbs(relPath) {
+		return "", fmt.Errorf("Absolute path %q not allowed", relPath)
+	}
+
+	for _, level := range strings.Split(relPath, "/") {
+		if level == ".." {
+			return "", fmt.Errorf("Path %q leaves its root", relPath)
+		}
+	}
+
+	return filepath.Join(toDir, filepath.FromSlash(relPath)), nil
+}
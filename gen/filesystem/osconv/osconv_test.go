@@ -2,7 +2,12 @@ package osconv_test
 
 import (
 	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/influx6/faux/tests"
 	"github.com/influx6/moz/gen/filesystem"
@@ -33,3 +38,204 @@ func TestOSConv(t *testing.T) {
 	}
 	tests.Passed("Should have received written total above 0")
 }
+
+func TestWriteDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "moz-osconv")
+	if err != nil {
+		tests.Failed("Should have successfully created temporary directory: %+q", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "config.yml"), []byte("local"), 0644); err != nil {
+		tests.Failed("Should have successfully written existing file: %+q", err)
+	}
+
+	config := filesystem.File("config.yml", filesystem.Content("generated"))
+	config.DontOverride = true
+
+	memFS := filesystem.FileSystem(
+		config,
+		filesystem.File("main.go", filesystem.Content("package main")),
+		filesystem.Dir("app", filesystem.Dir("src", filesystem.File("app.go", filesystem.Content("package src")))),
+		filesystem.Dir("empty"),
+	)
+
+	if err := osconv.WriteDir(memFS, dir, false); err != nil {
+		tests.Failed("Should have successfully written filesystem to disk: %+q", err)
+	}
+	tests.Passed("Should have successfully written filesystem to disk")
+
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, "app/src/app.go")); string(data) != "package src" {
+		tests.Failed("Should have successfully written nested file")
+	}
+
+	if info, err := os.Stat(filepath.Join(dir, "empty")); err != nil || !info.IsDir() {
+		tests.Failed("Should have successfully created empty directory")
+	}
+
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, "config.yml")); string(data) != "local" {
+		tests.Failed("Should have successfully kept existing file marked DontOverride")
+	}
+	tests.Passed("Should have successfully laid out filesystem")
+
+	memFS.Dir.ChildFiles[0].Content = filesystem.Content("generated")
+	if err := osconv.WriteDir(memFS, dir, true); err != nil {
+		tests.Failed("Should have successfully overwritten filesystem on disk: %+q", err)
+	}
+
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, "config.yml")); string(data) != "generated" {
+		tests.Failed("Should have successfully overwritten existing file when forced")
+	}
+	tests.Passed("Should have successfully overwritten existing file when forced")
+
+	escaping := filesystem.FileSystem(filesystem.Dir("..", filesystem.File("out.go", filesystem.Content(""))))
+	if err := osconv.WriteDir(escaping, dir, false); err == nil {
+		tests.Failed("Should have failed to write path leaving directory")
+	}
+	tests.Passed("Should have failed to write path leaving directory")
+}
+
+func TestConvertDirAttrs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "moz-osconv")
+	if err != nil {
+		tests.Failed("Should have successfully created temporary directory: %+q", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	modTime := time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	memFS := filesystem.FileSystem(
+		filesystem.Dir(
+			"bin",
+			filesystem.Executable("run.sh", filesystem.Content("#!/bin/sh")).WithModTime(modTime),
+			filesystem.Symlink("start.sh", "run.sh"),
+		).WithMode(0750).WithModTime(modTime),
+	)
+
+	if err := osconv.WriteDir(memFS, dir, false); err != nil {
+		tests.Failed("Should have successfully written filesystem to disk: %+q", err)
+	}
+
+	if info, err := os.Stat(filepath.Join(dir, "bin/run.sh")); err != nil || info.Mode().Perm() != 0755 || !info.ModTime().Equal(modTime) {
+		tests.Failed("Should have successfully written file attributes: %+q", err)
+	}
+
+	if info, err := os.Stat(filepath.Join(dir, "bin")); err != nil || info.Mode().Perm() != 0750 || !info.ModTime().Equal(modTime) {
+		tests.Failed("Should have successfully written directory attributes: %+q", err)
+	}
+
+	if target, err := os.Readlink(filepath.Join(dir, "bin/start.sh")); err != nil || target != "run.sh" {
+		tests.Failed("Should have successfully written link: %+q", err)
+	}
+	tests.Passed("Should have successfully written attributes and links to disk")
+
+	converted, err := osconv.ConvertDir(dir, false, func(string) bool { return true })
+	if err != nil {
+		tests.Failed("Should have successfully run through dir: %+q", err)
+	}
+
+	run, err := converted.GetFile("bin/run.sh")
+	if err != nil || run.FileMode() != 0755 || !run.ModTime.Equal(modTime) || run.UID != os.Getuid() {
+		tests.Info("File: %+v", run.Attrs)
+		tests.Failed("Should have successfully captured file attributes: %+q", err)
+	}
+
+	start, err := converted.GetFile("bin/start.sh")
+	if err != nil || start.Link != "run.sh" || start.FileMode()&os.ModeSymlink == 0 {
+		tests.Failed("Should have successfully captured link: %+q", err)
+	}
+
+	bin, err := converted.GetDir("bin")
+	if err != nil || bin.DirMode() != os.ModeDir|0750 {
+		tests.Failed("Should have successfully captured directory attributes: %+q", err)
+	}
+	tests.Passed("Should have successfully captured attributes and links from disk")
+
+	var walked []string
+	osconv.WalkDir(dir, func(rel string, abs string, info os.FileInfo) error {
+		walked = append(walked, rel)
+		return nil
+	})
+
+	if len(walked) != 2 {
+		tests.Info("Walked: %+q", walked)
+		tests.Failed("Should have successfully walked files and links")
+	}
+	tests.Passed("Should have successfully walked files and links")
+}
+
+func TestDiffDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "moz-osconv")
+	if err != nil {
+		tests.Failed("Should have successfully created temporary directory: %+q", err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	current := filesystem.FileSystem(
+		filesystem.File("readme.md", filesystem.Content("# App\n")),
+		filesystem.File("old.md", filesystem.Content("old\n")),
+		filesystem.File(".env", filesystem.Content("SECRET=1\n")),
+	)
+
+	if err := osconv.WriteDir(current, dir, false); err != nil {
+		tests.Failed("Should have successfully written filesystem to disk: %+q", err)
+	}
+
+	skeleton := filesystem.FileSystem(
+		filesystem.File("readme.md", filesystem.Content("# App v2\n")),
+		filesystem.Dir("app", filesystem.File("main.go", filesystem.Content("package main\n"))),
+	)
+
+	changes, err := osconv.DiffDir(dir, skeleton, osconv.NoHidden())
+	if err != nil {
+		tests.Failed("Should have successfully diffed directory: %+q", err)
+	}
+
+	var found []string
+	for _, change := range changes {
+		found = append(found, change.Kind.String()+" "+change.Path)
+	}
+
+	if listed := fmt.Sprint(found); listed != "[added app/main.go removed old.md modified readme.md]" {
+		tests.Info("Changes: %s", listed)
+		tests.Failed("Should have successfully found changes from directory")
+	}
+
+	if changes[2].Diff != "--- a/readme.md\n+++ b/readme.md\n@@ -1 +1 @@\n-# App\n+# App v2\n" {
+		tests.Info("Diff: %q", changes[2].Diff)
+		tests.Failed("Should have successfully written unified diff from directory")
+	}
+	tests.Passed("Should have successfully found changes from directory")
+}
+
+func TestWriteDirLinkChains(t *testing.T) {
+	parent, err := ioutil.TempDir("", "moz-osconv")
+	if err != nil {
+		tests.Failed("Should have successfully created temporary directory: %+q", err)
+	}
+
+	defer os.RemoveAll(parent)
+
+	root := filepath.Join(parent, "root")
+
+	// The links are declared in the order which lets each pass a check made when it is created alone.
+	memFS := filesystem.FileSystem(
+		filesystem.Dir(
+			"x",
+			filesystem.Symlink("esc", "up/.."),
+			filesystem.Symlink("up", ".."),
+		),
+	)
+
+	if err := osconv.WriteDir(memFS, root, true); err == nil {
+		tests.Failed("Should have failed to write chain of links leaving the directory")
+	}
+
+	if _, err := os.Lstat(filepath.Join(root, "x/esc")); err == nil {
+		tests.Failed("Should have successfully removed link leaving the directory")
+	}
+	tests.Passed("Should have failed to write chain of links leaving the directory")
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package osconv
+
+import "os"
+
+// ownerOf returns zero ids, as the platform does not expose the owner of files.
+func ownerOf(info os.FileInfo) (int, int) {
+	return 0, 0
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package osconv
+
+import (
+	"os"
+	"syscall"
+)
+
+// ownerOf returns the user and group id owning the file described by info.
+func ownerOf(info os.FileInfo) (int, int) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(stat.Uid), int(stat.Gid)
+	}
+	return 0, 0
+}
@@ -0,0 +1,337 @@
+package filesystem
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/influx6/moz/gen"
+)
+
+// metaFile defines the name of the entry holding the Meta of a MemoryFileSystem within archives.
+const metaFile = ".meta"
+
+// maxLinkSize defines the maximum length of the target of a symbolic link held as content.
+const maxLinkSize = 4096
+
+// ErrLimitExceeded is returned when an archive exceeds the ReadLimits it is read with.
+var ErrLimitExceeded = errors.New("archive exceeds read limits")
+
+// ReadLimits defines the limits applied when reading an archive into a MemoryFileSystem, which guard
+// against archives expanding into more data than expected. A zero value disables a limit.
+type ReadLimits struct {
+	// MaxFileSize defines the maximum size in bytes of the content of a single file.
+	MaxFileSize int64
+
+	// MaxTotalSize defines the maximum size in bytes of the content of all files, or of the encoded
+	// document for JSON.
+	MaxTotalSize int64
+
+	// MaxFiles defines the maximum number of files.
+	MaxFiles int
+}
+
+// DefaultReadLimits defines limits suitable for reading archives from untrusted sources.
+var DefaultReadLimits = ReadLimits{
+	MaxFileSize:  64 << 20,
+	MaxTotalSize: 512 << 20,
+	MaxFiles:     10000,
+}
+
+// archiveReader builds a MemoryFileSystem from the entries of an archive within the ReadLimits.
+type archiveReader struct {
+	limits ReadLimits
+	fsm    MemoryFileSystem
+	files  int
+	total  int64
+}
+
+func newArchiveReader(limits ReadLimits) *archiveReader {
+	return &archiveReader{limits: limits, fsm: FileSystem()}
+}
+
+// entryPath returns the cleaned path of an archive entry, rejecting absolute paths and paths which
+// leave the root of the archive.
+func entryPath(name string) (string, error) {
+	name = strings.Replace(name, "\\", "/", -1)
+
+	if err := validPath(name); err != nil {
+		return "", err
+	}
+
+	return path.Clean(name), nil
+}
+
+// dir adds the directory of the archive entry with the giving attributes.
+func (ar *archiveReader) dir(name string, attrs Attrs) error {
+	entry, err := entryPath(name)
+	if err != nil {
+		return err
+	}
+
+	dir, err := ar.fsm.Dir.ensureDir(strings.Split(entry, "/"))
+	if err != nil {
+		return err
+	}
+
+	if entry != "." {
+		dir.Attrs = attrs
+	}
+
+	return nil
+}
+
+// link adds the symbolic link of the archive entry to the target, rejecting targets which are
+// absolute or leave the root of the archive, including through the links added before it.
+func (ar *archiveReader) link(name string, target string, attrs Attrs) error {
+	entry, err := ar.entry(name)
+	if err != nil {
+		return err
+	}
+
+	if target == "" || !ar.fsm.linkInside(entry, target) {
+		return fmt.Errorf("Archive entry %q links to %q outside of the archive", name, target)
+	}
+
+	link := Symlink(path.Base(entry), target)
+	link.Attrs = attrs
+	link.Mode = fs.ModeSymlink | attrs.Mode.Perm()
+
+	return ar.fsm.Dir.add(strings.Split(path.Dir(entry), "/"), link)
+}
+
+// done returns the MemoryFileSystem read from the archive once all its links are checked again, as
+// a link added later may lead an earlier one outside of the root of the archive.
+func (ar *archiveReader) done() (MemoryFileSystem, error) {
+	if err := ar.fsm.Files(func(hostFilePath string, hostFile FileWriter) error {
+		if hostFile.Link != "" && !ar.fsm.linkInside(hostFilePath, hostFile.Link) {
+			return fmt.Errorf("Archive entry %q links to %q outside of the archive", hostFilePath, hostFile.Link)
+		}
+		return nil
+	}); err != nil {
+		return MemoryFileSystem{}, err
+	}
+
+	return ar.fsm, nil
+}
+
+// entry returns the path of the archive entry for a file, counting it against MaxFiles.
+func (ar *archiveReader) entry(name string) (string, error) {
+	entry, err := entryPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	if entry == "." {
+		return "", fmt.Errorf("Archive entry %q has no name", name)
+	}
+
+	if ar.files++; ar.limits.MaxFiles > 0 && ar.files > ar.limits.MaxFiles {
+		return "", fmt.Errorf("%q: more than %d files: %w", name, ar.limits.MaxFiles, ErrLimitExceeded)
+	}
+
+	return entry, nil
+}
+
+// file adds the file of the archive entry with the content read from r and the giving attributes,
+// where a root .meta entry is decoded into the Meta of the filesystem.
+func (ar *archiveReader) file(name string, r io.Reader, attrs Attrs) error {
+	entry, err := ar.entry(name)
+	if err != nil {
+		return err
+	}
+
+	limit := int64(-1)
+	if ar.limits.MaxFileSize > 0 {
+		limit = ar.limits.MaxFileSize
+	}
+
+	if ar.limits.MaxTotalSize > 0 && (limit < 0 || ar.limits.MaxTotalSize-ar.total < limit) {
+		limit = ar.limits.MaxTotalSize - ar.total
+	}
+
+	if limit >= 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if limit >= 0 && int64(len(data)) > limit {
+		return fmt.Errorf("%q: content larger than %d bytes: %w", name, limit, ErrLimitExceeded)
+	}
+
+	ar.total += int64(len(data))
+
+	if entry == metaFile {
+		return json.Unmarshal(data, &ar.fsm.Meta)
+	}
+
+	file := File(path.Base(entry), ContentByte(data))
+	file.Attrs = attrs
+
+	return ar.fsm.Dir.add(strings.Split(path.Dir(entry), "/"), file)
+}
+
+//======================================================================================
+
+// FromZip returns a MemoryFileSystem holding the directories, files and symbolic links of the zip
+// archive of the giving size, as written by ZipFileSystem, with its Meta restored from the .meta entry
+// and the mode and modification time of entries kept.
+func FromZip(r io.ReaderAt, size int64, limits ReadLimits) (MemoryFileSystem, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return MemoryFileSystem{}, err
+	}
+
+	reader := newArchiveReader(limits)
+
+	for _, entry := range archive.File {
+		attrs := Attrs{Mode: entry.Mode()}
+		if entry.ModifiedDate != 0 || entry.ModifiedTime != 0 {
+			attrs.ModTime = entry.Modified
+		}
+
+		if entry.FileInfo().IsDir() {
+			if err := reader.dir(entry.Name, attrs); err != nil {
+				return MemoryFileSystem{}, err
+			}
+			continue
+		}
+
+		if !entry.Mode().IsRegular() && entry.Mode()&fs.ModeSymlink == 0 {
+			continue
+		}
+
+		content, err := entry.Open()
+		if err != nil {
+			return MemoryFileSystem{}, err
+		}
+
+		if entry.Mode()&fs.ModeSymlink != 0 {
+			var target []byte
+			if target, err = io.ReadAll(io.LimitReader(content, maxLinkSize)); err == nil {
+				err = reader.link(entry.Name, string(target), attrs)
+			}
+		} else {
+			err = reader.file(entry.Name, content, attrs)
+		}
+
+		content.Close()
+
+		if err != nil {
+			return MemoryFileSystem{}, err
+		}
+	}
+
+	return reader.done()
+}
+
+// FromTar returns a MemoryFileSystem holding the directories, regular files and symbolic links of the
+// tar archive, as written by TarFileSystem, with its Meta restored from the .meta entry and the attributes
+// of entries kept. Other entries, such as hard links and devices, are skipped.
+func FromTar(r io.Reader, limits ReadLimits) (MemoryFileSystem, error) {
+	archive := tar.NewReader(r)
+	reader := newArchiveReader(limits)
+
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return MemoryFileSystem{}, err
+		}
+
+		attrs := Attrs{
+			Mode:    header.FileInfo().Mode(),
+			ModTime: header.ModTime,
+			UID:     header.Uid,
+			GID:     header.Gid,
+			Owner:   header.Uname,
+			Group:   header.Gname,
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = reader.dir(header.Name, attrs)
+		case tar.TypeReg, tar.TypeRegA:
+			err = reader.file(header.Name, archive, attrs)
+		case tar.TypeSymlink:
+			err = reader.link(header.Name, header.Linkname, attrs)
+		}
+
+		if err != nil {
+			return MemoryFileSystem{}, err
+		}
+	}
+
+	return reader.done()
+}
+
+// FromGzipTar returns a MemoryFileSystem from the gzip compressed tar archive, as written by
+// GzipTarFileSystem, as FromTar does.
+func FromGzipTar(r io.Reader, limits ReadLimits) (MemoryFileSystem, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return MemoryFileSystem{}, err
+	}
+
+	defer gzr.Close()
+
+	return FromTar(gzr, limits)
+}
+
+// FromJSON returns a MemoryFileSystem holding the files of the JSON document, as written by
+// JSONFileSystem, with its Meta restored from the .meta entry.
+func FromJSON(r io.Reader, limits ReadLimits) (MemoryFileSystem, error) {
+	limit := limits.MaxTotalSize
+	if limit > 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+
+	counter := gen.NewWriteCounter(ioutil.Discard)
+	decoder := json.NewDecoder(io.TeeReader(r, counter))
+
+	var archive map[string]string
+	if err := decoder.Decode(&archive); err != nil {
+		if limit > 0 && counter.Written() > limit {
+			return MemoryFileSystem{}, fmt.Errorf("document larger than %d bytes: %w", limit, ErrLimitExceeded)
+		}
+		return MemoryFileSystem{}, err
+	}
+
+	reader := newArchiveReader(ReadLimits{MaxFileSize: limits.MaxFileSize, MaxFiles: limits.MaxFiles})
+
+	// Files are added in path order, as JSONFileSystem writes them.
+	for _, name := range sortedKeys(archive) {
+		if err := reader.file(name, strings.NewReader(archive[name]), Attrs{}); err != nil {
+			return MemoryFileSystem{}, err
+		}
+	}
+
+	return reader.done()
+}
+
+// sortedKeys returns the keys of the map in order.
+func sortedKeys(items map[string]string) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
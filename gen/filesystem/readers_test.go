@@ -0,0 +1,250 @@
+package filesystem_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/gen/filesystem"
+)
+
+func readerFixture() filesystem.MemoryFileSystem {
+	return filesystem.FileSystem(
+		filesystem.Version("1.0"),
+		filesystem.Description("FileSystem for the faas docker system"),
+		filesystem.Dir(
+			"app",
+			filesystem.File("readme.md", filesystem.Content("# App v1.0")),
+			filesystem.Dir(
+				"src",
+				filesystem.File("main.go", filesystem.Content(`package main`)),
+			),
+		),
+		filesystem.File("dockerfile", filesystem.Content(`FROM alpine:latest`)),
+	)
+}
+
+func TestFromArchives(t *testing.T) {
+	formats := map[string]func([]byte) (filesystem.MemoryFileSystem, error){
+		"zip": func(data []byte) (filesystem.MemoryFileSystem, error) {
+			return filesystem.FromZip(bytes.NewReader(data), int64(len(data)), filesystem.DefaultReadLimits)
+		},
+		"tar": func(data []byte) (filesystem.MemoryFileSystem, error) {
+			return filesystem.FromTar(bytes.NewReader(data), filesystem.DefaultReadLimits)
+		},
+		"tar.gz": func(data []byte) (filesystem.MemoryFileSystem, error) {
+			return filesystem.FromGzipTar(bytes.NewReader(data), filesystem.DefaultReadLimits)
+		},
+		"json": func(data []byte) (filesystem.MemoryFileSystem, error) {
+			return filesystem.FromJSON(bytes.NewReader(data), filesystem.DefaultReadLimits)
+		},
+	}
+
+	writers := map[string]filesystem.Filesystem{
+		"zip":    filesystem.ZipFS(readerFixture()),
+		"tar":    filesystem.TarFS(readerFixture()),
+		"tar.gz": filesystem.GzipTarFS(readerFixture()),
+		"json":   filesystem.JSONFS(readerFixture(), true),
+	}
+
+	for format, read := range formats {
+		var archive bytes.Buffer
+		if _, err := writers[format].WriteTo(&archive); err != nil {
+			tests.Failed("Should have successfully written %s archive: %+q", format, err)
+		}
+
+		memFS, err := read(archive.Bytes())
+		if err != nil {
+			tests.Failed("Should have successfully read %s archive: %+q", format, err)
+		}
+		tests.Passed("Should have successfully read %s archive", format)
+
+		if memFS.Meta["version"] != "1.0" || memFS.Meta["description"] != "FileSystem for the faas docker system" {
+			tests.Info("Meta: %+v", memFS.Meta)
+			tests.Failed("Should have successfully restored meta of %s archive", format)
+		}
+
+		if _, err := memFS.Stat(".meta"); err == nil {
+			tests.Failed("Should have successfully kept .meta out of files of %s archive", format)
+		}
+		tests.Passed("Should have successfully restored meta of %s archive", format)
+
+		if err := fstest.TestFS(memFS, "app/readme.md", "app/src/main.go", "dockerfile"); err != nil {
+			tests.Failed("Should have successfully restored files of %s archive: %+q", format, err)
+		}
+
+		if data, _ := fs.ReadFile(memFS, "app/src/main.go"); string(data) != "package main" {
+			tests.Failed("Should have successfully restored content of %s archive", format)
+		}
+		tests.Passed("Should have successfully restored files of %s archive", format)
+	}
+}
+
+func TestFromArchivesUntrusted(t *testing.T) {
+	tarOf := func(entries map[string]string) []byte {
+		var archive bytes.Buffer
+
+		writer := tar.NewWriter(&archive)
+		for name, content := range entries {
+			writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+			writer.Write([]byte(content))
+		}
+		writer.Close()
+
+		return archive.Bytes()
+	}
+
+	for _, name := range []string{"../evil.sh", "app/../../evil.sh", "/etc/passwd"} {
+		if _, err := filesystem.FromTar(bytes.NewReader(tarOf(map[string]string{name: "rm -rf"})), filesystem.DefaultReadLimits); err == nil {
+			tests.Failed("Should have failed to read entry %q leaving the archive", name)
+		}
+	}
+	tests.Passed("Should have failed to read entries leaving the archive")
+
+	memFS, err := filesystem.FromTar(bytes.NewReader(tarOf(map[string]string{"./app/main.go": "package main"})), filesystem.DefaultReadLimits)
+	if err != nil {
+		tests.Failed("Should have successfully read relative entry: %+q", err)
+	}
+
+	if _, err := memFS.GetFile("app/main.go"); err != nil {
+		tests.Failed("Should have successfully cleaned relative entry: %+q", err)
+	}
+	tests.Passed("Should have successfully cleaned relative entry")
+
+	limits := filesystem.ReadLimits{MaxFileSize: 4}
+	if _, err := filesystem.FromTar(bytes.NewReader(tarOf(map[string]string{"big.txt": "12345"})), limits); !errors.Is(err, filesystem.ErrLimitExceeded) {
+		tests.Failed("Should have failed to read file above size limit: %+q", err)
+	}
+
+	limits = filesystem.ReadLimits{MaxTotalSize: 6}
+	if _, err := filesystem.FromTar(bytes.NewReader(tarOf(map[string]string{"a.txt": "1234", "b.txt": "1234"})), limits); !errors.Is(err, filesystem.ErrLimitExceeded) {
+		tests.Failed("Should have failed to read files above total limit: %+q", err)
+	}
+
+	limits = filesystem.ReadLimits{MaxFiles: 1}
+	if _, err := filesystem.FromTar(bytes.NewReader(tarOf(map[string]string{"a.txt": "1", "b.txt": "2"})), limits); !errors.Is(err, filesystem.ErrLimitExceeded) {
+		tests.Failed("Should have failed to read files above count limit: %+q", err)
+	}
+
+	if _, err := filesystem.FromJSON(bytes.NewReader([]byte(`{"a.txt": "1234567890"}`)), filesystem.ReadLimits{MaxTotalSize: 8}); !errors.Is(err, filesystem.ErrLimitExceeded) {
+		tests.Failed("Should have failed to read json document above total limit: %+q", err)
+	}
+	tests.Passed("Should have failed to read archives above limits")
+}
+
+func TestArchiveAttrs(t *testing.T) {
+	modTime := time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	fixture := func() filesystem.MemoryFileSystem {
+		return filesystem.FileSystem(
+			filesystem.Dir(
+				"bin",
+				filesystem.Executable("run.sh", filesystem.Content("#!/bin/sh")).WithModTime(modTime).WithOwner(1000, 1000, "app", "staff"),
+				filesystem.Symlink("start.sh", "run.sh"),
+			).WithMode(0700).WithModTime(modTime),
+			filesystem.Dir("empty"),
+		)
+	}
+
+	formats := map[string]func() (filesystem.MemoryFileSystem, error){
+		"zip": func() (filesystem.MemoryFileSystem, error) {
+			var archive bytes.Buffer
+			if _, err := filesystem.ZipFS(fixture()).WriteTo(&archive); err != nil {
+				return filesystem.MemoryFileSystem{}, err
+			}
+			return filesystem.FromZip(bytes.NewReader(archive.Bytes()), int64(archive.Len()), filesystem.DefaultReadLimits)
+		},
+		"tar.gz": func() (filesystem.MemoryFileSystem, error) {
+			var archive bytes.Buffer
+			if _, err := filesystem.GzipTarFS(fixture()).WriteTo(&archive); err != nil {
+				return filesystem.MemoryFileSystem{}, err
+			}
+			return filesystem.FromGzipTar(&archive, filesystem.DefaultReadLimits)
+		},
+	}
+
+	for format, read := range formats {
+		memFS, err := read()
+		if err != nil {
+			tests.Failed("Should have successfully round tripped %s archive: %+q", format, err)
+		}
+
+		run, err := memFS.GetFile("bin/run.sh")
+		if err != nil || run.FileMode() != 0755 || !run.ModTime.Equal(modTime) {
+			tests.Info("File: %+v", run.Attrs)
+			tests.Failed("Should have successfully kept file attributes in %s archive: %+q", format, err)
+		}
+
+		if format != "zip" && (run.UID != 1000 || run.GID != 1000 || run.Owner != "app" || run.Group != "staff") {
+			tests.Info("File: %+v", run.Attrs)
+			tests.Failed("Should have successfully kept file owner in %s archive", format)
+		}
+
+		bin, err := memFS.GetDir("bin")
+		if err != nil || bin.DirMode() != fs.ModeDir|0700 || !bin.ModTime.Equal(modTime) {
+			tests.Info("Dir: %+v", bin.Attrs)
+			tests.Failed("Should have successfully kept directory attributes in %s archive: %+q", format, err)
+		}
+
+		if _, err := memFS.GetDir("empty"); err != nil {
+			tests.Failed("Should have successfully kept empty directory in %s archive: %+q", format, err)
+		}
+		tests.Passed("Should have successfully kept attributes in %s archive", format)
+
+		if target, err := memFS.ReadLink("bin/start.sh"); err != nil || target != "run.sh" {
+			tests.Failed("Should have successfully kept link in %s archive: %+q", format, err)
+		}
+
+		if data, _ := fs.ReadFile(memFS, "bin/start.sh"); string(data) != "#!/bin/sh" {
+			tests.Failed("Should have successfully followed link in %s archive", format)
+		}
+		tests.Passed("Should have successfully kept links in %s archive", format)
+	}
+
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+	writer.WriteHeader(&tar.Header{Name: "app/passwd", Linkname: "../../etc/passwd", Typeflag: tar.TypeSymlink})
+	writer.Close()
+
+	if _, err := filesystem.FromTar(&archive, filesystem.DefaultReadLimits); err == nil {
+		tests.Failed("Should have failed to read link leaving the archive")
+	}
+	tests.Passed("Should have failed to read link leaving the archive")
+}
+
+func TestArchiveLinkChains(t *testing.T) {
+	orders := map[string][][2]string{
+		"up first":  {{"x/up", ".."}, {"x/esc", "up/.."}},
+		"esc first": {{"x/esc", "up/.."}, {"x/up", ".."}},
+	}
+
+	for order, links := range orders {
+		var archive bytes.Buffer
+		writer := tar.NewWriter(&archive)
+		for _, link := range links {
+			writer.WriteHeader(&tar.Header{Name: link[0], Linkname: link[1], Typeflag: tar.TypeSymlink, Mode: 0777})
+		}
+		writer.Close()
+
+		if _, err := filesystem.FromTar(&archive, filesystem.DefaultReadLimits); err == nil {
+			tests.Failed("Should have failed to read chain of links leaving the archive with %s", order)
+		}
+	}
+	tests.Passed("Should have failed to read chains of links leaving the archive")
+
+	var archive bytes.Buffer
+	writer := tar.NewWriter(&archive)
+	writer.WriteHeader(&tar.Header{Name: "x/up", Linkname: "..", Typeflag: tar.TypeSymlink, Mode: 0777})
+	writer.WriteHeader(&tar.Header{Name: "x/self", Linkname: "up/x", Typeflag: tar.TypeSymlink, Mode: 0777})
+	writer.Close()
+
+	if _, err := filesystem.FromTar(&archive, filesystem.DefaultReadLimits); err != nil {
+		tests.Failed("Should have successfully read chain of links within the archive: %+q", err)
+	}
+	tests.Passed("Should have successfully read chain of links within the archive")
+}
@@ -0,0 +1,97 @@
+package filesystem
+
+import (
+	"io/fs"
+	"sort"
+	"time"
+)
+
+// Reproducible returns a copy of the filesystem whose archives are byte-identical whenever it holds
+// the same directories, files and Meta, as needed for content addressed caching. Entries are sorted
+// by name, owners are cleared, and modes are normalized to DirMode for directories, FileMode for files
+// and 0755 for files executable by anyone. The Meta is written with its keys sorted, as encoding/json
+// does for maps, and gzip headers carry no name or time.
+//
+// If modTime is not zero, all entries are given it. Otherwise entries keep their own modification time,
+// with those without one given the latest time found in the filesystem, or the Unix epoch if none has
+// one. Times are kept in UTC to the second.
+func Reproducible(fsm MemoryFileSystem, modTime time.Time) MemoryFileSystem {
+	fixed := !modTime.IsZero()
+	if !fixed {
+		modTime = latestModTime(fsm.Dir, time.Unix(0, 0))
+	}
+
+	var reproduced MemoryFileSystem
+	reproduced.Meta = fsm.Meta
+	reproduced.Dir = reproducibleDir(fsm.Dir, modTime.UTC().Truncate(time.Second), fixed)
+
+	return reproduced
+}
+
+// reproducibleDir returns a sorted copy of the directory with normalized attributes.
+func reproducibleDir(dir DirWriter, modTime time.Time, fixed bool) DirWriter {
+	reproduced := Dir(dir.Name)
+	reproduced.Attrs = reproducibleAttrs(dir.Attrs, DirMode, modTime, fixed)
+
+	for _, file := range dir.ChildFiles {
+		mode := FileMode
+		switch {
+		case file.Link != "":
+			mode = fs.ModeSymlink | 0777
+		case file.FileMode()&0111 != 0:
+			mode = 0755
+		}
+
+		file.Attrs = reproducibleAttrs(file.Attrs, mode, modTime, fixed)
+		reproduced.ChildFiles = append(reproduced.ChildFiles, file)
+	}
+
+	for _, child := range dir.ChildDirs {
+		reproduced.ChildDirs = append(reproduced.ChildDirs, reproducibleDir(child, modTime, fixed))
+	}
+
+	// Stable sorts keep the order of entries sharing a name, which shadow one another.
+	sort.SliceStable(reproduced.ChildFiles, func(i, j int) bool {
+		return reproduced.ChildFiles[i].Name < reproduced.ChildFiles[j].Name
+	})
+
+	sort.SliceStable(reproduced.ChildDirs, func(i, j int) bool {
+		return reproduced.ChildDirs[i].Name < reproduced.ChildDirs[j].Name
+	})
+
+	return reproduced
+}
+
+// reproducibleAttrs returns attributes with the giving mode, no owner and the modification time of
+// attrs, unless fixed is true or it has none, in which case modTime is used.
+func reproducibleAttrs(attrs Attrs, mode fs.FileMode, modTime time.Time, fixed bool) Attrs {
+	if !fixed && !attrs.ModTime.IsZero() {
+		modTime = attrs.ModTime.UTC().Truncate(time.Second)
+	}
+
+	return Attrs{Mode: mode, ModTime: modTime}
+}
+
+// latestModTime returns the latest modification time of the directory and its entries, or
+// fallback if none has one.
+func latestModTime(dir DirWriter, fallback time.Time) time.Time {
+	latest := dir.ModTime
+
+	for _, file := range dir.ChildFiles {
+		if file.ModTime.After(latest) {
+			latest = file.ModTime
+		}
+	}
+
+	for _, child := range dir.ChildDirs {
+		if childLatest := latestModTime(child, time.Time{}); childLatest.After(latest) {
+			latest = childLatest
+		}
+	}
+
+	if latest.IsZero() {
+		return fallback
+	}
+
+	return latest
+}
@@ -0,0 +1,80 @@
+package filesystem_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/gen/filesystem"
+)
+
+func TestReproducible(t *testing.T) {
+	released := time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	fixture := func(reversed bool, uid int) filesystem.MemoryFileSystem {
+		entries := []interface{}{
+			filesystem.Dir(
+				"app",
+				filesystem.File("readme.md", filesystem.Content("# App v1.0")).WithOwner(uid, uid, "app", "app"),
+				filesystem.Executable("run.sh", filesystem.Content("#!/bin/sh")).WithModTime(released),
+				filesystem.Symlink("start.sh", "run.sh"),
+			).WithMode(0700),
+			filesystem.File("dockerfile", filesystem.Content(`FROM alpine:latest`)).WithModTime(time.Now()).WithMode(0600),
+			filesystem.Meta("version", "1.0"),
+			filesystem.Meta("description", "FileSystem for the faas docker system"),
+		}
+
+		if reversed {
+			for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+				entries[i], entries[j] = entries[j], entries[i]
+			}
+		}
+
+		return filesystem.FileSystem(entries...)
+	}
+
+	writers := map[string]func(filesystem.MemoryFileSystem) filesystem.Filesystem{
+		"zip":    func(fsm filesystem.MemoryFileSystem) filesystem.Filesystem { return filesystem.ZipFS(fsm) },
+		"tar":    func(fsm filesystem.MemoryFileSystem) filesystem.Filesystem { return filesystem.TarFS(fsm) },
+		"tar.gz": func(fsm filesystem.MemoryFileSystem) filesystem.Filesystem { return filesystem.GzipTarFS(fsm) },
+		"json":   func(fsm filesystem.MemoryFileSystem) filesystem.Filesystem { return filesystem.JSONFS(fsm, true) },
+	}
+
+	for format, writer := range writers {
+		var first, second bytes.Buffer
+
+		if _, err := writer(filesystem.Reproducible(fixture(false, 1000), released)).WriteTo(&first); err != nil {
+			tests.Failed("Should have successfully written %s archive: %+q", format, err)
+		}
+
+		if _, err := writer(filesystem.Reproducible(fixture(true, 501), released)).WriteTo(&second); err != nil {
+			tests.Failed("Should have successfully written %s archive: %+q", format, err)
+		}
+
+		if !bytes.Equal(first.Bytes(), second.Bytes()) {
+			tests.Failed("Should have successfully written byte-identical %s archives", format)
+		}
+		tests.Passed("Should have successfully written byte-identical %s archives", format)
+	}
+
+	memFS := filesystem.Reproducible(fixture(true, 1000), time.Time{})
+
+	dockerfile := memFS.Dir.ChildFiles[0]
+	if dockerfile.Name != "dockerfile" || !dockerfile.ModTime.After(released) || dockerfile.FileMode() != filesystem.FileMode {
+		tests.Info("Attrs: %+v", dockerfile.Attrs)
+		tests.Failed("Should have successfully kept source time and normalized attributes")
+	}
+
+	readme, _ := memFS.GetFile("app/readme.md")
+	if !readme.ModTime.Equal(dockerfile.ModTime) || readme.UID != 0 || readme.Owner != "" || readme.FileMode() != filesystem.FileMode {
+		tests.Info("Attrs: %+v", readme.Attrs)
+		tests.Failed("Should have successfully given latest source time and normalized attributes")
+	}
+
+	run, _ := memFS.GetFile("app/run.sh")
+	if run.FileMode() != 0755 || !run.ModTime.Equal(released) {
+		tests.Failed("Should have successfully kept executable files executable")
+	}
+	tests.Passed("Should have successfully normalized attributes")
+}
@@ -0,0 +1,290 @@
+package filesystem
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/influx6/moz/gen"
+)
+
+// Processor defines a function which post-processes the rendered content of a file of a Scaffold,
+// given its rendered path, returning the content to keep.
+type Processor func(filePath string, content []byte) ([]byte, error)
+
+// Matching returns a Processor running the processor only for files whose rendered path matches
+// the pattern, where patterns without a slash match the name of a file at any depth and others match
+// its path from the root, as by Match.
+func Matching(pattern string, processor Processor) Processor {
+	return func(filePath string, content []byte) ([]byte, error) {
+		if !matchPath(pattern, filePath) {
+			return content, nil
+		}
+
+		return processor(filePath, content)
+	}
+}
+
+// GoFormat returns a Processor formatting the content of Go files, being those ending in .go, with
+// go/format, failing for files which are not valid Go source.
+func GoFormat() Processor {
+	return Matching("*.go", func(filePath string, content []byte) ([]byte, error) {
+		return format.Source(content)
+	})
+}
+
+// Scaffold renders the MemoryFileSystem as a set of templates against a data model, as when creating
+// a new project from a skeleton. The names of the root, directories and files, the targets of links and
+// the contents of text files are text/templates, parsed with the default functions of gen along with
+// Funcs, and rendered with missing map keys reported as errors.
+//
+// Names may render into slash separated paths (e.g {{.Service}}/cmd/main.go), which are laid out
+// within the directory they are declared in. Directories and files whose name renders empty, usually
+// through {{if}}, are skipped along with their contents, which makes them conditional.
+//
+// Contents which are binary, or which belong to files matching any of the Verbatim patterns, are kept
+// as is. Rendered contents are then passed through the Processors in order. Verbatim patterns follow
+// Matching, supporting "**" as Match does, and are matched against rendered paths relative to the root,
+// which are also given to Processors. Link targets must render into relative paths which stay within
+// the filesystem. Attributes and the Meta of the filesystem are kept.
+type Scaffold struct {
+	FS         MemoryFileSystem
+	Funcs      template.FuncMap
+	Verbatim   []string
+	Processors []Processor
+}
+
+// ScaffoldFS returns a new instance of the Scaffold.
+func ScaffoldFS(fs MemoryFileSystem, processors ...Processor) Scaffold {
+	return Scaffold{FS: fs, Processors: processors}
+}
+
+// Render returns a MemoryFileSystem holding the directories and files of the Scaffold rendered
+// against the giving data.
+func (sc Scaffold) Render(data interface{}) (MemoryFileSystem, error) {
+	rootName, err := sc.render("root name", sc.FS.Dir.Name, data)
+	if err != nil {
+		return MemoryFileSystem{}, err
+	}
+
+	var rendered MemoryFileSystem
+	rendered.Meta = sc.FS.Meta
+	rendered.Dir = Dir(strings.TrimSpace(rootName))
+	rendered.Dir.Attrs = sc.FS.Dir.Attrs
+
+	if err := sc.renderDir(&rendered.Dir, "", sc.FS.Dir, data); err != nil {
+		return MemoryFileSystem{}, err
+	}
+
+	// Links are checked once all are rendered, as a link may lead through others.
+	if err := rendered.Files(func(filePath string, file FileWriter) error {
+		if file.Link != "" && !rendered.linkInside(filePath, file.Link) {
+			return fmt.Errorf("Link %q renders into %q outside of the filesystem", filePath, file.Link)
+		}
+		return nil
+	}); err != nil {
+		return MemoryFileSystem{}, err
+	}
+
+	return rendered, nil
+}
+
+// renderDir renders the files and directories of the source directory into the rendered root, at
+// the rendered path dirPath.
+func (sc Scaffold) renderDir(root *DirWriter, dirPath string, source DirWriter, data interface{}) error {
+	for _, file := range source.ChildFiles {
+		filePath, skip, err := sc.renderPath(dirPath, file.Name, data)
+		if err != nil {
+			return err
+		}
+
+		if skip {
+			continue
+		}
+
+		rendered, err := sc.renderFile(filePath, file, data)
+		if err != nil {
+			return err
+		}
+
+		if err := root.add(strings.Split(path.Dir(filePath), "/"), rendered); err != nil {
+			return err
+		}
+	}
+
+	for _, child := range source.ChildDirs {
+		childPath, skip, err := sc.renderPath(dirPath, child.Name, data)
+		if err != nil {
+			return err
+		}
+
+		if skip {
+			continue
+		}
+
+		dir, err := root.ensureDir(strings.Split(childPath, "/"))
+		if err != nil {
+			return err
+		}
+
+		dir.Attrs = child.Attrs
+
+		if err := sc.renderDir(root, childPath, child, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderPath returns the path of the entry of the giving name within dirPath, with its name rendered,
+// and true/false if the name renders empty.
+func (sc Scaffold) renderPath(dirPath string, name string, data interface{}) (string, bool, error) {
+	rendered, err := sc.render(path.Join(dirPath, name), name, data)
+	if err != nil {
+		return "", false, err
+	}
+
+	rendered = strings.TrimSpace(rendered)
+	if rendered == "" {
+		return "", true, nil
+	}
+
+	cleaned := path.Clean(rendered)
+	if path.IsAbs(rendered) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", false, fmt.Errorf("Name %q renders into %q outside of its directory", name, rendered)
+	}
+
+	return path.Join(dirPath, cleaned), false, nil
+}
+
+// renderFile returns the file at the rendered path, with its link target or content rendered and
+// processed.
+func (sc Scaffold) renderFile(filePath string, file FileWriter, data interface{}) (FileWriter, error) {
+	file.Name = path.Base(filePath)
+
+	if file.Link != "" {
+		target, err := sc.render(filePath, file.Link, data)
+		if err != nil {
+			return FileWriter{}, err
+		}
+
+		file.Link = strings.TrimSpace(target)
+		if file.Link == "" || path.IsAbs(file.Link) {
+			return FileWriter{}, fmt.Errorf("Link %q renders into %q which is not a relative path", filePath, file.Link)
+		}
+
+		return file, nil
+	}
+
+	content, err := contentBytes(file)
+	if err != nil {
+		return FileWriter{}, fmt.Errorf("IOError: Unable to read content of %q: %+q", filePath, err)
+	}
+
+	source := pathFile{file: file, data: content}
+
+	if source.text() && !matchAnyPath(sc.Verbatim, filePath) {
+		rendered, err := sc.render(filePath, string(content), data)
+		if err != nil {
+			return FileWriter{}, err
+		}
+
+		content = []byte(rendered)
+	}
+
+	for _, processor := range sc.Processors {
+		if content, err = processor(filePath, content); err != nil {
+			return FileWriter{}, fmt.Errorf("Unable to process %q: %+q", filePath, err)
+		}
+	}
+
+	file.Content = ContentByte(content)
+	return file, nil
+}
+
+// render returns the template text rendered against data, naming the template after the path it
+// belongs to for errors.
+func (sc Scaffold) render(name string, text string, data interface{}) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tml, err := gen.ToTemplate(name, text, sc.Funcs)
+	if err != nil {
+		return "", err
+	}
+
+	var bu bytes.Buffer
+	if err := tml.Option("missingkey=error").Execute(&bu, data); err != nil {
+		return "", err
+	}
+
+	return bu.String(), nil
+}
+
+// matchAnyPath returns true/false if the slash separated path matches any of the patterns.
+func matchAnyPath(patterns []string, filePath string) bool {
+	for _, pattern := range patterns {
+		if matchPath(pattern, filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath returns true/false if the slash separated path matches the pattern, where patterns
+// without a slash match its name.
+func matchPath(pattern string, filePath string) bool {
+	if !strings.Contains(pattern, "/") {
+		filePath = path.Base(filePath)
+	}
+
+	return Match(strings.TrimPrefix(pattern, "/"), filePath)
+}
+
+// Match returns true/false if the slash separated path matches the glob pattern, where each element
+// of the pattern is matched as by path.Match and a "**" element matches any number of elements,
+// including none (e.g "assets/**/*.css" matches "assets/site.css" and "assets/css/site.css").
+// Malformed patterns match nothing.
+func Match(pattern string, filePath string) bool {
+	return matchLevels(strings.Split(pattern, "/"), strings.Split(filePath, "/"))
+}
+
+func matchLevels(patterns []string, levels []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			// Collapse repeated "**" and try every number of elements they may match.
+			for len(patterns) > 0 && patterns[0] == "**" {
+				patterns = patterns[1:]
+			}
+
+			if len(patterns) == 0 {
+				return true
+			}
+
+			for index := range levels {
+				if matchLevels(patterns, levels[index:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(levels) == 0 {
+			return false
+		}
+
+		if matched, err := path.Match(patterns[0], levels[0]); err != nil || !matched {
+			return false
+		}
+
+		patterns, levels = patterns[1:], levels[1:]
+	}
+
+	return len(levels) == 0
+}
@@ -0,0 +1,116 @@
+package filesystem_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/gen/filesystem"
+)
+
+func TestScaffold(t *testing.T) {
+	skeleton := filesystem.FileSystem(
+		filesystem.Meta("kind", "service"),
+		filesystem.File("readme.md", filesystem.Content("# {{.Service}}\n\n{{trimSuffix .Description \".\"}}\n")),
+		filesystem.File("{{if .Docker}}Dockerfile{{end}}", filesystem.Content("FROM alpine:latest\nCMD [\"/{{.Service}}\"]\n")),
+		filesystem.File("logo.png", filesystem.ContentByte([]byte{0x89, 'P', 'N', 'G', 0, '{', '{'})),
+		filesystem.File("page.tml", filesystem.Content("<h1>{{.Title}}</h1>")),
+		filesystem.Dir("assets", filesystem.Dir("css", filesystem.File("site.css", filesystem.Content("a { b: {{c}} }")))),
+		filesystem.Executable("{{.Service}}/cmd/main.go", filesystem.Content("package main\nimport \"fmt\"\nfunc main() {\nfmt.Println(\"{{.Service}}\")\n}\n")),
+		filesystem.Dir(
+			"{{if .Docs}}docs{{end}}",
+			filesystem.File("index.md", filesystem.Content("# Docs")),
+		),
+		filesystem.Dir(
+			"{{.Service}}",
+			filesystem.Symlink("run", "cmd/{{.Service}}"),
+		).WithMode(0700),
+	)
+
+	scaffold := filesystem.ScaffoldFS(skeleton, filesystem.GoFormat())
+	scaffold.Verbatim = []string{"*.tml", "assets/**/*.css"}
+
+	rendered, err := scaffold.Render(map[string]interface{}{
+		"Service":     "billing",
+		"Description": "Handles invoices.",
+		"Docker":      true,
+		"Docs":        false,
+	})
+	if err != nil {
+		tests.Failed("Should have successfully rendered scaffold: %+q", err)
+	}
+	tests.Passed("Should have successfully rendered scaffold")
+
+	expected := map[string]string{
+		"readme.md":           "# billing\n\nHandles invoices\n",
+		"Dockerfile":          "FROM alpine:latest\nCMD [\"/billing\"]\n",
+		"logo.png":            "\x89PNG\x00{{",
+		"page.tml":            "<h1>{{.Title}}</h1>",
+		"assets/css/site.css": "a { b: {{c}} }",
+		"billing/cmd/main.go": "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"billing\")\n}\n",
+	}
+
+	for filePath, content := range expected {
+		data, err := rendered.ReadFile(filePath)
+		if err != nil {
+			tests.Failed("Should have successfully found rendered file %q: %+q", filePath, err)
+		}
+
+		if string(data) != content {
+			tests.Info("Content: %q", data)
+			tests.Failed("Should have successfully rendered content of %q", filePath)
+		}
+	}
+	tests.Passed("Should have successfully rendered paths and contents")
+
+	if _, err := rendered.Stat("docs"); err == nil {
+		tests.Failed("Should have successfully skipped conditional directory")
+	}
+	tests.Passed("Should have successfully skipped conditional directory")
+
+	if info, err := rendered.Stat("billing/cmd/main.go"); err != nil || info.Mode().Perm() != 0755 {
+		tests.Failed("Should have successfully kept file mode")
+	}
+
+	if info, err := rendered.Stat("billing"); err != nil || info.Mode().Perm() != 0700 {
+		tests.Failed("Should have successfully kept directory mode")
+	}
+
+	if target, err := rendered.ReadLink("billing/run"); err != nil || target != "cmd/billing" {
+		tests.Failed("Should have successfully rendered link target: %+q", err)
+	}
+	tests.Passed("Should have successfully kept attributes and rendered links")
+
+	if rendered.Meta["kind"] != "service" {
+		tests.Failed("Should have successfully kept Meta")
+	}
+	tests.Passed("Should have successfully kept Meta")
+}
+
+func TestScaffoldErrors(t *testing.T) {
+	cases := map[string]filesystem.MemoryFileSystem{
+		"missing key":    filesystem.FileSystem(filesystem.File("{{.Name}}.go", filesystem.Content("package main"))),
+		"escaping name":  filesystem.FileSystem(filesystem.File("{{.Service}}/../../main.go", filesystem.Content("package main"))),
+		"invalid go":     filesystem.FileSystem(filesystem.File("main.go", filesystem.Content("package {{.Service}}\nfunc {"))),
+		"malformed text": filesystem.FileSystem(filesystem.File("main.go", filesystem.Content("package {{.Service"))),
+		"absolute link":  filesystem.FileSystem(filesystem.Symlink("main.go", "/{{.Service}}/main.go")),
+		"escaping link":  filesystem.FileSystem(filesystem.Symlink("main.go", "../../{{.Service}}/main.go")),
+		"chained link": filesystem.FileSystem(
+			filesystem.Dir("x", filesystem.Symlink("up", "{{if .Service}}..{{end}}")),
+			filesystem.Dir("x", filesystem.Symlink("main.go", "up/..")),
+		),
+	}
+
+	for name, fsm := range cases {
+		_, err := filesystem.ScaffoldFS(fsm, filesystem.GoFormat()).Render(map[string]interface{}{"Service": "billing"})
+		if err == nil {
+			tests.Failed("Should have successfully failed to render scaffold with %s", name)
+		}
+
+		if !strings.Contains(err.Error(), ".go") {
+			tests.Info("Error: %+q", err)
+			tests.Failed("Should have successfully named the file failing with %s", name)
+		}
+	}
+	tests.Passed("Should have successfully failed to render invalid scaffolds")
+}
@@ -0,0 +1,180 @@
+package filesystem
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// spoolLimit defines the size of content of unknown length held in memory when its length is
+// needed ahead of writing it, beyond which it is spooled to a temporary file.
+const spoolLimit = 1 << 20
+
+// pipeReader returns a reader streaming the output of the io.WriterTo, which is written from a
+// goroutine through an io.Pipe as the reader is read. Errors of the writer are returned by the
+// reader, and closing the reader stops the writer.
+func pipeReader(wt io.WriterTo) io.Reader {
+	pr, pw := io.Pipe()
+
+	go func() {
+		_, err := wt.WriteTo(pw)
+		pw.CloseWithError(err)
+	}()
+
+	return pr
+}
+
+// contentLength returns the length of the content of the file if it is known without reading it,
+// from contents with a Len method, such as those returned by Content, or a Size method returning a
+// length which is not negative.
+func contentLength(file FileWriter) (int64, bool) {
+	switch content := file.Content.(type) {
+	case nil:
+		return 0, true
+	case interface{ Len() int }:
+		return int64(content.Len()), true
+	case interface{ Size() int64 }:
+		size := content.Size()
+		return size, size >= 0
+	}
+
+	return 0, false
+}
+
+// writeContent writes the content of the file to w, without consuming contents which expose their
+// bytes, so that they can be written again.
+func writeContent(w io.Writer, file FileWriter) (int64, error) {
+	switch content := file.Content.(type) {
+	case nil:
+		return 0, nil
+	case interface{ Bytes() []byte }:
+		n, err := w.Write(content.Bytes())
+		return int64(n), err
+	default:
+		return content.WriteTo(w)
+	}
+}
+
+// spool reads the content of the file once into a spoolWriter, for content whose length is not
+// known ahead. The spool must be cleaned up once read.
+func spool(file FileWriter) (*spoolWriter, error) {
+	spooled := &spoolWriter{}
+	if _, err := file.Content.WriteTo(spooled); err != nil && err != io.EOF {
+		spooled.cleanup()
+		return nil, err
+	}
+
+	return spooled, nil
+}
+
+// spoolWriter implements io.Writer, holding written data in memory up to spoolLimit and moving it
+// to a temporary file beyond it.
+type spoolWriter struct {
+	size int64
+	mem  bytes.Buffer
+	file *os.File
+}
+
+func (sw *spoolWriter) Write(p []byte) (int, error) {
+	if sw.file == nil && sw.mem.Len()+len(p) > spoolLimit {
+		file, err := os.CreateTemp("", "moz-filesystem-spool")
+		if err != nil {
+			return 0, err
+		}
+
+		sw.file = file
+
+		if _, err := sw.mem.WriteTo(file); err != nil {
+			return 0, err
+		}
+	}
+
+	var n int
+	var err error
+
+	if sw.file != nil {
+		n, err = sw.file.Write(p)
+	} else {
+		n, err = sw.mem.Write(p)
+	}
+
+	sw.size += int64(n)
+	return n, err
+}
+
+// reader returns a reader of the data written to the spool.
+func (sw *spoolWriter) reader() (io.Reader, error) {
+	if sw.file == nil {
+		return &sw.mem, nil
+	}
+
+	if _, err := sw.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return sw.file, nil
+}
+
+// cleanup removes the temporary file of the spool, if any.
+func (sw *spoolWriter) cleanup() {
+	if sw.file != nil {
+		sw.file.Close()
+		os.Remove(sw.file.Name())
+	}
+}
+
+//======================================================================================
+
+// jsonStringWriter implements io.Writer, writing the data written to it into w as the content of a
+// JSON string, escaped as encoding/json escapes strings. Runes split across writes are held until
+// complete, and Close writes what remains.
+type jsonStringWriter struct {
+	w       io.Writer
+	pending []byte
+}
+
+func (jw *jsonStringWriter) Write(p []byte) (int, error) {
+	data := append(jw.pending, p...)
+	jw.pending = nil
+
+	// Hold back the last rune if it is not yet complete.
+	for index := len(data) - 1; index >= 0 && index >= len(data)-utf8.UTFMax; index-- {
+		if utf8.RuneStart(data[index]) {
+			if !utf8.FullRune(data[index:]) {
+				jw.pending = append([]byte(nil), data[index:]...)
+				data = data[:index]
+			}
+			break
+		}
+	}
+
+	if err := jw.write(data); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close writes the bytes held back from earlier writes.
+func (jw *jsonStringWriter) Close() error {
+	pending := jw.pending
+	jw.pending = nil
+
+	return jw.write(pending)
+}
+
+func (jw *jsonStringWriter) write(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	quoted, err := json.Marshal(string(data))
+	if err != nil {
+		return err
+	}
+
+	_, err = jw.w.Write(quoted[1 : len(quoted)-1])
+	return err
+}
@@ -0,0 +1,118 @@
+package filesystem_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/influx6/faux/tests"
+	"github.com/influx6/moz/gen"
+	"github.com/influx6/moz/gen/filesystem"
+)
+
+// sizedContent implements io.WriterTo with a known size, counting how often it is read.
+type sizedContent struct {
+	data  string
+	reads int
+}
+
+func (sc *sizedContent) Size() int64 {
+	return int64(len(sc.data))
+}
+
+func (sc *sizedContent) WriteTo(w io.Writer) (int64, error) {
+	sc.reads++
+	n, err := io.WriteString(w, sc.data)
+	return int64(n), err
+}
+
+// trickleContent implements io.WriterTo, writing its data a byte at a time.
+type trickleContent struct {
+	data string
+}
+
+func (tc trickleContent) WriteTo(w io.Writer) (int64, error) {
+	for index := 0; index < len(tc.data); index++ {
+		if _, err := w.Write([]byte{tc.data[index]}); err != nil {
+			return int64(index), err
+		}
+	}
+	return int64(len(tc.data)), nil
+}
+
+// failingContent implements io.WriterTo, failing once written to.
+type failingContent struct{}
+
+func (failingContent) WriteTo(w io.Writer) (int64, error) {
+	return 0, errors.New("disk unavailable")
+}
+
+func TestStreamingArchives(t *testing.T) {
+	large := strings.Repeat("moz", 1<<20)
+	sized := &sizedContent{data: "package main"}
+
+	memFS := filesystem.FileSystem(
+		filesystem.File("main.go", sized),
+		filesystem.File("assets.bin", &gen.FromReader{R: strings.NewReader(large)}),
+	)
+
+	reader, err := filesystem.GzipTarFS(memFS).ToReader()
+	if err != nil {
+		tests.Failed("Should have successfully created streaming reader: %+q", err)
+	}
+
+	readFS, err := filesystem.FromGzipTar(reader, filesystem.DefaultReadLimits)
+	if err != nil {
+		tests.Failed("Should have successfully read streamed archive: %+q", err)
+	}
+
+	if data, _ := fs.ReadFile(readFS, "assets.bin"); string(data) != large {
+		tests.Failed("Should have successfully streamed content of unknown length")
+	}
+
+	if data, _ := fs.ReadFile(readFS, "main.go"); string(data) != "package main" || sized.reads != 1 {
+		tests.Info("Reads: %d", sized.reads)
+		tests.Failed("Should have successfully streamed content of known length once")
+	}
+	tests.Passed("Should have successfully streamed archive")
+
+	reader, err = filesystem.TarFS(filesystem.FileSystem(filesystem.File("main.go", failingContent{}))).ToReader()
+	if err != nil {
+		tests.Failed("Should have successfully created streaming reader: %+q", err)
+	}
+
+	if _, err := io.ReadAll(reader); err == nil || !strings.Contains(err.Error(), "disk unavailable") {
+		tests.Failed("Should have successfully returned error of content from reader: %+q", err)
+	}
+	tests.Passed("Should have successfully returned error of content from reader")
+}
+
+func TestStreamingJSON(t *testing.T) {
+	content := "héllo ☃ <b>\"quoted\"</b>\n\t\x01 \xff end"
+
+	reader, err := filesystem.JSONFS(filesystem.FileSystem(filesystem.File("notes.txt", trickleContent{data: content})), false).ToReader()
+	if err != nil {
+		tests.Failed("Should have successfully created streaming reader: %+q", err)
+	}
+
+	var streamed bytes.Buffer
+	if _, err := streamed.ReadFrom(reader); err != nil {
+		tests.Failed("Should have successfully streamed json: %+q", err)
+	}
+
+	expected, _ := json.Marshal(map[string]string{
+		".meta":     "{}",
+		"notes.txt": content,
+	})
+
+	if streamed.String() != string(expected)+"\n" {
+		tests.Info("Expected: %q", expected)
+		tests.Info("Received: %q", streamed.String())
+		tests.Failed("Should have successfully escaped content as encoding/json does")
+	}
+	tests.Passed("Should have successfully escaped content as encoding/json does")
+}
@@ -2,7 +2,6 @@ package gen
 
 import (
 	"bytes"
-	"fmt"
 	"io"
 	"strconv"
 	"strings"
@@ -39,10 +38,26 @@ type WriteDirective struct {
 	Dir          string      `ast:"dir,optional"`      // Relative dir path written into it if not existing.
 	FileName     string      `ast:"filename,optional"` // alternative fileName to use for new file.
 	DontOverride bool        `ast:"dont_override,optional"`
+	Rewrites     []RewriteDirective
 	Before       func() error
 	After        func() error
 }
 
+// RewriteDirective defines a struct which contains giving directives for an in-place edit
+// of an existing source file. The edit replaces the region of the file starting at the byte
+// offset From and spanning Length bytes with the content of Writer, where a zero Length
+// inserts the content at From without removing anything.
+//
+// If Original is provided, the region must still hold its content when the edit is applied,
+// which guards against editing a file that changed after it was parsed.
+type RewriteDirective struct {
+	FilePath string
+	From     int
+	Length   int
+	Original string
+	Writer   io.WriterTo
+}
+
 //======================================================================================================================
 
 // WriterToMap defines a int64erface which maps giving declaration values
@@ -1332,6 +1347,8 @@ type ByteBlockDeclr struct {
 
 // WriteTo writes the giving representation into the provided writer.
 func (b ByteBlockDeclr) WriteTo(w io.Writer) (int64, error) {
+	iw, indented := IndentWriterFor(w)
+
 	w = NewNoBOM(w)
 	wc := NewWriteCounter(w)
 
@@ -1341,10 +1358,18 @@ func (b ByteBlockDeclr) WriteTo(w io.Writer) (int64, error) {
 		}
 	}
 
+	if indented {
+		iw.Push()
+	}
+
 	if _, err := b.Block.WriteTo(wc); err != nil && err != io.EOF {
 		return 0, err
 	}
 
+	if indented {
+		iw.Pop()
+	}
+
 	if b.BlockEnd != nil {
 		if _, err := wc.Write(b.BlockEnd); err != nil {
 			return 0, err
@@ -1366,6 +1391,8 @@ type BlockDeclr struct {
 
 // WriteTo writes the giving representation into the provided writer.
 func (b BlockDeclr) WriteTo(w io.Writer) (int64, error) {
+	iw, indented := IndentWriterFor(w)
+
 	w = NewNoBOM(w)
 
 	wc := NewWriteCounter(w)
@@ -1374,10 +1401,18 @@ func (b BlockDeclr) WriteTo(w io.Writer) (int64, error) {
 		return 0, err
 	}
 
+	if indented {
+		iw.Push()
+	}
+
 	if _, err := b.Block.WriteTo(wc); err != nil && err != io.EOF {
 		return 0, err
 	}
 
+	if indented {
+		iw.Pop()
+	}
+
 	if _, err := wc.Write([]byte{byte(b.RuneEnd)}); err != nil {
 		return 0, err
 	}
@@ -1387,85 +1422,84 @@ func (b BlockDeclr) WriteTo(w io.Writer) (int64, error) {
 
 //======================================================================================================================
 
-// JSONBlock defines a block area which is encycled by braces.
-// {
-// 	...
-// }
-type JSONBlock struct {
-	Items map[string]io.WriterTo
+// IndentedDeclr defines a declaration which writes its block through an IndentWriter with the
+// giving unit, making all block-style declarations within it indentation aware. If the writer
+// is already an IndentWriter, it is used as is.
+type IndentedDeclr struct {
+	Unit  string      `json:"unit"`
+	Block io.WriterTo `json:"block"`
 }
 
-// WriteTo writes to the provided writer the variable declaration.
-func (v JSONBlock) WriteTo(w io.Writer) (int64, error) {
-	wc := NewWriteCounter(NewNoBOM(w))
+// WriteTo writes the giving representation into the provided writer.
+func (b IndentedDeclr) WriteTo(w io.Writer) (int64, error) {
+	if _, ok := IndentWriterFor(w); !ok {
+		w = NewIndentWriter(w, b.Unit)
+	}
 
-	tml, err := ToTemplate("jsonBlockDeclr", templates.Must("jsonblock.tml"), nil)
-	if err != nil {
+	wc := NewWriteCounter(w)
+
+	if _, err := b.Block.WriteTo(wc); IsNotDrainError(err) {
 		return 0, err
 	}
 
-	var content []string
-
-	var bu bytes.Buffer
-	for name, item := range v.Items {
-		bu.Reset()
+	return wc.Written(), nil
+}
 
-		if _, err := item.WriteTo(&bu); IsNotDrainError(err) {
-			return 0, err
-		}
+// IndentDeclr defines a declaration which indents its block one level deeper than the
+// content around it. If the writer is not an IndentWriter, the block is indented by a tab.
+type IndentDeclr struct {
+	Block io.WriterTo `json:"block"`
+}
 
-		content = append(content, fmt.Sprintf("%q:	%s", name, strings.Join(strings.Split(bu.String(), "\n"), "\n\t")))
+// WriteTo writes the giving representation into the provided writer.
+func (b IndentDeclr) WriteTo(w io.Writer) (int64, error) {
+	iw, ok := IndentWriterFor(w)
+	if !ok {
+		iw = NewIndentWriter(w, "")
+		w = iw
 	}
 
-	if err := tml.Execute(wc, content); err != nil {
+	wc := NewWriteCounter(w)
+
+	iw.Push()
+	defer iw.Pop()
+
+	if _, err := b.Block.WriteTo(wc); IsNotDrainError(err) {
 		return 0, err
 	}
 
 	return wc.Written(), nil
 }
 
-// JSONDeclr defines a block area contains other JSONBlocks.
+//======================================================================================================================
+
+// JSONBlock defines a JSON object whose keys are written in sorted order and escaped,
+// and whose values must each write a valid JSON fragment.
 // {
+// 	...
+// }
+type JSONBlock struct {
+	Items map[string]io.WriterTo
+}
+
+// WriteTo writes to the provided writer the JSON object.
+func (v JSONBlock) WriteTo(w io.Writer) (int64, error) {
+	return JSONDocumentDeclr{Value: v.Items, Indent: "\t"}.WriteTo(w)
+}
+
+// JSONDeclr defines a JSON array containing other JSON documents.
+// [
 // 	  {
 //		...
 // 	   }
-// }
+// ]
 type JSONDeclr struct {
 	Documents []io.WriterTo
 }
 
-// WriteTo writes to the provided writer the variable declaration.
+// WriteTo writes to the provided writer the JSON array.
 func (m JSONDeclr) WriteTo(w io.Writer) (int64, error) {
-	wc := NewWriteCounter(NewNoBOM(w))
-
-	tml, err := ToTemplate("jsonDeclr", templates.Must("json.tml"), nil)
-	if err != nil {
-		return 0, err
-	}
-
-	var contents []string
-
-	var bu bytes.Buffer
-
-	for _, doc := range m.Documents {
-		bu.Reset()
-
-		if _, err := doc.WriteTo(&bu); IsNotDrainError(err) {
-			return 0, err
-		}
-
-		contents = append(contents, bu.String())
-	}
-
-	if err := tml.Execute(wc, struct {
-		Documents []string
-	}{
-		Documents: contents,
-	}); err != nil {
-		return 0, err
-	}
-
-	return wc.Written(), nil
+	return JSONDocumentDeclr{Value: m.Documents, Indent: "\t"}.WriteTo(w)
 }
 
 //======================================================================================================================
@@ -1501,10 +1535,12 @@ func (c ConditionDeclr) WriteTo(w io.Writer) (int64, error) {
 //======================================================================================================================
 
 // FunctionDeclr defines a declaration which produces function about based on the giving
-// constructor and body.
+// constructor and body. Params, when set, are written as the parameters of the function
+// in place of the constructor.
 type FunctionDeclr struct {
 	Name        NameDeclr        `json:"name"`
 	Constructor ConstructorDeclr `json:"constructor"`
+	Params      []ParamDeclr     `json:"params"`
 	Returns     io.WriterTo      `json:"returns"`
 	Body        WritersTo        `json:"body"`
 }
@@ -1513,9 +1549,22 @@ type FunctionDeclr struct {
 func (f FunctionDeclr) WriteTo(w io.Writer) (int64, error) {
 	w = NewNoBOM(w)
 
-	var constr, returns, body bytes.Buffer
+	var constr, returns bytes.Buffer
 
-	if _, err := f.Constructor.WriteTo(&constr); IsNotDrainError(err) {
+	if f.Params != nil {
+		var params []string
+
+		for _, param := range f.Params {
+			content, err := writerToString(param)
+			if err != nil {
+				return 0, err
+			}
+
+			params = append(params, content)
+		}
+
+		constr.WriteString("(" + strings.Join(params, ", ") + ")")
+	} else if _, err := f.Constructor.WriteTo(&constr); IsNotDrainError(err) {
 		return 0, err
 	}
 
@@ -1523,7 +1572,8 @@ func (f FunctionDeclr) WriteTo(w io.Writer) (int64, error) {
 		return 0, err
 	}
 
-	if _, err := f.Body.WriteTo(&body); IsNotDrainError(err) {
+	body, err := renderNested(w, f.Body, 1)
+	if err != nil {
 		return 0, err
 	}
 
@@ -1535,7 +1585,7 @@ func (f FunctionDeclr) WriteTo(w io.Writer) (int64, error) {
 	}{
 		Name:        f.Name.String(),
 		Returns:     returns.String(),
-		Body:        body.String(),
+		Body:        body,
 		Constructor: constr.String(),
 	}
 
@@ -1693,15 +1743,13 @@ func (v StructDeclr) WriteTo(w io.Writer) (int64, error) {
 		return 0, err
 	}
 
-	var b bytes.Buffer
 	for _, item := range v.Fields {
-		b.Reset()
-
-		if _, err := item.WriteTo(&b); IsNotDrainError(err) {
+		field, err := renderNestedOr(w, item, legacyIndent)
+		if err != nil {
 			return 0, err
 		}
 
-		fields = append(fields, b.String())
+		fields = append(fields, field)
 	}
 
 	wc := NewWriteCounter(w)
@@ -1726,6 +1774,501 @@ func (v StructDeclr) WriteTo(w io.Writer) (int64, error) {
 
 //======================================================================================================================
 
+// TypeParamDeclr defines a declaration for a single type parameter of a generic type or function.
+type TypeParamDeclr struct {
+	Name       NameDeclr   `json:"name"`
+	Constraint io.WriterTo `json:"constraint"`
+}
+
+// WriteTo writes to the provided writer the type parameter declaration.
+func (t TypeParamDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("typeParamDeclr", templates.Must("type-param.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	constraint, err := writerToString(t.Constraint)
+	if err != nil {
+		return 0, err
+	}
+
+	if constraint == "" {
+		constraint = "any"
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name       string
+		Constraint string
+	}{
+		Name:       t.Name.String(),
+		Constraint: constraint,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// TypeParamsDeclr defines a declaration for the type parameter list of a generic type or
+// function. It writes nothing if it has no parameters.
+type TypeParamsDeclr struct {
+	Params []TypeParamDeclr `json:"params"`
+}
+
+// WriteTo writes to the provided writer the type parameter list declaration.
+func (t TypeParamsDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("typeParamsDeclr", templates.Must("type-params.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var params []string
+	for _, param := range t.Params {
+		content, err := writerToString(param)
+		if err != nil {
+			return 0, err
+		}
+
+		params = append(params, content)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Params []string
+	}{
+		Params: params,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// String returns the type parameter list as it would be written.
+func (t TypeParamsDeclr) String() string {
+	content, _ := writerToString(t)
+	return content
+}
+
+// TypeTermDeclr defines a declaration for a single term of a type set, where Tilde
+// marks the term as covering all types with the giving underlying type.
+type TypeTermDeclr struct {
+	Type  TypeDeclr `json:"type"`
+	Tilde bool      `json:"tilde"`
+}
+
+// WriteTo writes to the provided writer the type term declaration.
+func (t TypeTermDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("typeTermDeclr", templates.Must("type-term.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Type  string
+		Tilde bool
+	}{
+		Type:  t.Type.String(),
+		Tilde: t.Tilde,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// TypeSetDeclr defines a declaration for a union of type terms, as used within
+// interface constraints (e.g ~int | ~string).
+type TypeSetDeclr struct {
+	Terms []TypeTermDeclr `json:"terms"`
+}
+
+// WriteTo writes to the provided writer the type set declaration.
+func (t TypeSetDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("typeSetDeclr", templates.Must("type-set.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var terms []string
+	for _, term := range t.Terms {
+		content, err := writerToString(term)
+		if err != nil {
+			return 0, err
+		}
+
+		terms = append(terms, content)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Terms []string
+	}{
+		Terms: terms,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// ParamDeclr defines a declaration for a single parameter or result of a function
+// signature. The name is optional for results, and Variadic marks a final ...T parameter.
+type ParamDeclr struct {
+	Name     NameDeclr `json:"name"`
+	Type     TypeDeclr `json:"type"`
+	Variadic bool      `json:"variadic"`
+}
+
+// WriteTo writes to the provided writer the parameter declaration.
+func (p ParamDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("paramDeclr", templates.Must("param.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name     string
+		Type     string
+		Variadic bool
+	}{
+		Name:     p.Name.String(),
+		Type:     p.Type.String(),
+		Variadic: p.Variadic,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// MethodDeclr defines a declaration for a method signature within an interface.
+type MethodDeclr struct {
+	Name    NameDeclr    `json:"name"`
+	Params  []ParamDeclr `json:"params"`
+	Results []ParamDeclr `json:"results"`
+}
+
+// WriteTo writes to the provided writer the method signature declaration.
+func (m MethodDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("methodDeclr", templates.Must("method-signature.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var params, results []string
+
+	for _, param := range m.Params {
+		content, err := writerToString(param)
+		if err != nil {
+			return 0, err
+		}
+
+		params = append(params, content)
+	}
+
+	for _, result := range m.Results {
+		content, err := writerToString(result)
+		if err != nil {
+			return 0, err
+		}
+
+		results = append(results, content)
+	}
+
+	var returns string
+
+	switch {
+	case len(m.Results) == 1 && m.Results[0].Name.Name == "":
+		returns = results[0]
+	case len(m.Results) > 0:
+		returns = "(" + strings.Join(results, ", ") + ")"
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name    string
+		Params  []string
+		Results string
+	}{
+		Name:    m.Name.String(),
+		Params:  params,
+		Results: returns,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// InterfaceDeclr defines a declaration for a go interface type. Its elements are written
+// one per line in the order provided, which lets method signatures (MethodDeclr),
+// embedded interfaces (TypeDeclr) and type-set constraints (TypeSetDeclr) be mixed.
+type InterfaceDeclr struct {
+	Name        NameDeclr       `json:"name"`
+	TypeParams  TypeParamsDeclr `json:"typeParams"`
+	Comments    io.WriterTo     `json:"comments"`
+	Annotations io.WriterTo     `json:"annotations"`
+	Elements    WritersTo       `json:"elements"`
+}
+
+// WriteTo writes to the provided writer the interface declaration.
+func (i InterfaceDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("interfaceDeclr", templates.Must("interface.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(i.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	annotations, err := writerToString(i.Annotations)
+	if err != nil {
+		return 0, err
+	}
+
+	var elements []string
+	for _, elem := range i.Elements {
+		content, err := renderNestedOr(w, elem, "\t")
+		if err != nil {
+			return 0, err
+		}
+
+		elements = append(elements, content)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name        string
+		TypeParams  string
+		Comments    string
+		Annotations string
+		Elements    []string
+	}{
+		Name:        i.Name.String(),
+		TypeParams:  i.TypeParams.String(),
+		Comments:    strings.TrimRight(comments, "\n"),
+		Annotations: strings.TrimRight(annotations, "\n"),
+		Elements:    elements,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// TypeAliasDeclr defines a declaration for a type alias (e.g type X = Y).
+type TypeAliasDeclr struct {
+	Name        NameDeclr   `json:"name"`
+	Type        TypeDeclr   `json:"type"`
+	Comments    io.WriterTo `json:"comments"`
+	Annotations io.WriterTo `json:"annotations"`
+}
+
+// WriteTo writes to the provided writer the type alias declaration.
+func (t TypeAliasDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("typeAliasDeclr", templates.Must("type-alias.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(t.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	annotations, err := writerToString(t.Annotations)
+	if err != nil {
+		return 0, err
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name        string
+		Type        string
+		Comments    string
+		Annotations string
+	}{
+		Name:        t.Name.String(),
+		Type:        t.Type.String(),
+		Comments:    strings.TrimRight(comments, "\n"),
+		Annotations: strings.TrimRight(annotations, "\n"),
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// DefinedTypeDeclr defines a declaration for a new named type built on an underlying
+// type (e.g type ID string), which may be generic through its type parameters.
+type DefinedTypeDeclr struct {
+	Name        NameDeclr       `json:"name"`
+	TypeParams  TypeParamsDeclr `json:"typeParams"`
+	Type        TypeDeclr       `json:"type"`
+	Comments    io.WriterTo     `json:"comments"`
+	Annotations io.WriterTo     `json:"annotations"`
+}
+
+// WriteTo writes to the provided writer the defined type declaration.
+func (t DefinedTypeDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("definedTypeDeclr", templates.Must("defined-type.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(t.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	annotations, err := writerToString(t.Annotations)
+	if err != nil {
+		return 0, err
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name        string
+		TypeParams  string
+		Type        string
+		Comments    string
+		Annotations string
+	}{
+		Name:        t.Name.String(),
+		TypeParams:  t.TypeParams.String(),
+		Type:        t.Type.String(),
+		Comments:    strings.TrimRight(comments, "\n"),
+		Annotations: strings.TrimRight(annotations, "\n"),
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// ConstValueDeclr defines a declaration for a single constant within a const block.
+// Type and Value are optional, which allows repeating the previous expression of the
+// block as done with iota.
+type ConstValueDeclr struct {
+	Name    NameDeclr   `json:"name"`
+	Type    TypeDeclr   `json:"type"`
+	Value   io.WriterTo `json:"value"`
+	Comment string      `json:"comment"`
+}
+
+// WriteTo writes to the provided writer the constant declaration.
+func (c ConstValueDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("constValueDeclr", templates.Must("const-value.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := writerToString(c.Value)
+	if err != nil {
+		return 0, err
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name    string
+		Type    string
+		Value   string
+		Comment string
+	}{
+		Name:    c.Name.String(),
+		Type:    c.Type.String(),
+		Value:   value,
+		Comment: c.Comment,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// ConstBlockDeclr defines a declaration for a grouped const block.
+type ConstBlockDeclr struct {
+	Comments io.WriterTo       `json:"comments"`
+	Values   []ConstValueDeclr `json:"values"`
+}
+
+// WriteTo writes to the provided writer the const block declaration.
+func (c ConstBlockDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("constBlockDeclr", templates.Must("const-block.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(c.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	var values []string
+	for _, value := range c.Values {
+		content, err := renderNestedOr(w, value, "\t")
+		if err != nil {
+			return 0, err
+		}
+
+		values = append(values, content)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Comments string
+		Values   []string
+	}{
+		Comments: strings.TrimRight(comments, "\n"),
+		Values:   values,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+//======================================================================================================================
+
 // CommentDeclr defines a declaration struct for representing a single comment.
 type CommentDeclr struct {
 	MainBlock io.WriterTo `json:"mainBlock"`
@@ -2047,13 +2590,14 @@ func (c IfDeclr) WriteTo(w io.Writer) (int64, error) {
 		BlockEnd:   []byte(")"),
 	}
 
-	var action, condition bytes.Buffer
+	var condition bytes.Buffer
 
 	if _, err := block.WriteTo(&condition); IsNotDrainError(err) {
 		return 0, err
 	}
 
-	if _, err := c.Action.WriteTo(&action); IsNotDrainError(err) {
+	action, err := renderNested(w, c.Action, 1)
+	if err != nil {
 		return 0, err
 	}
 
@@ -2064,7 +2608,7 @@ func (c IfDeclr) WriteTo(w io.Writer) (int64, error) {
 		Action    string
 	}{
 		Condition: condition.String(),
-		Action:    action.String(),
+		Action:    action,
 	}); err != nil {
 		return 0, err
 	}
@@ -2088,9 +2632,8 @@ func (c DefaultCaseDeclr) WriteTo(w io.Writer) (int64, error) {
 		return 0, err
 	}
 
-	var caseAction bytes.Buffer
-
-	if _, err := c.Behaviour.WriteTo(&caseAction); IsNotDrainError(err) {
+	caseAction, err := renderNestedOr(w, c.Behaviour, legacyIndent)
+	if err != nil {
 		return 0, err
 	}
 
@@ -2099,7 +2642,7 @@ func (c DefaultCaseDeclr) WriteTo(w io.Writer) (int64, error) {
 	if err := tml.Execute(wc, struct {
 		Action string
 	}{
-		Action: caseAction.String(),
+		Action: caseAction,
 	}); err != nil {
 		return 0, err
 	}
@@ -2122,13 +2665,14 @@ func (c CaseDeclr) WriteTo(w io.Writer) (int64, error) {
 		return 0, err
 	}
 
-	var caseCondition, caseAction bytes.Buffer
+	var caseCondition bytes.Buffer
 
 	if _, err := c.Condition.WriteTo(&caseCondition); IsNotDrainError(err) {
 		return 0, err
 	}
 
-	if _, err := c.Behaviour.WriteTo(&caseAction); IsNotDrainError(err) {
+	caseAction, err := renderNestedOr(w, c.Behaviour, legacyIndent)
+	if err != nil {
 		return 0, err
 	}
 
@@ -2138,7 +2682,7 @@ func (c CaseDeclr) WriteTo(w io.Writer) (int64, error) {
 		Action    string
 		Condition string
 	}{
-		Action:    caseAction.String(),
+		Action:    caseAction,
 		Condition: caseCondition.String(),
 	}); err != nil {
 		return 0, err
@@ -2158,21 +2702,25 @@ type SwitchDeclr struct {
 func (c SwitchDeclr) WriteTo(w io.Writer) (int64, error) {
 	w = NewNoBOM(w)
 
-	tml, err := ToTemplate("caseDeclr", templates.Must("case.tml"), nil)
+	tml, err := ToTemplate("switchDeclr", templates.Must("switch.tml"), nil)
 	if err != nil {
 		return 0, err
 	}
 
-	var caseCondition, caseDefault, caseAction bytes.Buffer
+	var caseCondition bytes.Buffer
+	var caseDefault, caseAction string
 
 	for _, item := range c.Cases {
-		if _, err := item.WriteTo(&caseAction); IsNotDrainError(err) {
+		content, err := renderNested(w, item, 0)
+		if err != nil {
 			return 0, err
 		}
+
+		caseAction += content
 	}
 
 	if c.Default.Behaviour != nil {
-		if _, err := c.Default.WriteTo(&caseDefault); IsNotDrainError(err) {
+		if caseDefault, err = renderNested(w, c.Default, 0); err != nil {
 			return 0, err
 		}
 	}
@@ -2188,8 +2736,8 @@ func (c SwitchDeclr) WriteTo(w io.Writer) (int64, error) {
 		Cases     string
 		Default   string
 	}{
-		Cases:     caseAction.String(),
-		Default:   caseDefault.String(),
+		Cases:     caseAction,
+		Default:   caseDefault,
 		Condition: caseCondition.String(),
 	}); err != nil {
 		return 0, err
@@ -2238,3 +2786,65 @@ func cleanSpace(ns ...string) []string {
 
 	return ns
 }
+
+// writerToString returns the content written by the provided io.WriterTo, where a nil
+// io.WriterTo produces an empty string.
+func writerToString(w io.WriterTo) (string, error) {
+	if w == nil {
+		return "", nil
+	}
+
+	var b bytes.Buffer
+	if _, err := w.WriteTo(&b); IsNotDrainError(err) {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// legacyIndent defines the indentation templates used for nested content before
+// IndentWriter existed, which is kept when not writing to an IndentWriter.
+const legacyIndent = "    "
+
+// renderNested returns the content of the body as it should be embedded within a declaration
+// written to w. When w writes into an IndentWriter, the body is written through a new IndentWriter
+// with the same unit nested by the giving depth, so its own blocks stay indentation aware.
+// Otherwise the body is written as is.
+func renderNested(w io.Writer, body io.WriterTo, depth int) (string, error) {
+	iw, ok := IndentWriterFor(w)
+	if !ok {
+		return writerToString(body)
+	}
+
+	if body == nil {
+		return "", nil
+	}
+
+	var b bytes.Buffer
+
+	nested := NewIndentWriter(&b, iw.Unit)
+	for i := 0; i < depth; i++ {
+		nested.Push()
+	}
+
+	if _, err := body.WriteTo(nested); IsNotDrainError(err) {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// renderNestedOr returns the content of the body nested a single level within a declaration
+// written to w, where fallback is prefixed to the content when w is not an IndentWriter.
+func renderNestedOr(w io.Writer, body io.WriterTo, fallback string) (string, error) {
+	if _, ok := IndentWriterFor(w); ok {
+		return renderNested(w, body, 1)
+	}
+
+	content, err := writerToString(body)
+	if err != nil {
+		return "", err
+	}
+
+	return fallback + content, nil
+}
@@ -74,3 +74,183 @@ func TestMapGen(t *testing.T) {
 	}
 	tests.Passed("Should have successfully matched generated output with expected.")
 }
+
+// TestInterfaceGen validates the generation of a generic interface with type sets,
+// embedded interfaces and methods.
+func TestInterfaceGen(t *testing.T) {
+	expected := "// Store provides storage for numbers.\ntype Store[K comparable, V any] interface {\n\t~int | ~int64\n\tio.Closer\n\tGet(key K) (V, error)\n\tKeys(filters ...K) []K\n\tReset()\n}"
+
+	src := gen.GenericInterfaceType(
+		gen.Name("Store"),
+		gen.TypeParams(
+			gen.TypeParam("K", gen.Type("comparable")),
+			gen.TypeParam("V", nil),
+		),
+		gen.Text("// Store provides storage for numbers."),
+		nil,
+		gen.TypeSet(gen.ApproxTerm("int"), gen.ApproxTerm("int64")),
+		gen.Type("io.Closer"),
+		gen.Method(
+			gen.Name("Get"),
+			gen.Params(gen.Param("key", "K")),
+			gen.Result("V"),
+			gen.Result("error"),
+		),
+		gen.Method(gen.Name("Keys"), gen.Params(gen.VariadicParam("filters", "K")), gen.Result("[]K")),
+		gen.Method(gen.Name("Reset"), nil),
+	)
+
+	var bu bytes.Buffer
+
+	if _, err := src.WriteTo(&bu); err != nil && err != io.EOF {
+		tests.Failed("Should have successfully written source output: %+q.", err)
+	}
+	tests.Passed("Should have successfully written source output.")
+
+	if bu.String() != expected {
+		tests.Info("Source: %+q", bu.String())
+		tests.Info("Expected: %+q", expected)
+
+		tests.Failed("Should have successfully matched generated output with expected.")
+	}
+	tests.Passed("Should have successfully matched generated output with expected.")
+}
+
+// TestFunctionParamsGen validates the generation of a function taking params.
+func TestFunctionParamsGen(t *testing.T) {
+	expected := "\nfunc Scale(value int, factors ...int) (int) {\nreturn value\n}\n"
+
+	src := gen.FunctionWithParams(
+		gen.Name("Scale"),
+		gen.Params(gen.Param("value", "int"), gen.VariadicParam("factors", "int")),
+		gen.Returns(gen.Type("int")),
+		gen.Text("return value"),
+	)
+
+	var bu bytes.Buffer
+
+	if _, err := src.WriteTo(&bu); err != nil && err != io.EOF {
+		tests.Failed("Should have successfully written source output: %+q.", err)
+	}
+	tests.Passed("Should have successfully written source output.")
+
+	if bu.String() != expected {
+		tests.Info("Source: %+q", bu.String())
+		tests.Info("Expected: %+q", expected)
+
+		tests.Failed("Should have successfully matched generated output with expected.")
+	}
+	tests.Passed("Should have successfully matched generated output with expected.")
+}
+
+// TestConstBlockGen validates the generation of a defined type with its iota const block.
+func TestConstBlockGen(t *testing.T) {
+	expected := "type Color int\n\nconst (\n\tRed Color = iota\n\tGreen\n\tBlue\n)\n\ntype Shade = Color"
+
+	src := gen.WritersTo{
+		gen.DefinedType(gen.Name("Color"), gen.Type("int"), nil, nil),
+		gen.Text("\n\n"),
+		gen.IotaBlock(nil, "Color", "Red", "Green", "Blue"),
+		gen.Text("\n\n"),
+		gen.Alias(gen.Name("Shade"), gen.Type("Color"), nil),
+	}
+
+	var bu bytes.Buffer
+
+	if _, err := src.WriteTo(&bu); err != nil && err != io.EOF {
+		tests.Failed("Should have successfully written source output: %+q.", err)
+	}
+	tests.Passed("Should have successfully written source output.")
+
+	if bu.String() != expected {
+		tests.Info("Source: %+q", bu.String())
+		tests.Info("Expected: %+q", expected)
+
+		tests.Failed("Should have successfully matched generated output with expected.")
+	}
+	tests.Passed("Should have successfully matched generated output with expected.")
+}
+
+// TestJSONDocumentGen validates the generation of escaped, sorted JSON documents.
+func TestJSONDocumentGen(t *testing.T) {
+	document := map[string]interface{}{
+		"name\t\"id\"": "<b>\n</b>",
+		"age":          32,
+		"scores":       []float64{1.5, 2},
+		"empty":        map[string]string{},
+		"profile": gen.JSONDocument(map[string]io.WriterTo{
+			"active": gen.Text("true"),
+			"tags":   gen.Text(`["a", "b"]`),
+		}),
+	}
+
+	pretty := "{\n  \"age\": 32,\n  \"empty\": {},\n  \"name\\t\\\"id\\\"\": \"<b>\\n</b>\",\n  \"profile\": {\n    \"active\": true,\n    \"tags\": [\n      \"a\",\n      \"b\"\n    ]\n  },\n  \"scores\": [\n    1.5,\n    2\n  ]\n}"
+	compact := `{"age":32,"empty":{},"name\t\"id\"":"<b>\n</b>","profile":{"active":true,"tags":["a","b"]},"scores":[1.5,2]}`
+
+	var bu bytes.Buffer
+
+	if _, err := gen.JSONPretty(document, "  ").WriteTo(&bu); err != nil {
+		tests.Failed("Should have successfully written pretty JSON document: %+q.", err)
+	}
+	tests.Passed("Should have successfully written pretty JSON document.")
+
+	if bu.String() != pretty {
+		tests.Info("Source: %+q", bu.String())
+		tests.Info("Expected: %+q", pretty)
+
+		tests.Failed("Should have successfully matched pretty JSON document with expected.")
+	}
+	tests.Passed("Should have successfully matched pretty JSON document with expected.")
+
+	bu.Reset()
+
+	if _, err := gen.JSONCompact(document).WriteTo(&bu); err != nil {
+		tests.Failed("Should have successfully written compact JSON document: %+q.", err)
+	}
+	tests.Passed("Should have successfully written compact JSON document.")
+
+	if bu.String() != compact {
+		tests.Info("Source: %+q", bu.String())
+		tests.Info("Expected: %+q", compact)
+
+		tests.Failed("Should have successfully matched compact JSON document with expected.")
+	}
+	tests.Passed("Should have successfully matched compact JSON document with expected.")
+
+	if _, err := gen.JSONCompact([]io.WriterTo{gen.Text("[]string{}")}).WriteTo(&bu); err == nil {
+		tests.Failed("Should have failed to write JSON document with invalid fragment.")
+	}
+	tests.Passed("Should have failed to write JSON document with invalid fragment.")
+}
+
+// TestYAMLDocumentGen validates the generation of block style YAML documents.
+func TestYAMLDocumentGen(t *testing.T) {
+	document := map[string]interface{}{
+		"name":    "moz",
+		"version": "1.0",
+		"enabled": true,
+		"tags":    []string{"gen", "yes", "a: b"},
+		"owners": []interface{}{
+			map[string]interface{}{"name": "alex", "admin": false},
+		},
+		"empty": []string{},
+		"meta":  gen.Text(`{"retries": 3, "note": null}`),
+	}
+
+	expected := "empty: []\nenabled: true\nmeta:\n  note: null\n  retries: 3\nname: moz\nowners:\n  -\n    admin: false\n    name: alex\ntags:\n  - gen\n  - \"yes\"\n  - \"a: b\"\nversion: \"1.0\"\n"
+
+	var bu bytes.Buffer
+
+	if _, err := gen.YAML(document).WriteTo(&bu); err != nil {
+		tests.Failed("Should have successfully written YAML document: %+q.", err)
+	}
+	tests.Passed("Should have successfully written YAML document.")
+
+	if bu.String() != expected {
+		tests.Info("Source: %+q", bu.String())
+		tests.Info("Expected: %+q", expected)
+
+		tests.Failed("Should have successfully matched YAML document with expected.")
+	}
+	tests.Passed("Should have successfully matched YAML document with expected.")
+}
@@ -145,6 +145,29 @@ func JSONDocument(contents map[string]io.WriterTo) JSONBlock {
 	}
 }
 
+// JSONPretty returns a JSONDocumentDeclr which writes the value as JSON, indenting
+// nested objects and arrays with the giving indent.
+func JSONPretty(value interface{}, indent string) JSONDocumentDeclr {
+	return JSONDocumentDeclr{
+		Value:  value,
+		Indent: indent,
+	}
+}
+
+// JSONCompact returns a JSONDocumentDeclr which writes the value as compact JSON.
+func JSONCompact(value interface{}) JSONDocumentDeclr {
+	return JSONDocumentDeclr{
+		Value: value,
+	}
+}
+
+// YAML returns a YAMLDocumentDeclr which writes the value as a YAML document.
+func YAML(value interface{}) YAMLDocumentDeclr {
+	return YAMLDocumentDeclr{
+		Value: value,
+	}
+}
+
 // Text returns a new instance of a TextDeclr.
 func Text(txt string) TextBlockDeclr {
 	return TextBlockDeclr{
@@ -273,7 +296,8 @@ func Constructor(args ...VariableTypeDeclr) ConstructorDeclr {
 	}
 }
 
-// Interface returns a new instance of a StructDeclr to generate a go struct.
+// Interface returns a new instance of a StructDeclr to generate a go interface from
+// arbitrary fields. Use InterfaceType to declare method signatures and type sets.
 func Interface(name NameDeclr, comments io.WriterTo, annotations io.WriterTo, fields ...io.WriterTo) StructDeclr {
 	if annotations == nil {
 		annotations = bytes.NewBuffer(nil)
@@ -365,6 +389,16 @@ func Function(name NameDeclr, constr ConstructorDeclr, returns io.WriterTo, body
 	}
 }
 
+// FunctionWithParams returns a new instance of a FunctionDeclr taking the giving params.
+func FunctionWithParams(name NameDeclr, params []ParamDeclr, returns io.WriterTo, body ...io.WriterTo) FunctionDeclr {
+	return FunctionDeclr{
+		Name:    name,
+		Params:  params,
+		Returns: returns,
+		Body:    body,
+	}
+}
+
 // SourceWith returns a new instance of a SourceDeclr.
 func SourceWith(tml *template.Template, dfns template.FuncMap, binding interface{}) SourceDeclr {
 	return SourceDeclr{
@@ -485,6 +519,22 @@ func ByteWrapBlock(begin, end []byte, elems ...io.WriterTo) ByteBlockDeclr {
 	}
 }
 
+// Indented returns a new instance of a IndentedDeclr which writes the elements with
+// indentation tracking, using the giving unit for each level of depth.
+func Indented(unit string, elems ...io.WriterTo) IndentedDeclr {
+	return IndentedDeclr{
+		Unit:  unit,
+		Block: WritersTo(elems),
+	}
+}
+
+// Indent returns a new instance of a IndentDeclr which indents the elements a level deeper.
+func Indent(elems ...io.WriterTo) IndentDeclr {
+	return IndentDeclr{
+		Block: WritersTo(elems),
+	}
+}
+
 // Switch returns a new instance of a SwitchDeclr.
 func Switch(condition io.WriterTo, def DefaultCaseDeclr, cases ...CaseDeclr) SwitchDeclr {
 	if def.Behaviour == nil {
@@ -603,3 +653,332 @@ func If(condition, action io.WriterTo) IfDeclr {
 		Condition: condition,
 	}
 }
+
+// InterfaceType returns a new instance of a InterfaceDeclr to generate a go interface,
+// where elements are method signatures, embedded interfaces or type sets.
+func InterfaceType(name NameDeclr, comments io.WriterTo, annotations io.WriterTo, elems ...io.WriterTo) InterfaceDeclr {
+	return InterfaceDeclr{
+		Name:        name,
+		Comments:    comments,
+		Annotations: annotations,
+		Elements:    elems,
+	}
+}
+
+// GenericInterfaceType returns a new instance of a InterfaceDeclr to generate a go interface
+// with the provided type parameters.
+func GenericInterfaceType(name NameDeclr, params TypeParamsDeclr, comments io.WriterTo, annotations io.WriterTo, elems ...io.WriterTo) InterfaceDeclr {
+	return InterfaceDeclr{
+		Name:        name,
+		TypeParams:  params,
+		Comments:    comments,
+		Annotations: annotations,
+		Elements:    elems,
+	}
+}
+
+// Method returns a new instance of a MethodDeclr.
+func Method(name NameDeclr, params []ParamDeclr, results ...ParamDeclr) MethodDeclr {
+	return MethodDeclr{
+		Name:    name,
+		Params:  params,
+		Results: results,
+	}
+}
+
+// Params returns a slice of ParamDeclr, for use with Method.
+func Params(params ...ParamDeclr) []ParamDeclr {
+	return params
+}
+
+// Param returns a new instance of a ParamDeclr.
+func Param(name string, typeName string) ParamDeclr {
+	return ParamDeclr{
+		Name: Name(name),
+		Type: Type(typeName),
+	}
+}
+
+// VariadicParam returns a new instance of a ParamDeclr for a final ...T parameter.
+func VariadicParam(name string, typeName string) ParamDeclr {
+	return ParamDeclr{
+		Name:     Name(name),
+		Type:     Type(typeName),
+		Variadic: true,
+	}
+}
+
+// Result returns a new instance of a ParamDeclr for an unnamed result.
+func Result(typeName string) ParamDeclr {
+	return ParamDeclr{
+		Type: Type(typeName),
+	}
+}
+
+// Term returns a new instance of a TypeTermDeclr matching the exact type.
+func Term(typeName string) TypeTermDeclr {
+	return TypeTermDeclr{
+		Type: Type(typeName),
+	}
+}
+
+// ApproxTerm returns a new instance of a TypeTermDeclr matching all types
+// with the giving underlying type.
+func ApproxTerm(typeName string) TypeTermDeclr {
+	return TypeTermDeclr{
+		Type:  Type(typeName),
+		Tilde: true,
+	}
+}
+
+// TypeSet returns a new instance of a TypeSetDeclr.
+func TypeSet(terms ...TypeTermDeclr) TypeSetDeclr {
+	return TypeSetDeclr{
+		Terms: terms,
+	}
+}
+
+// TypeParam returns a new instance of a TypeParamDeclr, where a nil constraint
+// is written as any.
+func TypeParam(name string, constraint io.WriterTo) TypeParamDeclr {
+	return TypeParamDeclr{
+		Name:       Name(name),
+		Constraint: constraint,
+	}
+}
+
+// TypeParams returns a new instance of a TypeParamsDeclr.
+func TypeParams(params ...TypeParamDeclr) TypeParamsDeclr {
+	return TypeParamsDeclr{
+		Params: params,
+	}
+}
+
+// GenericName returns a new instance of a NameDeclr which holds the name followed by
+// the type parameter list, for use with declarations like StructDeclr or FunctionDeclr.
+func GenericName(name string, params ...TypeParamDeclr) NameDeclr {
+	return NameDeclr{
+		Name: name + TypeParams(params...).String(),
+	}
+}
+
+// Alias returns a new instance of a TypeAliasDeclr.
+func Alias(name NameDeclr, target TypeDeclr, comments io.WriterTo) TypeAliasDeclr {
+	return TypeAliasDeclr{
+		Name:     name,
+		Type:     target,
+		Comments: comments,
+	}
+}
+
+// DefinedType returns a new instance of a DefinedTypeDeclr.
+func DefinedType(name NameDeclr, underlying TypeDeclr, comments io.WriterTo, annotations io.WriterTo) DefinedTypeDeclr {
+	return DefinedTypeDeclr{
+		Name:        name,
+		Type:        underlying,
+		Comments:    comments,
+		Annotations: annotations,
+	}
+}
+
+// ConstValue returns a new instance of a ConstValueDeclr.
+func ConstValue(name NameDeclr, ctype TypeDeclr, value io.WriterTo) ConstValueDeclr {
+	return ConstValueDeclr{
+		Name:  name,
+		Type:  ctype,
+		Value: value,
+	}
+}
+
+// ConstBlock returns a new instance of a ConstBlockDeclr.
+func ConstBlock(comments io.WriterTo, values ...ConstValueDeclr) ConstBlockDeclr {
+	return ConstBlockDeclr{
+		Comments: comments,
+		Values:   values,
+	}
+}
+
+// IotaBlock returns a new instance of a ConstBlockDeclr which declares the names as
+// successive iota values of the giving type.
+func IotaBlock(comments io.WriterTo, typeName string, names ...string) ConstBlockDeclr {
+	var values []ConstValueDeclr
+
+	for index, name := range names {
+		if index == 0 {
+			values = append(values, ConstValue(Name(name), Type(typeName), Text("iota")))
+			continue
+		}
+
+		values = append(values, ConstValue(Name(name), TypeDeclr{}, nil))
+	}
+
+	return ConstBlock(comments, values...)
+}
+
+// TSInterface returns a new instance of a TSInterfaceDeclr for an exported typescript interface.
+func TSInterface(name NameDeclr, comments io.WriterTo, fields ...TSFieldDeclr) TSInterfaceDeclr {
+	return TSInterfaceDeclr{
+		Name:     name,
+		Exported: true,
+		Comments: comments,
+		Fields:   fields,
+	}
+}
+
+// TSField returns a new instance of a TSFieldDeclr.
+func TSField(name string, tsType string, optional bool) TSFieldDeclr {
+	return TSFieldDeclr{
+		Name:     name,
+		Type:     tsType,
+		Optional: optional,
+	}
+}
+
+// TSEnum returns a new instance of a TSEnumDeclr for an exported typescript enum.
+func TSEnum(name NameDeclr, comments io.WriterTo, members ...TSEnumMemberDeclr) TSEnumDeclr {
+	return TSEnumDeclr{
+		Name:     name,
+		Exported: true,
+		Comments: comments,
+		Members:  members,
+	}
+}
+
+// TSEnumMember returns a new instance of a TSEnumMemberDeclr.
+func TSEnumMember(name string, value io.WriterTo) TSEnumMemberDeclr {
+	return TSEnumMemberDeclr{
+		Name:  name,
+		Value: value,
+	}
+}
+
+// ProtoFile returns a new instance of a ProtoFileDeclr using the proto3 syntax.
+func ProtoFile(pkg string, elems ...io.WriterTo) ProtoFileDeclr {
+	return ProtoFileDeclr{
+		Syntax:   "proto3",
+		Package:  pkg,
+		Elements: elems,
+	}
+}
+
+// ProtoMessage returns a new instance of a ProtoMessageDeclr.
+func ProtoMessage(name NameDeclr, comments io.WriterTo, fields ...ProtoFieldDeclr) ProtoMessageDeclr {
+	return ProtoMessageDeclr{
+		Name:     name,
+		Comments: comments,
+		Fields:   fields,
+	}
+}
+
+// ProtoField returns a new instance of a ProtoFieldDeclr.
+func ProtoField(name string, protoType string, number int) ProtoFieldDeclr {
+	return ProtoFieldDeclr{
+		Name:   name,
+		Type:   protoType,
+		Number: number,
+	}
+}
+
+// ProtoService returns a new instance of a ProtoServiceDeclr.
+func ProtoService(name NameDeclr, comments io.WriterTo, methods ...ProtoRPCDeclr) ProtoServiceDeclr {
+	return ProtoServiceDeclr{
+		Name:     name,
+		Comments: comments,
+		Methods:  methods,
+	}
+}
+
+// ProtoRPC returns a new instance of a ProtoRPCDeclr.
+func ProtoRPC(name string, request string, response string) ProtoRPCDeclr {
+	return ProtoRPCDeclr{
+		Name:     name,
+		Request:  request,
+		Response: response,
+	}
+}
+
+// SQLTable returns a new instance of a SQLTableDeclr.
+func SQLTable(name string, columns ...SQLColumnDeclr) SQLTableDeclr {
+	return SQLTableDeclr{
+		Name:    name,
+		Columns: columns,
+	}
+}
+
+// SQLColumn returns a new instance of a SQLColumnDeclr.
+func SQLColumn(name string, sqlType string, notNull bool) SQLColumnDeclr {
+	return SQLColumnDeclr{
+		Name:    name,
+		Type:    sqlType,
+		NotNull: notNull,
+	}
+}
+
+// Enum returns a new instance of a EnumDeclr for the named type with the giving underlying type.
+func Enum(name string, underlying string, values ...EnumValueDeclr) EnumDeclr {
+	return EnumDeclr{
+		Name:       Name(name),
+		Underlying: Type(underlying),
+		Values:     values,
+	}
+}
+
+// EnumValue returns a new instance of a EnumValueDeclr.
+func EnumValue(constant string, name string) EnumValueDeclr {
+	return EnumValueDeclr{
+		Constant: constant,
+		Name:     name,
+	}
+}
+
+// Mock returns a new instance of a MockDeclr for a struct with the giving name implementing
+// the interface.
+func Mock(name string, intr string, methods ...MockMethodDeclr) MockDeclr {
+	return MockDeclr{
+		Name:      Name(name),
+		Interface: Type(intr),
+		Methods:   methods,
+	}
+}
+
+// MockMethod returns a new instance of a MockMethodDeclr.
+func MockMethod(name string, args []MockArgDeclr, returns ...string) MockMethodDeclr {
+	return MockMethodDeclr{
+		Name:    name,
+		Args:    args,
+		Returns: returns,
+	}
+}
+
+// Validate returns a new instance of a ValidateDeclr for a Validate method of the named struct.
+func Validate(name string, fields ...ValidateFieldDeclr) ValidateDeclr {
+	return ValidateDeclr{
+		Name:   Name(name),
+		Fields: fields,
+	}
+}
+
+// EmbedFS returns a new instance of a EmbedFSDeclr for a variable of the giving name serving the
+// files as a fs.FS.
+func EmbedFS(name string, files ...EmbedFileDeclr) EmbedFSDeclr {
+	return EmbedFSDeclr{
+		Name:  Name(name),
+		Files: files,
+	}
+}
+
+// EmbedFile returns a new instance of a EmbedFileDeclr for a file with the giving content.
+func EmbedFile(filePath string, data []byte) EmbedFileDeclr {
+	return EmbedFileDeclr{
+		Path: filePath,
+		Data: data,
+	}
+}
+
+// EmbedDir returns a new instance of a EmbedFileDeclr for a directory.
+func EmbedDir(dirPath string) EmbedFileDeclr {
+	return EmbedFileDeclr{
+		Path: dirPath,
+		Dir:  true,
+	}
+}
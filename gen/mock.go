@@ -0,0 +1,181 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/influx6/moz/gen/templates"
+)
+
+//======================================================================================================================
+
+// MockArgDeclr defines a parameter of a mocked method, where Type is the element type of a
+// variadic parameter.
+type MockArgDeclr struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Variadic bool   `json:"variadic"`
+}
+
+// MockMethodDeclr defines a method of a mocked interface with the types it returns.
+type MockMethodDeclr struct {
+	Name    string         `json:"name"`
+	Args    []MockArgDeclr `json:"args"`
+	Returns []string       `json:"returns"`
+}
+
+// MockDeclr defines a declaration for a struct implementing an interface, which has a function
+// field per method called by it, and records every call with its arguments. Methods without a
+// function set return zero values. The generated source requires the "sync" package.
+type MockDeclr struct {
+	Name      NameDeclr         `json:"name"`
+	Interface TypeDeclr         `json:"interface"`
+	Comments  io.WriterTo       `json:"comments"`
+	Methods   []MockMethodDeclr `json:"methods"`
+}
+
+// mockField defines a parameter of a mocked method as recorded and passed on.
+type mockField struct {
+	Name string
+	Type string
+	Arg  string
+}
+
+// mockMethod defines a mocked method as written by the mock template.
+type mockMethod struct {
+	Name     string
+	CallType string
+	FuncType string
+	Params   string
+	Results  string
+	Forward  string
+	Assigns  []string
+	Fields   []mockField
+}
+
+// WriteTo writes to the provided writer the mock declaration.
+func (m MockDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("mockDeclr", templates.Must("mock.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(m.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	name := m.Name.String()
+	first, _ := utf8.DecodeRuneInString(name)
+	receiver := string(unicode.ToLower(first))
+
+	var methods []mockMethod
+	for _, method := range m.Methods {
+		methods = append(methods, mockMethodOf(name, receiver, method))
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name      string
+		Interface string
+		Receiver  string
+		Comments  string
+		Methods   []mockMethod
+	}{
+		Name:      name,
+		Interface: m.Interface.String(),
+		Receiver:  receiver,
+		Comments:  strings.TrimRight(comments, "\n"),
+		Methods:   methods,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// mockMethodOf returns the mocked method, where unnamed or blank parameters are named by position and
+// parameters using the names of the receiver, function or results are renamed.
+func mockMethodOf(mockName string, receiver string, method MockMethodDeclr) mockMethod {
+	taken := map[string]bool{receiver: true, "fn": true}
+
+	var results []string
+	for index, ret := range method.Returns {
+		result := fmt.Sprintf("ret%d", index)
+		taken[result] = true
+		results = append(results, result+" "+ret)
+	}
+
+	mocked := mockMethod{
+		Name:     method.Name,
+		CallType: mockName + method.Name + "Call",
+	}
+
+	fields := make(map[string]bool)
+
+	var params, types, forward []string
+	for index, arg := range method.Args {
+		param := arg.Name
+		if param == "" || param == "_" {
+			param = fmt.Sprintf("arg%d", index+1)
+		}
+
+		for taken[param] {
+			param += "Arg"
+		}
+		taken[param] = true
+
+		field := mockField{
+			Name: exportedName(param),
+			Type: arg.Type,
+			Arg:  param,
+		}
+
+		for fields[field.Name] {
+			field.Name += "Arg"
+		}
+		fields[field.Name] = true
+
+		paramType := arg.Type
+		if arg.Variadic {
+			paramType = "..." + arg.Type
+			field.Type = "[]" + arg.Type
+			field.Arg = param + "..."
+		}
+
+		params = append(params, param+" "+paramType)
+		types = append(types, paramType)
+		forward = append(forward, field.Arg)
+
+		mocked.Fields = append(mocked.Fields, field)
+		mocked.Assigns = append(mocked.Assigns, field.Name+": "+param)
+	}
+
+	mocked.Params = strings.Join(params, ", ")
+	mocked.Forward = strings.Join(forward, ", ")
+	mocked.FuncType = "func(" + strings.Join(types, ", ") + ")"
+
+	switch len(method.Returns) {
+	case 0:
+	case 1:
+		mocked.FuncType += " " + method.Returns[0]
+		mocked.Results = " (" + results[0] + ")"
+	default:
+		mocked.FuncType += " (" + strings.Join(method.Returns, ", ") + ")"
+		mocked.Results = " (" + strings.Join(results, ", ") + ")"
+	}
+
+	return mocked
+}
+
+// exportedName returns the name with its first letter in upper case.
+func exportedName(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
@@ -0,0 +1,215 @@
+package gen
+
+import (
+	"io"
+	"strings"
+
+	"github.com/influx6/moz/gen/templates"
+)
+
+//======================================================================================================================
+
+// ProtoFieldDeclr defines a declaration for a field of a protobuf message.
+type ProtoFieldDeclr struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Number   int    `json:"number"`
+	Repeated bool   `json:"repeated"`
+	Optional bool   `json:"optional"`
+}
+
+// WriteTo writes to the provided writer the protobuf field declaration.
+func (p ProtoFieldDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("protoFieldDeclr", templates.Must("proto-field.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, p); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// ProtoMessageDeclr defines a declaration for a protobuf message.
+type ProtoMessageDeclr struct {
+	Name     NameDeclr         `json:"name"`
+	Comments io.WriterTo       `json:"comments"`
+	Fields   []ProtoFieldDeclr `json:"fields"`
+}
+
+// WriteTo writes to the provided writer the protobuf message declaration.
+func (p ProtoMessageDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("protoMessageDeclr", templates.Must("proto-message.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(p.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	var fields []string
+	for _, field := range p.Fields {
+		content, err := renderNestedOr(w, field, "  ")
+		if err != nil {
+			return 0, err
+		}
+
+		fields = append(fields, content)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name     string
+		Comments string
+		Fields   []string
+	}{
+		Name:     p.Name.String(),
+		Comments: strings.TrimRight(comments, "\n"),
+		Fields:   fields,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// ProtoRPCDeclr defines a declaration for a rpc method of a protobuf service.
+type ProtoRPCDeclr struct {
+	Name         string `json:"name"`
+	Request      string `json:"request"`
+	Response     string `json:"response"`
+	ClientStream bool   `json:"client_stream"`
+	ServerStream bool   `json:"server_stream"`
+}
+
+// WriteTo writes to the provided writer the protobuf rpc declaration.
+func (p ProtoRPCDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("protoRPCDeclr", templates.Must("proto-rpc.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, p); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// ProtoServiceDeclr defines a declaration for a protobuf service.
+type ProtoServiceDeclr struct {
+	Name     NameDeclr       `json:"name"`
+	Comments io.WriterTo     `json:"comments"`
+	Methods  []ProtoRPCDeclr `json:"methods"`
+}
+
+// WriteTo writes to the provided writer the protobuf service declaration.
+func (p ProtoServiceDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("protoServiceDeclr", templates.Must("proto-service.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(p.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	var methods []string
+	for _, method := range p.Methods {
+		content, err := renderNestedOr(w, method, "  ")
+		if err != nil {
+			return 0, err
+		}
+
+		methods = append(methods, content)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name     string
+		Comments string
+		Methods  []string
+	}{
+		Name:     p.Name.String(),
+		Comments: strings.TrimRight(comments, "\n"),
+		Methods:  methods,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// ProtoFileDeclr defines a declaration for a complete .proto file, where elements are
+// messages, services or any other top level declaration.
+type ProtoFileDeclr struct {
+	Syntax   string    `json:"syntax"`
+	Package  string    `json:"package"`
+	Imports  []string  `json:"imports"`
+	Options  []string  `json:"options"`
+	Elements WritersTo `json:"elements"`
+}
+
+// WriteTo writes to the provided writer the protobuf file declaration.
+func (p ProtoFileDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("protoFileDeclr", templates.Must("proto-file.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	syntax := p.Syntax
+	if syntax == "" {
+		syntax = "proto3"
+	}
+
+	var elements []string
+	for _, elem := range p.Elements {
+		content, err := renderNested(w, elem, 0)
+		if err != nil {
+			return 0, err
+		}
+
+		elements = append(elements, content)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Syntax   string
+		Package  string
+		Imports  []string
+		Options  []string
+		Elements []string
+	}{
+		Syntax:   syntax,
+		Package:  p.Package,
+		Imports:  p.Imports,
+		Options:  p.Options,
+		Elements: elements,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
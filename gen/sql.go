@@ -0,0 +1,128 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/influx6/moz/gen/templates"
+)
+
+//======================================================================================================================
+
+// SQLColumnDeclr defines a declaration for a column of a sql table.
+type SQLColumnDeclr struct {
+	Name       string      `json:"name"`
+	Type       string      `json:"type"`
+	NotNull    bool        `json:"not_null"`
+	Unique     bool        `json:"unique"`
+	PrimaryKey bool        `json:"primary_key"`
+	Default    io.WriterTo `json:"default"`
+}
+
+// WriteTo writes to the provided writer the sql column declaration.
+func (s SQLColumnDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("sqlColumnDeclr", templates.Must("sql-column.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	def, err := writerToString(s.Default)
+	if err != nil {
+		return 0, err
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name    string
+		Type    string
+		NotNull bool
+		Unique  bool
+		Default string
+	}{
+		Name:    s.Name,
+		Type:    s.Type,
+		NotNull: s.NotNull,
+		Unique:  s.Unique,
+		Default: def,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// SQLTableDeclr defines a declaration for a sql CREATE TABLE statement. The primary
+// key constraint is built from the columns marked as PrimaryKey.
+type SQLTableDeclr struct {
+	Name        string           `json:"name"`
+	IfNotExists bool             `json:"if_not_exists"`
+	Comments    io.WriterTo      `json:"comments"`
+	Columns     []SQLColumnDeclr `json:"columns"`
+	Constraints []string         `json:"constraints"`
+}
+
+// WriteTo writes to the provided writer the sql table declaration.
+func (s SQLTableDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("sqlTableDeclr", templates.Must("sql-table.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(s.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	var lines, keys []string
+
+	for _, column := range s.Columns {
+		content, err := renderNestedOr(w, column, "  ")
+		if err != nil {
+			return 0, err
+		}
+
+		if column.PrimaryKey {
+			keys = append(keys, column.Name)
+		}
+
+		lines = append(lines, content)
+	}
+
+	constraints := s.Constraints
+	if len(keys) != 0 {
+		constraints = append([]string{fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(keys, ", "))}, constraints...)
+	}
+
+	for _, constraint := range constraints {
+		content, err := renderNestedOr(w, Text(constraint), "  ")
+		if err != nil {
+			return 0, err
+		}
+
+		lines = append(lines, content)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name        string
+		IfNotExists bool
+		Comments    string
+		Lines       []string
+	}{
+		Name:        s.Name,
+		IfNotExists: s.IfNotExists,
+		Comments:    strings.TrimRight(comments, "\n"),
+		Lines:       lines,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
@@ -109,6 +109,15 @@ var (
 		"indent": func(b string) string {
 			return strings.Join(strings.Split(b, "\n"), "\n\t")
 		},
+		"indentWith": func(b string, unit string, depth int) string {
+			lines := strings.Split(b, "\n")
+			for index, line := range lines {
+				if line != "" {
+					lines[index] = strings.Repeat(unit, depth) + line
+				}
+			}
+			return strings.Join(lines, "\n")
+		},
 		"lessThanEqual": func(b, a int) bool {
 			return b <= a
 		},
@@ -31,16 +31,14 @@ func Get(file string) (string, bool) {
 func init() {
 	internalFiles["name.tml"] = "{{.Name}}"
 	internalFiles["import.tml"] = "import ({{ range .Imports}}\n    {{.}}\n{{end}})\n\n"
-	internalFiles["json.tml"] = "{\n{{ $len := subtract (len .Documents) 1 }}\n{{ range $ind, $item := .Documents }}\n    {{ if lessThan $ind $len}}{{$item}},{{else}}{{$item}}{{end}}\n{{ end }}\n}"
 	internalFiles["typename.tml"] = "{{.Type}}"
 	internalFiles["var-variable-type.tml"] = "var {{.Name}} {{.Type}}\n"
 	internalFiles["variable-assign-basic.tml"] = "var {{.Name}} = {{.Value}}\n"
 	internalFiles["variable-type-only.tml"] = "{{.Type}}"
-	internalFiles["case.tml"] = "case {{.Condition}}:\n    {{.Action}}\n\n\n "
+	internalFiles["case.tml"] = "case {{.Condition}}:\n{{.Action}}\n\n\n "
 	internalFiles["comments.tml"] = "// {{.MainBlock}}\n// {{ range .Blocks}}\n// {{.}}\n// {{end}}\n//\n"
 	internalFiles["function-type.tml"] = "func {{.Name}}{{.Constructor}} {{.Returns}}"
-	internalFiles["jsonblock.tml"] = "{\n{{ $len := subtract (len .) 1 }}\n{{ range $ind, $item := . }}\n    {{ if lessThan $ind $len}}{{$item}},{{else}}{{$item}}{{end}}\n{{ end }}\n}"
-	internalFiles["struct.tml"] = "{{.Comments}}\n{{.Annotations}}\ntype {{.Name}} {{.Type}} {\n{{ range .Fields }}\n    {{.}} \n{{ end }}\n}"
+	internalFiles["struct.tml"] = "{{.Comments}}\n{{.Annotations}}\ntype {{.Name}} {{.Type}} {\n{{ range .Fields }}\n{{.}} \n{{ end }}\n}"
 	internalFiles["value.tml"] = "{{.Value}}"
 	internalFiles["variable-assign.tml"] = "{{.Name}}:={{.Value}}\n"
 	internalFiles["map.tml"] = "{{.MapType}}[{{.Type}}]{{.Value}}{\n    {{ range $k, $v :=  .Values }}\n        {{quote $k}}: {{$v}},\n    {{ end }}\n}"
@@ -51,7 +49,7 @@ func init() {
 	internalFiles["function.tml"] = "\nfunc {{.Name}}{{.Constructor}} {{.Returns}} {\n{{.Body}}\n}\n"
 	internalFiles["if.tml"] = "if{{.Condition}}{\n{{.Action}}\n}"
 	internalFiles["annotations.tml"] = "//@{{.Value}}"
-	internalFiles["case-default.tml"] = "default:\n    {{.Action}}\n\n\n "
+	internalFiles["case-default.tml"] = "default:\n{{.Action}}\n\n\n "
 	internalFiles["import-item.tml"] = "{{.Namespace}} \"{{.Path}}\"\n"
 	internalFiles["map-header.tml"] = "{{.MapType}}[{{.Type}}]{{.ValueType}}"
 	internalFiles["slicevalue.tml"] = "[]{{.Type}}{ {{.Values}} }"
@@ -60,7 +58,33 @@ func init() {
 	internalFiles["variable-name.tml"] = "{{.Name}}"
 	internalFiles["multicomments.tml"] = "/* {{.MainBlock}}\n{{ range .Blocks}}\n* {{.}}\n{{end}}\n*/\n"
 	internalFiles["slicetype.tml"] = "[]{{.Type}}"
-	internalFiles["switch.tml"] = "switch {{.Condition}} {\n{{.Case }}\n{{.Default }}\n}"
+	internalFiles["switch.tml"] = "switch {{.Condition}} {\n{{.Cases }}\n{{.Default }}\n}"
 	internalFiles["text.tml"] = "{{.Block}}"
+	internalFiles["interface.tml"] = "{{if .Comments}}{{.Comments}}\n{{end}}{{if .Annotations}}{{.Annotations}}\n{{end}}type {{.Name}}{{.TypeParams}} interface {\n{{- range .Elements}}\n{{.}}\n{{- end}}\n}"
+	internalFiles["method-signature.tml"] = "{{.Name}}({{join .Params \", \"}}){{if .Results}} {{.Results}}{{end}}"
+	internalFiles["type-params.tml"] = "{{if .Params}}[{{join .Params \", \"}}]{{end}}"
+	internalFiles["type-param.tml"] = "{{.Name}} {{.Constraint}}"
+	internalFiles["type-term.tml"] = "{{if .Tilde}}~{{end}}{{.Type}}"
+	internalFiles["type-set.tml"] = "{{join .Terms \" | \"}}"
+	internalFiles["type-alias.tml"] = "{{if .Comments}}{{.Comments}}\n{{end}}{{if .Annotations}}{{.Annotations}}\n{{end}}type {{.Name}} = {{.Type}}"
+	internalFiles["defined-type.tml"] = "{{if .Comments}}{{.Comments}}\n{{end}}{{if .Annotations}}{{.Annotations}}\n{{end}}type {{.Name}}{{.TypeParams}} {{.Type}}"
+	internalFiles["const-block.tml"] = "{{if .Comments}}{{.Comments}}\n{{end}}const (\n{{- range .Values}}\n{{.}}\n{{- end}}\n)"
+	internalFiles["const-value.tml"] = "{{.Name}}{{if .Type}} {{.Type}}{{end}}{{if .Value}} = {{.Value}}{{end}}{{if .Comment}} // {{.Comment}}{{end}}"
+	internalFiles["param.tml"] = "{{if .Name}}{{.Name}} {{end}}{{if .Variadic}}...{{end}}{{.Type}}"
+	internalFiles["ts-field.tml"] = "{{if .Readonly}}readonly {{end}}{{.Name}}{{if .Optional}}?{{end}}: {{.Type}};"
+	internalFiles["ts-interface.tml"] = "{{if .Comments}}{{.Comments}}\n{{end}}{{if .Exported}}export {{end}}interface {{.Name}}{{if .Extends}} extends {{join .Extends \", \"}}{{end}} {\n{{- range .Fields}}\n{{.}}\n{{- end}}\n}"
+	internalFiles["ts-enum-member.tml"] = "{{.Name}}{{if .Value}} = {{.Value}}{{end}},"
+	internalFiles["ts-enum.tml"] = "{{if .Comments}}{{.Comments}}\n{{end}}{{if .Exported}}export {{end}}{{if .Const}}const {{end}}enum {{.Name}} {\n{{- range .Members}}\n{{.}}\n{{- end}}\n}"
+	internalFiles["proto-field.tml"] = "{{if .Repeated}}repeated {{else if .Optional}}optional {{end}}{{.Type}} {{.Name}} = {{.Number}};"
+	internalFiles["proto-message.tml"] = "{{if .Comments}}{{.Comments}}\n{{end}}message {{.Name}} {\n{{- range .Fields}}\n{{.}}\n{{- end}}\n}"
+	internalFiles["proto-rpc.tml"] = "rpc {{.Name}}({{if .ClientStream}}stream {{end}}{{.Request}}) returns ({{if .ServerStream}}stream {{end}}{{.Response}});"
+	internalFiles["proto-service.tml"] = "{{if .Comments}}{{.Comments}}\n{{end}}service {{.Name}} {\n{{- range .Methods}}\n{{.}}\n{{- end}}\n}"
+	internalFiles["proto-file.tml"] = "syntax = \"{{.Syntax}}\";\n{{if .Package}}\npackage {{.Package}};\n{{end}}{{if .Imports}}\n{{range .Imports}}import \"{{.}}\";\n{{end}}{{end}}{{if .Options}}\n{{range .Options}}option {{.}};\n{{end}}{{end}}{{range .Elements}}\n{{.}}\n{{end}}"
+	internalFiles["sql-column.tml"] = "{{.Name}} {{.Type}}{{if .NotNull}} NOT NULL{{end}}{{if .Unique}} UNIQUE{{end}}{{if .Default}} DEFAULT {{.Default}}{{end}}"
+	internalFiles["sql-table.tml"] = "{{if .Comments}}{{.Comments}}\n{{end}}CREATE TABLE {{if .IfNotExists}}IF NOT EXISTS {{end}}{{.Name}} (\n{{join .Lines \",\\n\"}}\n);"
+	internalFiles["enum.tml"] = "{{if .Comments}}{{.Comments}}\n{{end}}var _{{.Name}}Values = []{{.Name}}{\n{{- range .Values}}\n\t{{.Constant}},\n{{- end}}\n}\n\nvar _{{.Name}}Names = map[string]{{.Name}}{\n{{- range .Values}}\n\t{{.Key}}: {{.Constant}},\n{{- end}}\n}\n\n// String returns the name of the {{.Name}} value.\nfunc ({{.Receiver}} {{.Name}}) String() string {\n\tswitch {{.Receiver}} {\n{{- range .Values}}\n\tcase {{.Constant}}:\n\t\treturn {{.Text}}\n{{- end}}\n\t}\n\n\treturn fmt.Sprintf(\"{{.Name}}(%v)\", {{.Underlying}}({{.Receiver}}))\n}\n\n// Parse{{.Name}} returns the {{.Name}} value with the giving name{{if .IgnoreCase}}, ignoring case{{end}}.\nfunc Parse{{.Name}}(name string) ({{.Name}}, error) {\n\tif value, ok := _{{.Name}}Names[{{if .IgnoreCase}}strings.ToLower(name){{else}}name{{end}}]; ok {\n\t\treturn value, nil\n\t}\n\n\tvar zero {{.Name}}\n\treturn zero, fmt.Errorf(\"%q is not a valid {{.Name}}\", name)\n}\n\n// Values returns all {{.Name}} values in declaration order.\nfunc ({{.Name}}) Values() []{{.Name}} {\n\treturn append([]{{.Name}}(nil), _{{.Name}}Values...)\n}\n\n// IsValid returns true/false if the value is one of the declared {{.Name}} values.\nfunc ({{.Receiver}} {{.Name}}) IsValid() bool {\n\tswitch {{.Receiver}} {\n\tcase {{join .Constants \", \"}}:\n\t\treturn true\n\t}\n\n\treturn false\n}\n\n// MarshalText implements encoding.TextMarshaler by writing the name of the value.\nfunc ({{.Receiver}} {{.Name}}) MarshalText() ([]byte, error) {\n\tif !{{.Receiver}}.IsValid() {\n\t\treturn nil, fmt.Errorf(\"%v is not a valid {{.Name}}\", {{.Underlying}}({{.Receiver}}))\n\t}\n\n\treturn []byte({{.Receiver}}.String()), nil\n}\n\n// UnmarshalText implements encoding.TextUnmarshaler by parsing the value from its name.\nfunc ({{.Receiver}} *{{.Name}}) UnmarshalText(text []byte) error {\n\tvalue, err := Parse{{.Name}}(string(text))\n\tif err != nil {\n\t\treturn err\n\t}\n\n\t*{{.Receiver}} = value\n\treturn nil\n}"
+	internalFiles["mock.tml"] = "{{if .Comments}}{{.Comments}}\n{{end}}type {{.Name}} struct {\n{{- range .Methods}}\n\t{{.Name}}Func {{.FuncType}}\n{{- end}}\n\n\tml    sync.Mutex\n\tcalls struct {\n{{- range .Methods}}\n\t\t{{.Name}} []{{.CallType}}\n{{- end}}\n\t}\n}\n\nvar _ {{.Interface}} = (*{{.Name}})(nil)\n{{range .Methods}}\n// {{.CallType}} defines the arguments of a call to {{$.Name}}.{{.Name}}.\ntype {{.CallType}} struct {\n{{- range .Fields}}\n\t{{.Name}} {{.Type}}\n{{- end}}\n}\n\n// {{.Name}} records the call and calls {{.Name}}Func if set, else returning zero values.\nfunc ({{$.Receiver}} *{{$.Name}}) {{.Name}}({{.Params}}){{.Results}} {\n\t{{$.Receiver}}.ml.Lock()\n\t{{$.Receiver}}.calls.{{.Name}} = append({{$.Receiver}}.calls.{{.Name}}, {{.CallType}}{ {{- join .Assigns \", \" -}} })\n\tfn := {{$.Receiver}}.{{.Name}}Func\n\t{{$.Receiver}}.ml.Unlock()\n\n\tif fn == nil {\n\t\treturn\n\t}\n\n\t{{if .Results}}return {{end}}fn({{.Forward}})\n}\n\n// {{.Name}}Calls returns the calls made to {{.Name}} in order.\nfunc ({{$.Receiver}} *{{$.Name}}) {{.Name}}Calls() []{{.CallType}} {\n\t{{$.Receiver}}.ml.Lock()\n\tdefer {{$.Receiver}}.ml.Unlock()\n\n\treturn append([]{{.CallType}}(nil), {{$.Receiver}}.calls.{{.Name}}...)\n}\n\n// {{.Name}}Count returns the number of calls made to {{.Name}}.\nfunc ({{$.Receiver}} *{{$.Name}}) {{.Name}}Count() int {\n\t{{$.Receiver}}.ml.Lock()\n\tdefer {{$.Receiver}}.ml.Unlock()\n\n\treturn len({{$.Receiver}}.calls.{{.Name}})\n}\n{{end}}"
+	internalFiles["validate.tml"] = "{{range .Patterns}}var {{.Name}} = regexp.MustCompile({{.Pattern}})\n{{end}}{{if .Patterns}}\n{{end}}{{if .Comments}}{{.Comments}}\n{{end}}func ({{.Receiver}} {{.Name}}) Validate() error {\n\tvar errs []error\n{{- if .Nested}}\n\n\tnested := func(path string, err error) {\n\t\tif joined, ok := err.(interface{ Unwrap() []error }); ok {\n\t\t\tfor _, err := range joined.Unwrap() {\n\t\t\t\terrs = append(errs, fmt.Errorf(\"%s.%w\", path, err))\n\t\t\t}\n\t\t\treturn\n\t\t}\n\n\t\terrs = append(errs, fmt.Errorf(\"%s: %w\", path, err))\n\t}\n{{- end}}\n{{- range .Statements}}\n\n{{.}}\n{{- end}}\n\n\treturn errors.Join(errs...)\n}"
+	internalFiles["embed.tml"] = "{{if .Comments}}{{.Comments}}\n{{else}}// {{.Name}} serves the embedded files as a fs.FS, along with fs.ReadDirFS, fs.ReadFileFS and fs.StatFS.\n{{end}}var {{.Name}} = {{.Prefix}}FS{}\n\nvar (\n\t_ fs.ReadDirFS  = {{.Prefix}}FS{}\n\t_ fs.ReadFileFS = {{.Prefix}}FS{}\n\t_ fs.StatFS     = {{.Prefix}}FS{}\n)\n\n// {{.Prefix}}Entries holds the embedded files and directories by path.\nvar {{.Prefix}}Entries = map[string]*{{.Prefix}}Entry{\n{{- range .Entries}}\n\t{{.Path}}: {{\"{\"}}{{.Fields}}},\n{{- end}}\n}\n\n// {{.Prefix}}FS implements fs.FS over {{.Prefix}}Entries.\ntype {{.Prefix}}FS struct{}\n\n// Open implements fs.FS, opening the file or directory with the giving slash separated path.\nfunc ({{.Prefix}}FS) Open(name string) (fs.File, error) {\n\tentry, err := {{.Prefix}}Lookup(\"open\", name)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\n\tif entry.entries != nil {\n\t\treturn &{{.Prefix}}Dir{entry: entry, path: name}, nil\n\t}\n\n\treader, err := entry.reader()\n\tif err != nil {\n\t\treturn nil, &fs.PathError{Op: \"open\", Path: name, Err: err}\n\t}\n\n\treturn &{{.Prefix}}File{entry: entry, reader: reader}, nil\n}\n\n// ReadDir implements fs.ReadDirFS, returning the entries of the directory sorted by name.\nfunc ({{.Prefix}}FS) ReadDir(name string) ([]fs.DirEntry, error) {\n\tentry, err := {{.Prefix}}Lookup(\"readdir\", name)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\n\tif entry.entries == nil {\n\t\treturn nil, &fs.PathError{Op: \"readdir\", Path: name, Err: errors.New(\"not a directory\")}\n\t}\n\n\treturn entry.dirEntries(name), nil\n}\n\n// ReadFile implements fs.ReadFileFS, returning a copy of the content of the file.\nfunc ({{.Prefix}}FS) ReadFile(name string) ([]byte, error) {\n\tentry, err := {{.Prefix}}Lookup(\"readfile\", name)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\n\tif entry.entries != nil {\n\t\treturn nil, &fs.PathError{Op: \"readfile\", Path: name, Err: errors.New(\"is a directory\")}\n\t}\n\n\tif !entry.gzipped {\n\t\treturn []byte(entry.data), nil\n\t}\n\n\tdata, err := entry.content()\n\tif err != nil {\n\t\treturn nil, &fs.PathError{Op: \"readfile\", Path: name, Err: err}\n\t}\n\n\treturn append([]byte(nil), data...), nil\n}\n\n// Stat implements fs.StatFS.\nfunc ({{.Prefix}}FS) Stat(name string) (fs.FileInfo, error) {\n\tentry, err := {{.Prefix}}Lookup(\"stat\", name)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\n\treturn {{.Prefix}}Info{entry}, nil\n}\n\n// Compressed returns the gzip compressed content of the file, if it is embedded compressed, which\n// can be served as is to clients accepting gzip.\nfunc ({{.Prefix}}FS) Compressed(name string) ([]byte, bool) {\n\tentry, err := {{.Prefix}}Lookup(\"compressed\", name)\n\tif err != nil || !entry.gzipped {\n\t\treturn nil, false\n\t}\n\n\treturn []byte(entry.data), true\n}\n{{if .Hashes}}\n// Hash returns the hex encoded SHA-256 hash of the content of the file.\nfunc ({{.Prefix}}FS) Hash(name string) (string, error) {\n\tentry, err := {{.Prefix}}Lookup(\"hash\", name)\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\n\tif entry.entries != nil {\n\t\treturn \"\", &fs.PathError{Op: \"hash\", Path: name, Err: errors.New(\"is a directory\")}\n\t}\n\n\treturn entry.hash, nil\n}\n\n// ETag returns the strong HTTP entity tag of the file, derived from the hash of its content.\nfunc (efs {{.Prefix}}FS) ETag(name string) (string, error) {\n\thash, err := efs.Hash(name)\n\tif err != nil {\n\t\treturn \"\", err\n\t}\n\n\treturn `\"` + hash + `\"`, nil\n}\n{{end}}\n// {{.Prefix}}Lookup returns the entry with the giving path.\nfunc {{.Prefix}}Lookup(op string, name string) (*{{.Prefix}}Entry, error) {\n\tif !fs.ValidPath(name) {\n\t\treturn nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}\n\t}\n\n\tentry, ok := {{.Prefix}}Entries[name]\n\tif !ok {\n\t\treturn nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}\n\t}\n\n\treturn entry, nil\n}\n\n// {{.Prefix}}Entry defines an embedded file, or a directory with the names of its entries, where\n// gzipped content is decompressed once when first read.\ntype {{.Prefix}}Entry struct {\n\tname    string\n\tmode    fs.FileMode\n\tmodTime time.Time\n\tsize    int64\n\thash    string\n\tgzipped bool\n\tdata    string\n\tentries []string\n\n\tonce sync.Once\n\traw  []byte\n\terr  error\n}\n\n// content returns the decompressed content of a gzipped file.\nfunc (entry *{{.Prefix}}Entry) content() ([]byte, error) {\n\tentry.once.Do(func() {\n\t\treader, err := gzip.NewReader(strings.NewReader(entry.data))\n\t\tif err != nil {\n\t\t\tentry.err = err\n\t\t\treturn\n\t\t}\n\n\t\tentry.raw, entry.err = io.ReadAll(reader)\n\t})\n\n\treturn entry.raw, entry.err\n}\n\n// reader returns a reader of the content of the file.\nfunc (entry *{{.Prefix}}Entry) reader() ({{.Prefix}}Reader, error) {\n\tif !entry.gzipped {\n\t\treturn strings.NewReader(entry.data), nil\n\t}\n\n\tdata, err := entry.content()\n\tif err != nil {\n\t\treturn nil, err\n\t}\n\n\treturn bytes.NewReader(data), nil\n}\n\n// dirEntries returns the entries of the directory at the giving path.\nfunc (entry *{{.Prefix}}Entry) dirEntries(dir string) []fs.DirEntry {\n\tentries := make([]fs.DirEntry, 0, len(entry.entries))\n\tfor _, name := range entry.entries {\n\t\tentries = append(entries, fs.FileInfoToDirEntry({{.Prefix}}Info{ {{- .Prefix}}Entries[path.Join(dir, name)]}))\n\t}\n\n\treturn entries\n}\n\n// {{.Prefix}}Reader defines the reader of the content of an opened file.\ntype {{.Prefix}}Reader interface {\n\tio.Reader\n\tio.Seeker\n\tio.ReaderAt\n}\n\n// {{.Prefix}}Info implements fs.FileInfo for an embedded entry.\ntype {{.Prefix}}Info struct {\n\tentry *{{.Prefix}}Entry\n}\n\nfunc (info {{.Prefix}}Info) Name() string       { return info.entry.name }\nfunc (info {{.Prefix}}Info) Size() int64        { return info.entry.size }\nfunc (info {{.Prefix}}Info) Mode() fs.FileMode  { return info.entry.mode }\nfunc (info {{.Prefix}}Info) ModTime() time.Time { return info.entry.modTime }\nfunc (info {{.Prefix}}Info) IsDir() bool        { return info.entry.mode.IsDir() }\nfunc (info {{.Prefix}}Info) Sys() interface{}   { return nil }\n\n// {{.Prefix}}File implements fs.File for an opened file, along with io.Seeker and io.ReaderAt as\n// required by http.FileServer.\ntype {{.Prefix}}File struct {\n\tentry  *{{.Prefix}}Entry\n\treader {{.Prefix}}Reader\n}\n\nfunc (file *{{.Prefix}}File) Stat() (fs.FileInfo, error) { return {{.Prefix}}Info{file.entry}, nil }\nfunc (file *{{.Prefix}}File) Read(p []byte) (int, error) { return file.reader.Read(p) }\nfunc (file *{{.Prefix}}File) Close() error               { return nil }\n\nfunc (file *{{.Prefix}}File) Seek(offset int64, whence int) (int64, error) {\n\treturn file.reader.Seek(offset, whence)\n}\n\nfunc (file *{{.Prefix}}File) ReadAt(p []byte, offset int64) (int, error) {\n\treturn file.reader.ReadAt(p, offset)\n}\n\n// {{.Prefix}}Dir implements fs.ReadDirFile for an opened directory.\ntype {{.Prefix}}Dir struct {\n\tentry  *{{.Prefix}}Entry\n\tpath   string\n\toffset int\n}\n\nfunc (dir *{{.Prefix}}Dir) Stat() (fs.FileInfo, error) { return {{.Prefix}}Info{dir.entry}, nil }\nfunc (dir *{{.Prefix}}Dir) Close() error               { return nil }\n\nfunc (dir *{{.Prefix}}Dir) Read(p []byte) (int, error) {\n\treturn 0, &fs.PathError{Op: \"read\", Path: dir.path, Err: errors.New(\"is a directory\")}\n}\n\n// ReadDir returns the next n entries of the directory, or all remaining entries if n <= 0.\nfunc (dir *{{.Prefix}}Dir) ReadDir(n int) ([]fs.DirEntry, error) {\n\tentries := dir.entry.dirEntries(dir.path)[dir.offset:]\n\n\tif n <= 0 {\n\t\tdir.offset += len(entries)\n\t\treturn entries, nil\n\t}\n\n\tif len(entries) == 0 {\n\t\treturn nil, io.EOF\n\t}\n\n\tif n > len(entries) {\n\t\tn = len(entries)\n\t}\n\n\tdir.offset += n\n\treturn entries[:n], nil\n}\n"
 
 }
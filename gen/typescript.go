@@ -0,0 +1,224 @@
+package gen
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/influx6/moz/gen/templates"
+)
+
+//======================================================================================================================
+
+// TSFieldDeclr defines a declaration for a property of a typescript interface.
+type TSFieldDeclr struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Optional bool   `json:"optional"`
+	Readonly bool   `json:"readonly"`
+}
+
+// WriteTo writes to the provided writer the typescript property declaration.
+func (t TSFieldDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("tsFieldDeclr", templates.Must("ts-field.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	name := t.Name
+	if !isTSIdentifier(name) {
+		name = strconv.Quote(name)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name     string
+		Type     string
+		Optional bool
+		Readonly bool
+	}{
+		Name:     name,
+		Type:     t.Type,
+		Optional: t.Optional,
+		Readonly: t.Readonly,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// TSInterfaceDeclr defines a declaration for a typescript interface.
+type TSInterfaceDeclr struct {
+	Name     NameDeclr      `json:"name"`
+	Exported bool           `json:"exported"`
+	Extends  []string       `json:"extends"`
+	Comments io.WriterTo    `json:"comments"`
+	Fields   []TSFieldDeclr `json:"fields"`
+}
+
+// WriteTo writes to the provided writer the typescript interface declaration.
+func (t TSInterfaceDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("tsInterfaceDeclr", templates.Must("ts-interface.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(t.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	var fields []string
+	for _, field := range t.Fields {
+		content, err := renderNestedOr(w, field, "  ")
+		if err != nil {
+			return 0, err
+		}
+
+		fields = append(fields, content)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name     string
+		Exported bool
+		Extends  []string
+		Comments string
+		Fields   []string
+	}{
+		Name:     t.Name.String(),
+		Exported: t.Exported,
+		Extends:  t.Extends,
+		Comments: strings.TrimRight(comments, "\n"),
+		Fields:   fields,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// TSEnumMemberDeclr defines a declaration for a member of a typescript enum, where
+// a nil value leaves the member to be numbered by typescript.
+type TSEnumMemberDeclr struct {
+	Name  string      `json:"name"`
+	Value io.WriterTo `json:"value"`
+}
+
+// WriteTo writes to the provided writer the typescript enum member declaration.
+func (t TSEnumMemberDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("tsEnumMemberDeclr", templates.Must("ts-enum-member.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := writerToString(t.Value)
+	if err != nil {
+		return 0, err
+	}
+
+	name := t.Name
+	if !isTSIdentifier(name) {
+		name = strconv.Quote(name)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name  string
+		Value string
+	}{
+		Name:  name,
+		Value: value,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// TSEnumDeclr defines a declaration for a typescript enum.
+type TSEnumDeclr struct {
+	Name     NameDeclr           `json:"name"`
+	Exported bool                `json:"exported"`
+	Const    bool                `json:"const"`
+	Comments io.WriterTo         `json:"comments"`
+	Members  []TSEnumMemberDeclr `json:"members"`
+}
+
+// WriteTo writes to the provided writer the typescript enum declaration.
+func (t TSEnumDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("tsEnumDeclr", templates.Must("ts-enum.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(t.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	var members []string
+	for _, member := range t.Members {
+		content, err := renderNestedOr(w, member, "  ")
+		if err != nil {
+			return 0, err
+		}
+
+		members = append(members, content)
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name     string
+		Exported bool
+		Const    bool
+		Comments string
+		Members  []string
+	}{
+		Name:     t.Name.String(),
+		Exported: t.Exported,
+		Const:    t.Const,
+		Comments: strings.TrimRight(comments, "\n"),
+		Members:  members,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// isTSIdentifier returns true/false if the name can be used unquoted as a typescript
+// property or enum member name.
+func isTSIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for index, r := range name {
+		if r == '_' || r == '$' || unicode.IsLetter(r) {
+			continue
+		}
+
+		if index > 0 && unicode.IsDigit(r) {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,466 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/influx6/moz/gen/templates"
+)
+
+//======================================================================================================================
+
+// ValidateKind defines the kind of value a validated field holds, which decides how its rules
+// are checked.
+type ValidateKind int
+
+// Kinds of values supported by a ValidateFieldDeclr.
+const (
+	// ValidateOther defines a value which supports no rules.
+	ValidateOther ValidateKind = iota
+
+	// ValidateString defines a string value, whose length is checked by min, max and len.
+	ValidateString
+
+	// ValidateInt defines a signed integer value.
+	ValidateInt
+
+	// ValidateUint defines an unsigned integer value.
+	ValidateUint
+
+	// ValidateFloat defines a floating point value.
+	ValidateFloat
+
+	// ValidateBool defines a boolean value, which is only required to be true.
+	ValidateBool
+
+	// ValidateSlice defines a slice value, whose length is checked by min, max and len.
+	ValidateSlice
+
+	// ValidateArray defines an array value, whose length is checked by min, max and len.
+	ValidateArray
+
+	// ValidateMap defines a map value, whose length is checked by min, max and len.
+	ValidateMap
+
+	// ValidateStruct defines a struct value, which is required by its IsZero method.
+	ValidateStruct
+
+	// ValidateNilable defines an interface, channel or function value, which is required to be non-nil.
+	ValidateNilable
+)
+
+// String returns the name of the kind.
+func (vk ValidateKind) String() string {
+	switch vk {
+	case ValidateString:
+		return "string"
+	case ValidateInt:
+		return "int"
+	case ValidateUint:
+		return "uint"
+	case ValidateFloat:
+		return "float"
+	case ValidateBool:
+		return "bool"
+	case ValidateSlice:
+		return "slice"
+	case ValidateArray:
+		return "array"
+	case ValidateMap:
+		return "map"
+	case ValidateStruct:
+		return "struct"
+	case ValidateNilable:
+		return "nilable"
+	}
+	return "other"
+}
+
+// ValidateRuleDeclr defines a rule a field is validated against, where Name is one of min, max,
+// len, regex or oneof and Value its argument as written (e.g min=3). Values of oneof are space
+// separated.
+type ValidateRuleDeclr struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ValidateFieldDeclr defines a field of a validated struct, where Selector accesses the field from
+// the receiver (e.g Address.Street) and Path names it in errors. Pointer fields have their rules
+// checked against the value pointed to when not nil. Nested fields have their Validate method
+// called, or that of every element of a slice, array or map, where ElemPointer marks elements
+// which are pointers.
+type ValidateFieldDeclr struct {
+	Path        string              `json:"path"`
+	Selector    string              `json:"selector"`
+	Kind        ValidateKind        `json:"kind"`
+	Pointer     bool                `json:"pointer"`
+	Required    bool                `json:"required"`
+	Nested      bool                `json:"nested"`
+	ElemPointer bool                `json:"elem_pointer"`
+	Rules       []ValidateRuleDeclr `json:"rules"`
+}
+
+// ValidateCondition defines a Go expression which is true when a field fails a rule, along with
+// the message reported for it.
+type ValidateCondition struct {
+	Condition string
+	Message   string
+}
+
+// Conditions returns the conditions checked for the field of the named struct in order. An error is
+// returned if a rule is unknown, has an invalid value or is not supported by the kind of the field.
+func (v ValidateFieldDeclr) Conditions(typeName string) ([]ValidateCondition, error) {
+	selector := validateReceiver(typeName) + "." + v.Selector
+
+	value := selector
+	if v.Pointer {
+		value = "*" + selector
+	}
+
+	var conditions []ValidateCondition
+
+	if v.Required {
+		var condition string
+
+		switch {
+		case v.Pointer:
+			condition = selector + " == nil"
+		case v.Kind == ValidateString:
+			condition = value + ` == ""`
+		case v.Kind == ValidateInt, v.Kind == ValidateUint, v.Kind == ValidateFloat:
+			condition = value + " == 0"
+		case v.Kind == ValidateBool:
+			condition = "!" + value
+		case v.Kind == ValidateSlice, v.Kind == ValidateMap:
+			condition = "len(" + value + ") == 0"
+		case v.Kind == ValidateNilable:
+			condition = value + " == nil"
+		case v.Kind == ValidateStruct:
+			condition = value + ".IsZero()"
+		default:
+			return nil, fmt.Errorf("Field %q of kind %s does not support required", v.Path, v.Kind)
+		}
+
+		conditions = append(conditions, ValidateCondition{Condition: condition, Message: "is required"})
+	}
+
+	guard := ""
+	if v.Pointer {
+		guard = selector + " != nil && "
+	}
+
+	var patterns int
+	for _, rule := range v.Rules {
+		if rule.Name == "regex" {
+			if patterns++; patterns > 1 {
+				return nil, fmt.Errorf("Field %q can only have a single regex", v.Path)
+			}
+		}
+
+		condition, message, err := v.ruleCondition(typeName, rule, value)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, ValidateCondition{Condition: guard + condition, Message: message})
+	}
+
+	return conditions, nil
+}
+
+// ruleCondition returns the condition and message of a single rule checked against the value.
+func (v ValidateFieldDeclr) ruleCondition(typeName string, rule ValidateRuleDeclr, value string) (string, string, error) {
+	sized := v.Kind == ValidateString || v.Kind == ValidateSlice || v.Kind == ValidateArray || v.Kind == ValidateMap
+	numeric := v.Kind == ValidateInt || v.Kind == ValidateUint || v.Kind == ValidateFloat
+
+	switch rule.Name {
+	case "min", "max":
+		op, bound := "<", "at least"
+		if rule.Name == "max" {
+			op, bound = ">", "at most"
+		}
+
+		switch {
+		case sized:
+			if _, err := strconv.ParseUint(rule.Value, 10, 0); err != nil {
+				return "", "", fmt.Errorf("Field %q has invalid length %q for %s", v.Path, rule.Value, rule.Name)
+			}
+			return fmt.Sprintf("len(%s) %s %s", value, op, rule.Value), fmt.Sprintf("must have a length of %s %s", bound, rule.Value), nil
+		case numeric:
+			if err := v.checkNumber(rule); err != nil {
+				return "", "", err
+			}
+			return fmt.Sprintf("%s %s %s", value, op, rule.Value), fmt.Sprintf("must be %s %s", bound, rule.Value), nil
+		}
+
+	case "len":
+		if sized {
+			if _, err := strconv.ParseUint(rule.Value, 10, 0); err != nil {
+				return "", "", fmt.Errorf("Field %q has invalid length %q for len", v.Path, rule.Value)
+			}
+			return fmt.Sprintf("len(%s) != %s", value, rule.Value), fmt.Sprintf("must have a length of %s", rule.Value), nil
+		}
+
+	case "regex":
+		if v.Kind == ValidateString {
+			if _, err := regexp.Compile(rule.Value); err != nil {
+				return "", "", fmt.Errorf("Field %q has invalid regex %q: %s", v.Path, rule.Value, err)
+			}
+			return fmt.Sprintf("!%s.MatchString(%s)", v.patternName(typeName), value), fmt.Sprintf("must match %s", rule.Value), nil
+		}
+
+	case "oneof":
+		options := strings.Fields(rule.Value)
+		if len(options) == 0 {
+			return "", "", fmt.Errorf("Field %q has no values for oneof", v.Path)
+		}
+
+		if v.Kind != ValidateString && !numeric {
+			break
+		}
+
+		var checks []string
+		for _, option := range options {
+			literal := option
+			if v.Kind == ValidateString {
+				literal = strconv.Quote(option)
+			} else if err := v.checkNumber(ValidateRuleDeclr{Name: rule.Name, Value: option}); err != nil {
+				return "", "", err
+			}
+
+			checks = append(checks, value+" != "+literal)
+		}
+
+		return strings.Join(checks, " && "), "must be one of " + strings.Join(options, ", "), nil
+
+	default:
+		return "", "", fmt.Errorf("Field %q has unknown rule %q", v.Path, rule.Name)
+	}
+
+	return "", "", fmt.Errorf("Field %q of kind %s does not support %s", v.Path, v.Kind, rule.Name)
+}
+
+// checkNumber returns an error if the value of the rule is not a number the field can hold.
+func (v ValidateFieldDeclr) checkNumber(rule ValidateRuleDeclr) error {
+	var err error
+
+	switch v.Kind {
+	case ValidateInt:
+		_, err = strconv.ParseInt(rule.Value, 10, 64)
+	case ValidateUint:
+		_, err = strconv.ParseUint(rule.Value, 10, 64)
+	default:
+		_, err = strconv.ParseFloat(rule.Value, 64)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Field %q of kind %s has invalid number %q for %s", v.Path, v.Kind, rule.Value, rule.Name)
+	}
+
+	return nil
+}
+
+// patternName returns the name of the package variable holding the compiled regex of the field of
+// the named struct.
+func (v ValidateFieldDeclr) patternName(typeName string) string {
+	name := "_" + typeName
+	for _, part := range strings.Split(v.Selector, ".") {
+		name += exportedName(part)
+	}
+	return name + "Pattern"
+}
+
+// validateReceiver returns the name of the receiver of the Validate method of the named struct.
+func validateReceiver(typeName string) string {
+	first, _ := utf8.DecodeRuneInString(typeName)
+	return string(unicode.ToLower(first))
+}
+
+// ValidateDeclr defines a declaration for a Validate method of a struct, which checks the rules of
+// every field and returns nil or an error joining the failures with errors.Join, each prefixed with
+// the path of the field failing it (e.g "Address.Street: is required"). Failures of nested Validate
+// methods which join several errors are prefixed individually. The generated source requires the
+// "errors" package, "fmt" if any field is Nested and "regexp" if any rule is a regex.
+type ValidateDeclr struct {
+	Name     NameDeclr            `json:"name"`
+	Comments io.WriterTo          `json:"comments"`
+	Fields   []ValidateFieldDeclr `json:"fields"`
+}
+
+// Imports returns the import paths required by the generated source.
+func (v ValidateDeclr) Imports() []string {
+	imports := []string{"errors"}
+
+	var nested, pattern bool
+	for _, field := range v.Fields {
+		nested = nested || field.Nested
+
+		for _, rule := range field.Rules {
+			pattern = pattern || rule.Name == "regex"
+		}
+	}
+
+	if nested {
+		imports = append(imports, "fmt")
+	}
+
+	if pattern {
+		imports = append(imports, "regexp")
+	}
+
+	return imports
+}
+
+// validatePattern defines a regex compiled into a package variable.
+type validatePattern struct {
+	Name    string
+	Pattern string
+}
+
+// WriteTo writes to the provided writer the validate declaration.
+func (v ValidateDeclr) WriteTo(w io.Writer) (int64, error) {
+	w = NewNoBOM(w)
+
+	tml, err := ToTemplate("validateDeclr", templates.Must("validate.tml"), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	comments, err := writerToString(v.Comments)
+	if err != nil {
+		return 0, err
+	}
+
+	name := v.Name.String()
+
+	var patterns []validatePattern
+	var statements []string
+	var nested bool
+
+	for _, field := range v.Fields {
+		for _, rule := range field.Rules {
+			if rule.Name == "regex" {
+				patterns = append(patterns, validatePattern{Name: field.patternName(name), Pattern: strconv.Quote(rule.Value)})
+			}
+		}
+
+		statement, err := validateStatement(field, name)
+		if err != nil {
+			return 0, err
+		}
+
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+
+		nested = nested || field.Nested
+	}
+
+	wc := NewWriteCounter(w)
+
+	if err := tml.Execute(wc, struct {
+		Name       string
+		Receiver   string
+		Comments   string
+		Nested     bool
+		Patterns   []validatePattern
+		Statements []string
+	}{
+		Name:       name,
+		Receiver:   validateReceiver(name),
+		Comments:   strings.TrimRight(comments, "\n"),
+		Nested:     nested,
+		Patterns:   patterns,
+		Statements: statements,
+	}); err != nil {
+		return 0, err
+	}
+
+	return wc.Written(), nil
+}
+
+// validateStatement returns the statements checking the field, indented for the body of the
+// Validate method. Rules of a field are chained so only the first failing one is reported.
+func validateStatement(field ValidateFieldDeclr, typeName string) (string, error) {
+	conditions, err := field.Conditions(typeName)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+
+	for index, condition := range conditions {
+		if index == 0 {
+			out.WriteString("\tif ")
+		} else {
+			out.WriteString(" else if ")
+		}
+
+		fmt.Fprintf(&out, "%s {\n\t\terrs = append(errs, errors.New(%s))\n\t}", condition.Condition, strconv.Quote(field.Path+": "+condition.Message))
+	}
+
+	if !field.Nested {
+		return out.String(), nil
+	}
+
+	if out.Len() != 0 {
+		out.WriteString("\n\n")
+	}
+
+	selector := validateReceiver(typeName) + "." + field.Selector
+	path := strconv.Quote(field.Path)
+
+	switch field.Kind {
+	case ValidateSlice, ValidateArray, ValidateMap:
+		value := selector
+		if field.Pointer {
+			value = "*" + selector
+			fmt.Fprintf(&out, "\tif %s != nil {\n", selector)
+		}
+
+		var loop bytes.Buffer
+
+		index, format := "index", "%d"
+		if field.Kind == ValidateMap {
+			index, format = "key", "%v"
+		}
+
+		fmt.Fprintf(&loop, "for %s, item := range %s {\n", index, value)
+		if field.ElemPointer {
+			loop.WriteString("\tif item == nil {\n\t\tcontinue\n\t}\n\n")
+		}
+		fmt.Fprintf(&loop, "\tif err := item.Validate(); err != nil {\n\t\tnested(fmt.Sprintf(%s, %s), err)\n\t}\n}", strconv.Quote(field.Path+"["+format+"]"), index)
+
+		indent := "\t"
+		if field.Pointer {
+			indent = "\t\t"
+		}
+
+		for _, line := range strings.Split(loop.String(), "\n") {
+			if line != "" {
+				out.WriteString(indent)
+			}
+			out.WriteString(line + "\n")
+		}
+
+		out.Truncate(out.Len() - 1)
+
+		if field.Pointer {
+			out.WriteString("\n\t}")
+		}
+	default:
+		if field.Pointer {
+			fmt.Fprintf(&out, "\tif %s != nil {\n\t\tif err := %s.Validate(); err != nil {\n\t\t\tnested(%s, err)\n\t\t}\n\t}", selector, selector, path)
+		} else {
+			fmt.Fprintf(&out, "\tif err := %s.Validate(); err != nil {\n\t\tnested(%s, err)\n\t}", selector, path)
+		}
+	}
+
+	return out.String(), nil
+}
@@ -2,6 +2,7 @@ package gen_test
 
 import (
 	"bytes"
+	"io"
 	"testing"
 
 	"github.com/influx6/faux/tests"
@@ -37,3 +38,41 @@ func TestFromReader(t *testing.T) {
 	}
 	tests.Passed("Should have written content exactly the same as reader.")
 }
+
+// TestIndentWriter validates nested block declarations are indented by their depth.
+func TestIndentWriter(t *testing.T) {
+	expected := "\nfunc main()  {\n  if(ready){\n    switch state {\n    case 1:\n      fire()\n\n\n     \n    default:\n      wait()\n\n\n     \n    }\n  }\n  list := []string{\n    \"a\",\n  }\n}\n"
+
+	src := gen.Indented("  ",
+		gen.Function(
+			gen.Name("main"),
+			gen.Constructor(),
+			gen.Returns(),
+			gen.If(
+				gen.Text("ready"),
+				gen.Switch(
+					gen.Text("state"),
+					gen.DefaultCase(gen.Text("wait()")),
+					gen.Case(gen.Text("1"), gen.Text("fire()")),
+				),
+			),
+			gen.Text("\nlist := []string"),
+			gen.ByteWrapBlock([]byte("{\n"), []byte("\n}"), gen.Text("\"a\",")),
+		),
+	)
+
+	var bu bytes.Buffer
+
+	if _, err := src.WriteTo(&bu); err != nil && err != io.EOF {
+		tests.Failed("Should have successfully written source output: %+q.", err)
+	}
+	tests.Passed("Should have successfully written source output.")
+
+	if bu.String() != expected {
+		tests.Info("Source: %+q", bu.String())
+		tests.Info("Expected: %+q", expected)
+
+		tests.Failed("Should have successfully matched indented output with expected.")
+	}
+	tests.Passed("Should have successfully matched indented output with expected.")
+}
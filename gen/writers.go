@@ -2,7 +2,9 @@ package gen
 
 import (
 	"bufio"
+	"bytes"
 	"io"
+	"strings"
 )
 
 var (
@@ -87,6 +89,98 @@ func (w *WriteCounter) Write(data []byte) (int, error) {
 
 //======================================================================================================================
 
+// IndentWriter defines a io.Writer which indents every line written through it by
+// its current depth, where each level of depth is a single Unit. Block-style declarations
+// written to an IndentWriter push and pop its depth around their content, so nested
+// output is indented correctly for any target language. Empty lines are not indented.
+type IndentWriter struct {
+	io.Writer
+	Unit    string
+	depth   int
+	midLine bool
+}
+
+// NewIndentWriter returns a new instance of the IndentWriter, where an empty unit
+// defaults to a single tab.
+func NewIndentWriter(w io.Writer, unit string) *IndentWriter {
+	if unit == "" {
+		unit = "\t"
+	}
+
+	return &IndentWriter{Writer: w, Unit: unit}
+}
+
+// Push increases the depth of indentation by one unit.
+func (iw *IndentWriter) Push() {
+	iw.depth++
+}
+
+// Pop decreases the depth of indentation by one unit.
+func (iw *IndentWriter) Pop() {
+	if iw.depth > 0 {
+		iw.depth--
+	}
+}
+
+// Depth returns the current depth of indentation.
+func (iw *IndentWriter) Depth() int {
+	return iw.depth
+}
+
+// Write writes the data into the underline writer, indenting the start of every line.
+// The returned count covers only the provided data, not the indentation added.
+func (iw *IndentWriter) Write(data []byte) (int, error) {
+	var written int
+
+	for len(data) > 0 {
+		if !iw.midLine && data[0] != '\n' {
+			if _, err := io.WriteString(iw.Writer, strings.Repeat(iw.Unit, iw.depth)); err != nil {
+				return written, err
+			}
+
+			iw.midLine = true
+		}
+
+		end := len(data)
+		if index := bytes.IndexByte(data, '\n'); index != -1 {
+			end = index + 1
+		}
+
+		n, err := iw.Writer.Write(data[:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+
+		if data[end-1] == '\n' {
+			iw.midLine = false
+		}
+
+		data = data[end:]
+	}
+
+	return written, nil
+}
+
+// IndentWriterFor returns the IndentWriter the provided writer writes into, looking through
+// the NoBOMWriter and WriteCounter wrappers declarations place around their writers.
+func IndentWriterFor(w io.Writer) (*IndentWriter, bool) {
+	for {
+		switch ww := w.(type) {
+		case *IndentWriter:
+			return ww, true
+		case *NoBOMWriter:
+			w = ww.Writer
+		case *WriteCounter:
+			w = ww.Writer
+		default:
+			return nil, false
+		}
+	}
+}
+
+//======================================================================================================================
+
 // IsNoError returns true/false if the error is nil.
 func IsNoError(err error) bool {
 	return err == nil